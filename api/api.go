@@ -0,0 +1,10 @@
+// Package api holds the published API contracts: the OpenAPI document for
+// the REST API and, in balance/v1, the gRPC service.
+package api
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI 3 document describing every REST endpoint.
+//
+//go:embed openapi.json
+var OpenAPI []byte
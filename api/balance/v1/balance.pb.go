@@ -0,0 +1,844 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: balance/v1/balance.proto
+
+package balancev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Error is attached as a status detail to failed calls and carries the same
+// code and message as the REST error body.
+type Error struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Error   string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Every field that failed validation; error and message repeat the first.
+	Details       []*FieldError `protobuf:"bytes,3,rep,name=details,proto3" json:"details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_balance_v1_balance_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_balance_v1_balance_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_balance_v1_balance_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Error) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetDetails() []*FieldError {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+type FieldError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldError) Reset() {
+	*x = FieldError{}
+	mi := &file_balance_v1_balance_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldError) ProtoMessage() {}
+
+func (x *FieldError) ProtoReflect() protoreflect.Message {
+	mi := &file_balance_v1_balance_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldError.ProtoReflect.Descriptor instead.
+func (*FieldError) Descriptor() ([]byte, []int) {
+	return file_balance_v1_balance_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FieldError) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FieldError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *FieldError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint64                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceRequest) Reset() {
+	*x = GetBalanceRequest{}
+	mi := &file_balance_v1_balance_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceRequest) ProtoMessage() {}
+
+func (x *GetBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_balance_v1_balance_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_balance_v1_balance_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetBalanceRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type WalletBalances struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cash          string                 `protobuf:"bytes,1,opt,name=cash,proto3" json:"cash,omitempty"`
+	Bonus         string                 `protobuf:"bytes,2,opt,name=bonus,proto3" json:"bonus,omitempty"`
+	Locked        string                 `protobuf:"bytes,3,opt,name=locked,proto3" json:"locked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletBalances) Reset() {
+	*x = WalletBalances{}
+	mi := &file_balance_v1_balance_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletBalances) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletBalances) ProtoMessage() {}
+
+func (x *WalletBalances) ProtoReflect() protoreflect.Message {
+	mi := &file_balance_v1_balance_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletBalances.ProtoReflect.Descriptor instead.
+func (*WalletBalances) Descriptor() ([]byte, []int) {
+	return file_balance_v1_balance_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WalletBalances) GetCash() string {
+	if x != nil {
+		return x.Cash
+	}
+	return ""
+}
+
+func (x *WalletBalances) GetBonus() string {
+	if x != nil {
+		return x.Bonus
+	}
+	return ""
+}
+
+func (x *WalletBalances) GetLocked() string {
+	if x != nil {
+		return x.Locked
+	}
+	return ""
+}
+
+type Balance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint64                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Balance       string                 `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	Wallets       *WalletBalances        `protobuf:"bytes,3,opt,name=wallets,proto3" json:"wallets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	mi := &file_balance_v1_balance_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_balance_v1_balance_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_balance_v1_balance_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Balance) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Balance) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *Balance) GetWallets() *WalletBalances {
+	if x != nil {
+		return x.Wallets
+	}
+	return nil
+}
+
+type TransactionRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId uint64                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// One of game, server, payment, as in the Source-Type header.
+	SourceType string `protobuf:"bytes,2,opt,name=source_type,json=sourceType,proto3" json:"source_type,omitempty"`
+	// Either win or lose.
+	State         string `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Amount        string `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	TransactionId string `protobuf:"bytes,5,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	// Optional game round, as in the REST request. game_id and provider are
+	// required with round_id; close_round closes the round afterwards.
+	RoundId       string `protobuf:"bytes,6,opt,name=round_id,json=roundId,proto3" json:"round_id,omitempty"`
+	GameId        string `protobuf:"bytes,7,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Provider      string `protobuf:"bytes,8,opt,name=provider,proto3" json:"provider,omitempty"`
+	CloseRound    bool   `protobuf:"varint,9,opt,name=close_round,json=closeRound,proto3" json:"close_round,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionRequest) Reset() {
+	*x = TransactionRequest{}
+	mi := &file_balance_v1_balance_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionRequest) ProtoMessage() {}
+
+func (x *TransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_balance_v1_balance_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionRequest.ProtoReflect.Descriptor instead.
+func (*TransactionRequest) Descriptor() ([]byte, []int) {
+	return file_balance_v1_balance_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TransactionRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *TransactionRequest) GetSourceType() string {
+	if x != nil {
+		return x.SourceType
+	}
+	return ""
+}
+
+func (x *TransactionRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *TransactionRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *TransactionRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *TransactionRequest) GetRoundId() string {
+	if x != nil {
+		return x.RoundId
+	}
+	return ""
+}
+
+func (x *TransactionRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *TransactionRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *TransactionRequest) GetCloseRound() bool {
+	if x != nil {
+		return x.CloseRound
+	}
+	return false
+}
+
+type TransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// Set when the transaction was rejected on a stream.
+	Error *Error `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// booked, or pending when a fraud check held the transaction for review.
+	Status        string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionResponse) Reset() {
+	*x = TransactionResponse{}
+	mi := &file_balance_v1_balance_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionResponse) ProtoMessage() {}
+
+func (x *TransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_balance_v1_balance_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionResponse.ProtoReflect.Descriptor instead.
+func (*TransactionResponse) Descriptor() ([]byte, []int) {
+	return file_balance_v1_balance_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TransactionResponse) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *TransactionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TransactionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TransactionResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *TransactionResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type StreamBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint64                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	LastEventId   uint64                 `protobuf:"varint,2,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamBalanceRequest) Reset() {
+	*x = StreamBalanceRequest{}
+	mi := &file_balance_v1_balance_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamBalanceRequest) ProtoMessage() {}
+
+func (x *StreamBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_balance_v1_balance_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamBalanceRequest.ProtoReflect.Descriptor instead.
+func (*StreamBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_balance_v1_balance_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamBalanceRequest) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *StreamBalanceRequest) GetLastEventId() uint64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+type TransactionSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Amount        string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	SourceType    string                 `protobuf:"bytes,4,opt,name=source_type,json=sourceType,proto3" json:"source_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionSummary) Reset() {
+	*x = TransactionSummary{}
+	mi := &file_balance_v1_balance_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionSummary) ProtoMessage() {}
+
+func (x *TransactionSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_balance_v1_balance_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionSummary.ProtoReflect.Descriptor instead.
+func (*TransactionSummary) Descriptor() ([]byte, []int) {
+	return file_balance_v1_balance_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TransactionSummary) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *TransactionSummary) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *TransactionSummary) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *TransactionSummary) GetSourceType() string {
+	if x != nil {
+		return x.SourceType
+	}
+	return ""
+}
+
+type BalanceUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Zero for the snapshot sent when the stream cannot resume.
+	EventId       uint64                 `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	UserId        uint64                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Balance       string                 `protobuf:"bytes,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	Wallets       *WalletBalances        `protobuf:"bytes,4,opt,name=wallets,proto3" json:"wallets,omitempty"`
+	Transaction   *TransactionSummary    `protobuf:"bytes,5,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BalanceUpdate) Reset() {
+	*x = BalanceUpdate{}
+	mi := &file_balance_v1_balance_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BalanceUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalanceUpdate) ProtoMessage() {}
+
+func (x *BalanceUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_balance_v1_balance_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalanceUpdate.ProtoReflect.Descriptor instead.
+func (*BalanceUpdate) Descriptor() ([]byte, []int) {
+	return file_balance_v1_balance_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BalanceUpdate) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *BalanceUpdate) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *BalanceUpdate) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *BalanceUpdate) GetWallets() *WalletBalances {
+	if x != nil {
+		return x.Wallets
+	}
+	return nil
+}
+
+func (x *BalanceUpdate) GetTransaction() *TransactionSummary {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *BalanceUpdate) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+var File_balance_v1_balance_proto protoreflect.FileDescriptor
+
+const file_balance_v1_balance_proto_rawDesc = "" +
+	"\n" +
+	"\x18balance/v1/balance.proto\x12\n" +
+	"balance.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"i\n" +
+	"\x05Error\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x120\n" +
+	"\adetails\x18\x03 \x03(\v2\x16.balance.v1.FieldErrorR\adetails\"P\n" +
+	"\n" +
+	"FieldError\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\",\n" +
+	"\x11GetBalanceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x04R\x06userId\"R\n" +
+	"\x0eWalletBalances\x12\x12\n" +
+	"\x04cash\x18\x01 \x01(\tR\x04cash\x12\x14\n" +
+	"\x05bonus\x18\x02 \x01(\tR\x05bonus\x12\x16\n" +
+	"\x06locked\x18\x03 \x01(\tR\x06locked\"r\n" +
+	"\aBalance\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x04R\x06userId\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\tR\abalance\x124\n" +
+	"\awallets\x18\x03 \x01(\v2\x1a.balance.v1.WalletBalancesR\awallets\"\x94\x02\n" +
+	"\x12TransactionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x04R\x06userId\x12\x1f\n" +
+	"\vsource_type\x18\x02 \x01(\tR\n" +
+	"sourceType\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\tR\x06amount\x12%\n" +
+	"\x0etransaction_id\x18\x05 \x01(\tR\rtransactionId\x12\x19\n" +
+	"\bround_id\x18\x06 \x01(\tR\aroundId\x12\x17\n" +
+	"\agame_id\x18\a \x01(\tR\x06gameId\x12\x1a\n" +
+	"\bprovider\x18\b \x01(\tR\bprovider\x12\x1f\n" +
+	"\vclose_round\x18\t \x01(\bR\n" +
+	"closeRound\"\xb1\x01\n" +
+	"\x13TransactionResponse\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12'\n" +
+	"\x05error\x18\x04 \x01(\v2\x11.balance.v1.ErrorR\x05error\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\"S\n" +
+	"\x14StreamBalanceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x04R\x06userId\x12\"\n" +
+	"\rlast_event_id\x18\x02 \x01(\x04R\vlastEventId\"\x8a\x01\n" +
+	"\x12TransactionSummary\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\x12\x1f\n" +
+	"\vsource_type\x18\x04 \x01(\tR\n" +
+	"sourceType\"\x92\x02\n" +
+	"\rBalanceUpdate\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x04R\aeventId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x04R\x06userId\x12\x18\n" +
+	"\abalance\x18\x03 \x01(\tR\abalance\x124\n" +
+	"\awallets\x18\x04 \x01(\v2\x1a.balance.v1.WalletBalancesR\awallets\x12@\n" +
+	"\vtransaction\x18\x05 \x01(\v2\x1e.balance.v1.TransactionSummaryR\vtransaction\x12;\n" +
+	"\voccurred_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt2\xd5\x02\n" +
+	"\x0eBalanceService\x12@\n" +
+	"\n" +
+	"GetBalance\x12\x1d.balance.v1.GetBalanceRequest\x1a\x13.balance.v1.Balance\x12U\n" +
+	"\x12ProcessTransaction\x12\x1e.balance.v1.TransactionRequest\x1a\x1f.balance.v1.TransactionResponse\x12N\n" +
+	"\rStreamBalance\x12 .balance.v1.StreamBalanceRequest\x1a\x19.balance.v1.BalanceUpdate0\x01\x12Z\n" +
+	"\x13ProcessTransactions\x12\x1e.balance.v1.TransactionRequest\x1a\x1f.balance.v1.TransactionResponse(\x010\x01BDZBgithub.com/lielamurs/balance-transactions/api/balance/v1;balancev1b\x06proto3"
+
+var (
+	file_balance_v1_balance_proto_rawDescOnce sync.Once
+	file_balance_v1_balance_proto_rawDescData []byte
+)
+
+func file_balance_v1_balance_proto_rawDescGZIP() []byte {
+	file_balance_v1_balance_proto_rawDescOnce.Do(func() {
+		file_balance_v1_balance_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_balance_v1_balance_proto_rawDesc), len(file_balance_v1_balance_proto_rawDesc)))
+	})
+	return file_balance_v1_balance_proto_rawDescData
+}
+
+var file_balance_v1_balance_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_balance_v1_balance_proto_goTypes = []any{
+	(*Error)(nil),                 // 0: balance.v1.Error
+	(*FieldError)(nil),            // 1: balance.v1.FieldError
+	(*GetBalanceRequest)(nil),     // 2: balance.v1.GetBalanceRequest
+	(*WalletBalances)(nil),        // 3: balance.v1.WalletBalances
+	(*Balance)(nil),               // 4: balance.v1.Balance
+	(*TransactionRequest)(nil),    // 5: balance.v1.TransactionRequest
+	(*TransactionResponse)(nil),   // 6: balance.v1.TransactionResponse
+	(*StreamBalanceRequest)(nil),  // 7: balance.v1.StreamBalanceRequest
+	(*TransactionSummary)(nil),    // 8: balance.v1.TransactionSummary
+	(*BalanceUpdate)(nil),         // 9: balance.v1.BalanceUpdate
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+}
+var file_balance_v1_balance_proto_depIdxs = []int32{
+	1,  // 0: balance.v1.Error.details:type_name -> balance.v1.FieldError
+	3,  // 1: balance.v1.Balance.wallets:type_name -> balance.v1.WalletBalances
+	0,  // 2: balance.v1.TransactionResponse.error:type_name -> balance.v1.Error
+	3,  // 3: balance.v1.BalanceUpdate.wallets:type_name -> balance.v1.WalletBalances
+	8,  // 4: balance.v1.BalanceUpdate.transaction:type_name -> balance.v1.TransactionSummary
+	10, // 5: balance.v1.BalanceUpdate.occurred_at:type_name -> google.protobuf.Timestamp
+	2,  // 6: balance.v1.BalanceService.GetBalance:input_type -> balance.v1.GetBalanceRequest
+	5,  // 7: balance.v1.BalanceService.ProcessTransaction:input_type -> balance.v1.TransactionRequest
+	7,  // 8: balance.v1.BalanceService.StreamBalance:input_type -> balance.v1.StreamBalanceRequest
+	5,  // 9: balance.v1.BalanceService.ProcessTransactions:input_type -> balance.v1.TransactionRequest
+	4,  // 10: balance.v1.BalanceService.GetBalance:output_type -> balance.v1.Balance
+	6,  // 11: balance.v1.BalanceService.ProcessTransaction:output_type -> balance.v1.TransactionResponse
+	9,  // 12: balance.v1.BalanceService.StreamBalance:output_type -> balance.v1.BalanceUpdate
+	6,  // 13: balance.v1.BalanceService.ProcessTransactions:output_type -> balance.v1.TransactionResponse
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_balance_v1_balance_proto_init() }
+func file_balance_v1_balance_proto_init() {
+	if File_balance_v1_balance_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_balance_v1_balance_proto_rawDesc), len(file_balance_v1_balance_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_balance_v1_balance_proto_goTypes,
+		DependencyIndexes: file_balance_v1_balance_proto_depIdxs,
+		MessageInfos:      file_balance_v1_balance_proto_msgTypes,
+	}.Build()
+	File_balance_v1_balance_proto = out.File
+	file_balance_v1_balance_proto_goTypes = nil
+	file_balance_v1_balance_proto_depIdxs = nil
+}
@@ -0,0 +1,5 @@
+// Package balancev1 holds the gRPC contract for the balance service, generated
+// from balance.proto.
+package balancev1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative balance/v1/balance.proto
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lielamurs/balance-transactions/internal/service"
+)
+
+// runBackfillBalances implements the backfill-balances subcommand. It stores
+// the balance before and after every transaction booked before balances were
+// recorded and re-links the hash chain at the current version, for one user
+// or every user that needs it, and prints one JSON result per user.
+func runBackfillBalances(args []string) error {
+	flags := flag.NewFlagSet("backfill-balances", flag.ContinueOnError)
+	userID := flags.Uint64("user", 0, "user ID, every user with transactions missing balances or on an old chain version when 0")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	backfillService := service.NewBackfillService()
+	userIDs := []uint64{*userID}
+	if *userID == 0 {
+		var err error
+		if userIDs, err = backfillService.BackfillUserIDs(); err != nil {
+			return fmt.Errorf("failed to list users: %w", err)
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	for _, id := range userIDs {
+		result, err := backfillService.BackfillUser(id)
+		if err != nil {
+			return fmt.Errorf("user %d: %w", id, err)
+		}
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lielamurs/balance-transactions/internal/service"
+)
+
+// runVerifyChain implements the verify-chain subcommand. It verifies one
+// user's chain, or every user's, prints one JSON result per user and fails
+// when any chain is broken.
+func runVerifyChain(args []string) error {
+	flags := flag.NewFlagSet("verify-chain", flag.ContinueOnError)
+	userID := flags.Uint64("user", 0, "user ID, every user with transactions when 0")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	chainService := service.NewChainService()
+	userIDs := []uint64{*userID}
+	if *userID == 0 {
+		var err error
+		if userIDs, err = chainService.ChainUserIDs(); err != nil {
+			return fmt.Errorf("failed to list users: %w", err)
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	broken := 0
+	for _, id := range userIDs {
+		verification, err := chainService.VerifyUser(id)
+		if err != nil {
+			return fmt.Errorf("user %d: %w", id, err)
+		}
+		if err := encoder.Encode(verification); err != nil {
+			return err
+		}
+		if !verification.Intact {
+			broken++
+		}
+	}
+
+	if broken > 0 {
+		return fmt.Errorf("%d of %d chains are broken", broken, len(userIDs))
+	}
+	return nil
+}
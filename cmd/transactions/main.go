@@ -1,33 +1,230 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	balancev1 "github.com/lielamurs/balance-transactions/api/balance/v1"
+	"github.com/lielamurs/balance-transactions/internal/bonus"
+	"github.com/lielamurs/balance-transactions/internal/closing"
+	"github.com/lielamurs/balance-transactions/internal/config"
 	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/fraud"
 	"github.com/lielamurs/balance-transactions/internal/handler"
+	"github.com/lielamurs/balance-transactions/internal/outbox"
+	"github.com/lielamurs/balance-transactions/internal/review"
+	"github.com/lielamurs/balance-transactions/internal/rules"
+	"github.com/lielamurs/balance-transactions/internal/service"
+	"github.com/lielamurs/balance-transactions/internal/snapshot"
+	"github.com/lielamurs/balance-transactions/internal/webhook"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
+
 	setupLogger()
 
+	config.Init()
 	database.Init()
 
+	startRulesWatcher()
+	startOutboxRelay()
+	startSnapshotter()
+	startClosingScheduler()
+	startReviewExpirer()
+	startBonusExpirer()
+	startGRPCServer()
+
 	e := echo.New()
+	e.Validator = handler.NewRequestValidator()
 
+	e.Use(middleware.RequestID())
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 
+	registerRoutes(e)
+
+	logrus.Info("Starting server on :8080")
+	log.Fatal(e.Start(":8080"))
+}
+
+// runCommand runs a one-off subcommand instead of the server. Logs go to
+// stderr so they do not mix with output written to stdout.
+func runCommand(name string, args []string) {
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	logrus.SetOutput(os.Stderr)
+
+	config.Init()
+	database.Init()
+
+	var err error
+	switch name {
+	case "statement":
+		err = runStatement(args)
+	case "verify-chain":
+		err = runVerifyChain(args)
+	case "backfill-balances":
+		err = runBackfillBalances(args)
+	default:
+		err = fmt.Errorf("unknown command %q, expected statement, verify-chain or backfill-balances", name)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func registerRoutes(e *echo.Echo) {
 	userHandler := handler.NewUserHandler()
+	limitHandler := handler.NewLimitHandler()
+	accountHandler := handler.NewAccountHandler()
+	webhookHandler := handler.NewWebhookHandler()
+	statementHandler := handler.NewStatementHandler()
+	closingHandler := handler.NewClosingHandler()
+	chainHandler := handler.NewChainHandler()
+	auditHandler := handler.NewAuditHandler()
+	adjustmentHandler := handler.NewAdjustmentHandler()
+	reviewHandler := handler.NewReviewHandler()
 
 	e.GET("/user/:userId/balance", userHandler.GetBalance)
+	e.GET("/user/:userId/balance/stream", userHandler.StreamBalance)
+	e.GET("/user/:userId/bonuses", userHandler.GetBonuses)
 	e.POST("/user/:userId/transaction", userHandler.ProcessTransaction)
+	e.GET("/user/:userId/transactions/:transactionId", userHandler.GetUserTransaction)
+	e.GET("/transactions/:transactionId", userHandler.GetTransaction)
+	e.GET("/user/:userId/limits", limitHandler.GetLimits)
+	e.PUT("/user/:userId/limits/:type/:period", limitHandler.SetLimit)
+	e.DELETE("/user/:userId/limits/:type/:period", limitHandler.RemoveLimit)
+	e.GET("/rounds/:roundId", userHandler.GetRound)
 
-	logrus.Info("Starting server on :8080")
-	log.Fatal(e.Start(":8080"))
+	e.GET("/openapi.json", handler.OpenAPISpec)
+	e.GET("/docs", handler.Docs)
+
+	admin := e.Group("/admin", handler.RequireOperator)
+	admin.POST("/users", userHandler.CreateUser)
+	admin.GET("/users", userHandler.ListUsers)
+	admin.GET("/users/:userId", userHandler.GetUser)
+	admin.PUT("/users/:userId/status", accountHandler.ChangeStatus)
+	admin.GET("/users/:userId/status-history", accountHandler.GetStatusHistory)
+	admin.GET("/users/:userId/statement", statementHandler.GetStatement)
+	admin.GET("/users/:userId/chain/verify", chainHandler.VerifyChain)
+	admin.POST("/users/:userId/adjustments", adjustmentHandler.RequestAdjustment)
+	admin.GET("/adjustments", adjustmentHandler.ListAdjustments)
+	admin.GET("/adjustments/:adjustmentId", adjustmentHandler.GetAdjustment)
+	admin.POST("/adjustments/:adjustmentId/approve", adjustmentHandler.ApproveAdjustment)
+	admin.POST("/adjustments/:adjustmentId/reject", adjustmentHandler.RejectAdjustment)
+	admin.GET("/reviews", reviewHandler.ListReviews)
+	admin.GET("/reviews/:reviewId", reviewHandler.GetReview)
+	admin.POST("/reviews/:reviewId/approve", reviewHandler.ApproveReview)
+	admin.POST("/reviews/:reviewId/decline", reviewHandler.DeclineReview)
+	admin.POST("/rounds/:roundId/rollback", userHandler.RollbackRound)
+	admin.GET("/closings/:date", closingHandler.GetClosingReport)
+	admin.GET("/audit", auditHandler.ListEntries)
+	admin.GET("/metrics", handler.Metrics)
+	admin.POST("/webhooks", webhookHandler.CreateWebhook)
+	admin.GET("/webhooks", webhookHandler.ListWebhooks)
+	admin.DELETE("/webhooks/:webhookId", webhookHandler.DeleteWebhook)
+	admin.GET("/webhooks/deliveries", webhookHandler.ListDeliveries)
+	admin.POST("/webhooks/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
+}
+
+// startRulesWatcher loads the transaction rules and fraud checks and reloads
+// each file when it changes.
+func startRulesWatcher() {
+	cfg := config.Get()
+	engine := rules.Default()
+	if err := engine.Load(); err != nil {
+		log.Fatalf("Failed to load transaction rules from %s: %v", cfg.TransactionRulesFile, err)
+	}
+	go engine.Watch(context.Background(), cfg.TransactionRulesReload)
+
+	checks := fraud.Default()
+	if err := checks.Load(); err != nil {
+		log.Fatalf("Failed to load fraud checks from %s: %v", cfg.FraudChecksFile, err)
+	}
+	go checks.Watch(context.Background(), cfg.TransactionRulesReload)
+}
+
+func startOutboxRelay() {
+	cfg := config.Get()
+	webhookRepo := database.NewWebhookRepository()
+
+	publishers := []outbox.Publisher{webhook.NewPublisher(webhookRepo)}
+	switch cfg.OutboxPublisher {
+	case "none":
+	case "stdout":
+		publishers = append(publishers, outbox.NewStdoutPublisher())
+	case "file":
+		filePublisher, err := outbox.NewFilePublisher(cfg.OutboxFile)
+		if err != nil {
+			log.Fatalf("Failed to open outbox file %s: %v", cfg.OutboxFile, err)
+		}
+		publishers = append(publishers, filePublisher)
+	case "memory":
+		publishers = append(publishers, outbox.NewMemoryPublisher())
+	default:
+		log.Fatalf("Unknown OUTBOX_PUBLISHER %q, expected none, stdout, file or memory", cfg.OutboxPublisher)
+	}
+
+	relay := outbox.NewRelay(database.NewOutboxRepository(), outbox.NewMultiPublisher(publishers...), cfg.OutboxBatchSize, cfg.OutboxPollInterval, cfg.OutboxMaxBackoff)
+	go relay.Run(context.Background())
+
+	client := &http.Client{Timeout: cfg.WebhookTimeout}
+	dispatcher := webhook.NewDispatcher(webhookRepo, client, cfg.OutboxBatchSize, cfg.WebhookPollInterval, cfg.WebhookRetryBase, cfg.WebhookMaxAttempts)
+	go dispatcher.Run(context.Background())
+}
+
+func startSnapshotter() {
+	snapshotter := snapshot.NewSnapshotter(database.NewSnapshotRepository(), config.Get().SnapshotInterval)
+	go snapshotter.Run(context.Background())
+}
+
+func startClosingScheduler() {
+	cfg := config.Get()
+	scheduler := closing.NewScheduler(database.NewClosingRepository(), cfg.ClosingLocation, cfg.ClosingPollInterval)
+	go scheduler.Run(context.Background())
+}
+
+func startReviewExpirer() {
+	cfg := config.Get()
+	expirer := review.NewExpirer(service.NewReviewService(), cfg.ReviewSLA, cfg.ReviewPollInterval)
+	go expirer.Run(context.Background())
+}
+
+func startBonusExpirer() {
+	expirer := bonus.NewExpirer(service.NewUserService(), config.Get().BonusExpiryPollInterval)
+	go expirer.Run(context.Background())
+}
+
+func startGRPCServer() {
+	addr := fmt.Sprintf(":%d", config.Get().GRPCPort)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Failed to listen for gRPC on %s: %v", addr, err)
+	}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(handler.GRPCRecover),
+		grpc.ChainStreamInterceptor(handler.GRPCStreamRecover),
+	)
+	balancev1.RegisterBalanceServiceServer(server, handler.NewGRPCHandler())
+	reflection.Register(server)
+
+	logrus.Infof("Starting gRPC server on %s", addr)
+	go func() {
+		log.Fatal(server.Serve(listener))
+	}()
 }
 
 func setupLogger() {
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(api.OpenAPI, &spec))
+
+	documented := map[string]bool{}
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if method != "parameters" {
+				documented[strings.ToUpper(method)+" "+path] = true
+			}
+		}
+	}
+
+	e := echo.New()
+	registerRoutes(e)
+
+	param := regexp.MustCompile(`:(\w+)`)
+	registered := map[string]bool{}
+	for _, route := range e.Routes() {
+		if route.Method == echo.RouteNotFound {
+			continue
+		}
+		registered[route.Method+" "+param.ReplaceAllString(route.Path, "{$1}")] = true
+	}
+
+	for route := range registered {
+		assert.True(t, documented[route], "%s is not in api/openapi.json", route)
+	}
+	for route := range documented {
+		assert.True(t, registered[route], "%s is documented but not registered", route)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lielamurs/balance-transactions/internal/service"
+	"github.com/lielamurs/balance-transactions/internal/statement"
+)
+
+// runStatement implements the statement subcommand, which writes the same
+// statement as the admin endpoint to a file or stdout.
+func runStatement(args []string) error {
+	flags := flag.NewFlagSet("statement", flag.ContinueOnError)
+	userID := flags.Uint64("user", 0, "user ID")
+	from := flags.String("from", "", "start of the period, YYYY-MM-DD or RFC 3339")
+	to := flags.String("to", "", "end of the period, YYYY-MM-DD (inclusive) or RFC 3339 (exclusive)")
+	format := flags.String("format", statement.FormatCSV, "csv, json or html")
+	out := flags.String("out", "", "output file, stdout when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *userID == 0 {
+		return errors.New("-user is required")
+	}
+	start, end, err := statement.ParseRange(*from, *to)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *out, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	writer, err := statement.NewWriter(*format, w)
+	if err != nil {
+		return err
+	}
+	return service.NewStatementService().WriteStatement(*userID, start, end, writer)
+}
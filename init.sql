@@ -1,6 +1,34 @@
 CREATE TABLE users (
-    id BIGINT PRIMARY KEY,
+    id BIGSERIAL PRIMARY KEY,
+    external_ref VARCHAR(255) UNIQUE,
+    metadata JSONB NOT NULL DEFAULT '{}',
     balance DECIMAL(15,2) DEFAULT 0.00,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'self_excluded', 'closed')),
+    excluded_until TIMESTAMP,
+    version BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE account_status_changes (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    excluded_until TIMESTAMP,
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE game_rounds (
+    id BIGSERIAL PRIMARY KEY,
+    round_id VARCHAR(255) UNIQUE NOT NULL,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    game_id VARCHAR(255) NOT NULL,
+    provider VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed', 'rolled_back')),
+    closed_at TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -11,14 +39,268 @@ CREATE TABLE transactions (
     transaction_id VARCHAR(255) UNIQUE NOT NULL,
     amount DECIMAL(15,2) NOT NULL,
     state VARCHAR(10) NOT NULL CHECK (state IN ('win', 'lose')),
-    source_type VARCHAR(20) NOT NULL CHECK (source_type IN ('game', 'server', 'payment')),
+    source_type VARCHAR(20) NOT NULL CHECK (source_type IN ('game', 'server', 'payment', 'adjustment')),
+    round_id BIGINT REFERENCES game_rounds(id),
+    balance_before DECIMAL(15,2),
+    balance_after DECIMAL(15,2),
+    chain_version SMALLINT NOT NULL DEFAULT 1,
+    previous_hash CHAR(64),
+    hash CHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, previous_hash)
+);
+
+CREATE TABLE balance_adjustments (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('credit', 'debit')),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    reason_code VARCHAR(50) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    requested_by VARCHAR(255) NOT NULL,
+    reviewed_by VARCHAR(255),
+    review_note TEXT,
+    reviewed_at TIMESTAMP,
+    transaction_id VARCHAR(255) UNIQUE REFERENCES transactions(transaction_id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (reviewed_by IS NULL OR reviewed_by <> requested_by)
+);
+
+CREATE TABLE wallets (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('cash', 'bonus', 'locked')),
+    balance DECIMAL(15,2) NOT NULL DEFAULT 0.00 CHECK (balance >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, type)
+);
+
+CREATE TABLE bonuses (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    transaction_id BIGINT NOT NULL REFERENCES transactions(id),
+    amount DECIMAL(15,2) NOT NULL,
+    balance DECIMAL(15,2) NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    wagering_required DECIMAL(15,2) NOT NULL,
+    wagering_remaining DECIMAL(15,2) NOT NULL CHECK (wagering_remaining >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'forfeited')),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE user_limits (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    limit_type VARCHAR(20) NOT NULL CHECK (limit_type IN ('loss', 'deposit')),
+    period VARCHAR(20) NOT NULL CHECK (period IN ('daily', 'weekly', 'monthly')),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    pending_amount DECIMAL(15,2) CHECK (pending_amount > 0),
+    pending_effective_at TIMESTAMP,
+    set_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, limit_type, period)
+);
+
+CREATE TABLE wallet_entries (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    transaction_id BIGINT REFERENCES transactions(id),
+    bonus_id BIGINT REFERENCES bonuses(id),
+    wallet_type VARCHAR(20) NOT NULL CHECK (wallet_type IN ('cash', 'bonus', 'locked')),
+    amount DECIMAL(15,2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE bonus_wagers (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL REFERENCES transactions(id),
+    bonus_id BIGINT NOT NULL REFERENCES bonuses(id),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE transaction_flags (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL REFERENCES transactions(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    rule_id VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE transaction_reviews (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    transaction_id VARCHAR(255) UNIQUE NOT NULL,
+    source_type VARCHAR(20) NOT NULL,
+    state VARCHAR(10) NOT NULL CHECK (state IN ('win', 'lose')),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    round_id VARCHAR(255),
+    game_id VARCHAR(255),
+    provider VARCHAR(255),
+    close_round BOOLEAN NOT NULL DEFAULT FALSE,
+    rule_id VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    flags JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'declined')),
+    reviewed_by VARCHAR(255),
+    review_note TEXT,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_id BIGINT NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    dedupe_key VARCHAR(255) UNIQUE NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    available_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE webhook_subscriptions (
+    id SERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    event_types TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions(id),
+    event_id BIGINT NOT NULL REFERENCES outbox_events(id),
+    event_type VARCHAR(100) NOT NULL,
+    dedupe_key VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_status_code INT,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (subscription_id, dedupe_key)
+);
+
+CREATE TABLE balance_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    transaction_id BIGINT NOT NULL REFERENCES transactions(id),
+    balance DECIMAL(15,2) NOT NULL,
+    as_of TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, transaction_id)
+);
+
+CREATE TABLE daily_closings (
+    id BIGSERIAL PRIMARY KEY,
+    business_date DATE UNIQUE NOT NULL,
+    time_zone VARCHAR(64) NOT NULL,
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    closed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (period_end > period_start)
+);
+
+CREATE TABLE daily_closing_balances (
+    closing_id BIGINT NOT NULL REFERENCES daily_closings(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    opening_balance DECIMAL(15,2) NOT NULL,
+    closing_balance DECIMAL(15,2) NOT NULL,
+    PRIMARY KEY (closing_id, user_id)
+);
+
+CREATE TABLE daily_closing_totals (
+    closing_id BIGINT NOT NULL REFERENCES daily_closings(id),
+    source_type VARCHAR(20) NOT NULL,
+    state VARCHAR(10) NOT NULL,
+    transactions INT NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    PRIMARY KEY (closing_id, source_type, state)
+);
+
+CREATE FUNCTION reject_closing_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'closed business days are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER daily_closings_immutable BEFORE UPDATE OR DELETE ON daily_closings
+    FOR EACH ROW EXECUTE FUNCTION reject_closing_changes();
+CREATE TRIGGER daily_closing_balances_immutable BEFORE UPDATE OR DELETE ON daily_closing_balances
+    FOR EACH ROW EXECUTE FUNCTION reject_closing_changes();
+CREATE TRIGGER daily_closing_totals_immutable BEFORE UPDATE OR DELETE ON daily_closing_totals
+    FOR EACH ROW EXECUTE FUNCTION reject_closing_changes();
+
+CREATE TABLE audit_entries (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    target_user_id BIGINT REFERENCES users(id),
+    resource VARCHAR(255) NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE FUNCTION reject_audit_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit entries are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_entries_append_only BEFORE UPDATE OR DELETE ON audit_entries
+    FOR EACH ROW EXECUTE FUNCTION reject_audit_changes();
+
 CREATE INDEX idx_transactions_user_id ON transactions(user_id);
 CREATE INDEX idx_transactions_transaction_id ON transactions(transaction_id);
+CREATE INDEX idx_transactions_user_id_source_state ON transactions(user_id, source_type, state, created_at);
+CREATE INDEX idx_transactions_user_id_created_at ON transactions(user_id, created_at, id);
+CREATE INDEX idx_transactions_created_at ON transactions(created_at);
+CREATE INDEX idx_transactions_round_id ON transactions(round_id) WHERE round_id IS NOT NULL;
+CREATE INDEX idx_transactions_source_state_created_at ON transactions(source_type, state, created_at);
+CREATE INDEX idx_balance_adjustments_status ON balance_adjustments(status, id);
+CREATE INDEX idx_balance_adjustments_user_id ON balance_adjustments(user_id, id);
+CREATE INDEX idx_account_status_changes_user_id ON account_status_changes(user_id);
+CREATE INDEX idx_outbox_events_unpublished ON outbox_events(aggregate_id, id) WHERE published_at IS NULL;
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_audit_entries_actor ON audit_entries(actor, created_at);
+CREATE INDEX idx_audit_entries_target_user_id ON audit_entries(target_user_id, created_at);
+CREATE INDEX idx_audit_entries_created_at ON audit_entries(created_at);
+CREATE INDEX idx_bonuses_user_id_status ON bonuses(user_id, status);
+CREATE INDEX idx_wallet_entries_user_id ON wallet_entries(user_id);
+CREATE INDEX idx_wallet_entries_transaction_id ON wallet_entries(transaction_id);
+CREATE INDEX idx_bonus_wagers_transaction_id ON bonus_wagers(transaction_id);
+CREATE INDEX idx_transaction_flags_transaction_id ON transaction_flags(transaction_id);
+CREATE INDEX idx_transaction_flags_rule_id ON transaction_flags(rule_id, created_at);
+CREATE INDEX idx_transaction_reviews_status ON transaction_reviews(status, id);
+CREATE INDEX idx_transaction_reviews_user_id ON transaction_reviews(user_id, id);
+CREATE INDEX idx_transaction_reviews_pending_created_at ON transaction_reviews(created_at) WHERE status = 'pending';
 
 INSERT INTO users (id, balance) VALUES 
 (1, 0.00),
 (2, 0.00),
-(3, 0.00);
\ No newline at end of file
+(3, 0.00);
+
+SELECT setval('users_id_seq', (SELECT MAX(id) FROM users));
+
+INSERT INTO wallets (user_id, type, balance) VALUES
+(1, 'cash', 0.00),
+(2, 'cash', 0.00),
+(3, 'cash', 0.00);
@@ -0,0 +1,61 @@
+package bonus
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Store is the part of the user service the expirer depends on.
+type Store interface {
+	ExpireBonuses(now time.Time) (int, error)
+}
+
+// Expirer forfeits bonuses past their expiry, so an expired bonus stops being
+// shown and spent as active even when its user never transacts again.
+type Expirer struct {
+	store    Store
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewExpirer(store Store, interval time.Duration) *Expirer {
+	return &Expirer{
+		store:    store,
+		interval: interval,
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (e *Expirer) Run(ctx context.Context) {
+	logrus.WithField("interval", e.interval).Info("Bonus expirer started")
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.ExpireBonuses()
+
+		select {
+		case <-ctx.Done():
+			logrus.Info("Bonus expirer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireBonuses forfeits the bonuses that have expired and returns how many
+// users' bonuses it forfeited.
+func (e *Expirer) ExpireBonuses() int {
+	expired, err := e.store.ExpireBonuses(e.now())
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"users": expired, "error": err}).Error("Failed to forfeit expired bonuses")
+		return expired
+	}
+	if expired > 0 {
+		logrus.WithField("users", expired).Info("Expired bonuses forfeited")
+	}
+	return expired
+}
@@ -0,0 +1,76 @@
+package bonus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	mu      sync.Mutex
+	now     []time.Time
+	expired int
+	err     error
+}
+
+func (f *fakeStore) ExpireBonuses(now time.Time) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = append(f.now, now)
+	return f.expired, f.err
+}
+
+func (f *fakeStore) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.now)
+}
+
+func TestExpireBonuses(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		store    *fakeStore
+		expected int
+	}{
+		{name: "bonuses forfeited", store: &fakeStore{expired: 2}, expected: 2},
+		{name: "nothing expired", store: &fakeStore{}, expected: 0},
+		{name: "store failure after some users", store: &fakeStore{expired: 1, err: errors.New("connection refused")}, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expirer := NewExpirer(tt.store, time.Minute)
+			expirer.now = func() time.Time { return now }
+
+			assert.Equal(t, tt.expected, expirer.ExpireBonuses())
+			assert.Equal(t, []time.Time{now}, tt.store.now)
+		})
+	}
+}
+
+func TestRunExpiresImmediatelyAndOnEveryTick(t *testing.T) {
+	store := &fakeStore{}
+	expirer := NewExpirer(store, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		expirer.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return store.Calls() >= 3 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expirer did not stop after the context was cancelled")
+	}
+}
@@ -0,0 +1,128 @@
+package closing
+
+import (
+	"context"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// Store is the part of the closing repository the scheduler depends on.
+type Store interface {
+	GetLastClosing() (*model.DailyClosing, error)
+	CloseDay(closing *model.DailyClosing) (bool, error)
+}
+
+// Scheduler closes every business day once it has ended in the closing time
+// zone. Days are closed oldest first and each period starts where the
+// previous one ended, so the closings cover the ledger without gaps.
+type Scheduler struct {
+	store    Store
+	location *time.Location
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewScheduler(store Store, location *time.Location, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		location: location,
+		interval: interval,
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s *Scheduler) Run(ctx context.Context) {
+	logrus.WithFields(logrus.Fields{"timeZone": s.location.String(), "interval": s.interval}).Info("Closing scheduler started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.CloseDueDays(); err != nil {
+			logrus.WithField("error", err).Error("Failed to close business days")
+		}
+
+		select {
+		case <-ctx.Done():
+			logrus.Info("Closing scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CloseDueDays closes every ended business day after the last closing and
+// returns how many it closed. Without any closing it starts with the
+// previous day.
+func (s *Scheduler) CloseDueDays() (int, error) {
+	last, err := s.store.GetLastClosing()
+	if err != nil {
+		return 0, err
+	}
+
+	var lastDate, previousEnd *time.Time
+	if last != nil {
+		lastDate = &last.BusinessDate
+		previousEnd = &last.PeriodEnd
+	}
+
+	closed := 0
+	for _, day := range DueDays(lastDate, s.now(), s.location) {
+		start, end := Period(day, s.location)
+		if previousEnd != nil {
+			start = *previousEnd
+		}
+
+		closing := &model.DailyClosing{
+			BusinessDate: day,
+			TimeZone:     s.location.String(),
+			PeriodStart:  start,
+			PeriodEnd:    end,
+			ClosedAt:     s.now(),
+		}
+		written, err := s.store.CloseDay(closing)
+		if err != nil {
+			return closed, err
+		}
+		if written {
+			closed++
+			logrus.WithFields(logrus.Fields{
+				"businessDate": day.Format("2006-01-02"),
+				"periodStart":  start,
+				"periodEnd":    end,
+			}).Info("Business day closed")
+		}
+		previousEnd = &end
+	}
+
+	return closed, nil
+}
+
+// DueDays lists the business dates that have ended at now in location and
+// come after last, oldest first. Dates are midnight UTC values.
+func DueDays(last *time.Time, now time.Time, location *time.Location) []time.Time {
+	year, month, day := now.In(location).Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	next := today.AddDate(0, 0, -1)
+	if last != nil {
+		next = last.AddDate(0, 0, 1)
+	}
+
+	var days []time.Time
+	for ; next.Before(today); next = next.AddDate(0, 0, 1) {
+		days = append(days, next)
+	}
+	return days
+}
+
+// Period returns the UTC instants a business date starts and ends at in
+// location. Days around a daylight saving change are 23 or 25 hours long.
+func Period(date time.Time, location *time.Location) (time.Time, time.Time) {
+	year, month, day := date.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, location)
+	end := time.Date(year, month, day+1, 0, 0, 0, 0, location)
+	return start.UTC(), end.UTC()
+}
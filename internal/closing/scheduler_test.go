@@ -0,0 +1,178 @@
+package closing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	last     *model.DailyClosing
+	closings []model.DailyClosing
+	closed   map[string]bool
+	err      error
+}
+
+func (f *fakeStore) GetLastClosing() (*model.DailyClosing, error) {
+	return f.last, nil
+}
+
+func (f *fakeStore) CloseDay(closing *model.DailyClosing) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	if f.closed[closing.BusinessDate.Format("2006-01-02")] {
+		return false, nil
+	}
+	f.closings = append(f.closings, *closing)
+	return true, nil
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDueDays(t *testing.T) {
+	riga, err := time.LoadLocation("Europe/Riga")
+	require.NoError(t, err)
+	last := date(2025, 6, 28)
+
+	tests := []struct {
+		name     string
+		last     *time.Time
+		now      time.Time
+		location *time.Location
+		expected []time.Time
+	}{
+		{
+			name:     "first run closes the previous day",
+			now:      time.Date(2025, 7, 2, 0, 5, 0, 0, time.UTC),
+			location: time.UTC,
+			expected: []time.Time{date(2025, 7, 1)},
+		},
+		{
+			name:     "catches up on missed days",
+			last:     &last,
+			now:      time.Date(2025, 7, 1, 8, 0, 0, 0, time.UTC),
+			location: time.UTC,
+			expected: []time.Time{date(2025, 6, 29), date(2025, 6, 30)},
+		},
+		{
+			name:     "nothing due before the day ends",
+			last:     &last,
+			now:      time.Date(2025, 6, 29, 23, 59, 0, 0, time.UTC),
+			location: time.UTC,
+		},
+		{
+			name:     "day ends in the closing time zone",
+			last:     &last,
+			now:      time.Date(2025, 6, 29, 21, 30, 0, 0, time.UTC),
+			location: riga,
+			expected: []time.Time{date(2025, 6, 29)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DueDays(tt.last, tt.now, tt.location))
+		})
+	}
+}
+
+func TestPeriod(t *testing.T) {
+	riga, err := time.LoadLocation("Europe/Riga")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		date          time.Time
+		location      *time.Location
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "UTC",
+			date:          date(2025, 6, 1),
+			location:      time.UTC,
+			expectedStart: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "summer time",
+			date:          date(2025, 6, 1),
+			location:      riga,
+			expectedStart: time.Date(2025, 5, 31, 21, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 6, 1, 21, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "clocks go forward",
+			date:          date(2025, 3, 30),
+			location:      riga,
+			expectedStart: time.Date(2025, 3, 29, 22, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 3, 30, 21, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := Period(tt.date, tt.location)
+			assert.Equal(t, tt.expectedStart, start)
+			assert.Equal(t, tt.expectedEnd, end)
+		})
+	}
+}
+
+func TestCloseDueDaysChainsPeriods(t *testing.T) {
+	riga, err := time.LoadLocation("Europe/Riga")
+	require.NoError(t, err)
+
+	// The previous closing was taken in UTC; the next period starts where it
+	// ended even though the time zone has changed since.
+	store := &fakeStore{
+		last: &model.DailyClosing{
+			BusinessDate: date(2025, 6, 28),
+			PeriodStart:  time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:    time.Date(2025, 6, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	scheduler := NewScheduler(store, riga, time.Minute)
+	scheduler.now = func() time.Time { return time.Date(2025, 6, 30, 22, 0, 0, 0, time.UTC) }
+
+	closed, err := scheduler.CloseDueDays()
+	require.NoError(t, err)
+	assert.Equal(t, 2, closed)
+	require.Len(t, store.closings, 2)
+
+	assert.Equal(t, date(2025, 6, 29), store.closings[0].BusinessDate)
+	assert.Equal(t, time.Date(2025, 6, 29, 0, 0, 0, 0, time.UTC), store.closings[0].PeriodStart)
+	assert.Equal(t, time.Date(2025, 6, 29, 21, 0, 0, 0, time.UTC), store.closings[0].PeriodEnd)
+	assert.Equal(t, "Europe/Riga", store.closings[0].TimeZone)
+
+	assert.Equal(t, date(2025, 6, 30), store.closings[1].BusinessDate)
+	assert.Equal(t, store.closings[0].PeriodEnd, store.closings[1].PeriodStart)
+	assert.Equal(t, time.Date(2025, 6, 30, 21, 0, 0, 0, time.UTC), store.closings[1].PeriodEnd)
+}
+
+func TestCloseDueDaysSkipsDaysClosedElsewhere(t *testing.T) {
+	store := &fakeStore{closed: map[string]bool{"2025-07-01": true}}
+	scheduler := NewScheduler(store, time.UTC, time.Minute)
+	scheduler.now = func() time.Time { return time.Date(2025, 7, 2, 0, 1, 0, 0, time.UTC) }
+
+	closed, err := scheduler.CloseDueDays()
+	require.NoError(t, err)
+	assert.Equal(t, 0, closed)
+	assert.Empty(t, store.closings)
+}
+
+func TestCloseDueDaysStopsOnError(t *testing.T) {
+	store := &fakeStore{err: errors.New("connection refused")}
+	scheduler := NewScheduler(store, time.UTC, time.Minute)
+
+	closed, err := scheduler.CloseDueDays()
+	assert.Error(t, err)
+	assert.Equal(t, 0, closed)
+}
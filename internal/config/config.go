@@ -0,0 +1,159 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	GRPCPort                int
+	BonusWageringMultiplier int
+	BonusExpiry             time.Duration
+	BonusExpiryPollInterval time.Duration
+	LimitCoolingOff         time.Duration
+	AutoProvisionSources    []string
+	OutboxPublisher         string
+	OutboxFile              string
+	OutboxBatchSize         int
+	OutboxPollInterval      time.Duration
+	OutboxMaxBackoff        time.Duration
+	WebhookPollInterval     time.Duration
+	WebhookRetryBase        time.Duration
+	WebhookMaxAttempts      int
+	WebhookTimeout          time.Duration
+	StreamHeartbeat         time.Duration
+	StreamBufferSize        int
+	StreamSubscriberBuffer  int
+	SnapshotInterval        time.Duration
+	ClosingLocation         *time.Location
+	ClosingPollInterval     time.Duration
+	TransactionRulesFile    string
+	TransactionRulesReload  time.Duration
+	FraudChecksFile         string
+	ReviewSLA               time.Duration
+	ReviewPollInterval      time.Duration
+	BalanceLocking          string
+	OptimisticAttempts      int
+	RetryAttempts           int
+	RetryBaseDelay          time.Duration
+	RetryMaxDelay           time.Duration
+}
+
+var Cfg *Config
+
+func Init() {
+	Cfg = Load()
+}
+
+func Load() *Config {
+	return &Config{
+		GRPCPort:                getInt("GRPC_PORT", 9090),
+		BonusWageringMultiplier: getInt("BONUS_WAGERING_MULTIPLIER", 30),
+		BonusExpiry:             getDuration("BONUS_EXPIRY", 30*24*time.Hour),
+		BonusExpiryPollInterval: getDuration("BONUS_EXPIRY_POLL_INTERVAL", time.Minute),
+		LimitCoolingOff:         getDuration("LIMIT_COOLING_OFF", 24*time.Hour),
+		AutoProvisionSources:    getList("AUTO_PROVISION_SOURCE_TYPES"),
+		OutboxPublisher:         getString("OUTBOX_PUBLISHER", "none"),
+		OutboxFile:              getString("OUTBOX_FILE", "outbox-events.jsonl"),
+		OutboxBatchSize:         getInt("OUTBOX_BATCH_SIZE", 100),
+		OutboxPollInterval:      getDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxMaxBackoff:        getDuration("OUTBOX_MAX_BACKOFF", 5*time.Minute),
+		WebhookPollInterval:     getDuration("WEBHOOK_POLL_INTERVAL", time.Second),
+		WebhookRetryBase:        getDuration("WEBHOOK_RETRY_BASE", 5*time.Second),
+		WebhookMaxAttempts:      getInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookTimeout:          getDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		StreamHeartbeat:         getDuration("STREAM_HEARTBEAT_INTERVAL", 15*time.Second),
+		StreamBufferSize:        getInt("STREAM_BUFFER_SIZE", 100),
+		StreamSubscriberBuffer:  getInt("STREAM_SUBSCRIBER_BUFFER", 16),
+		SnapshotInterval:        getDuration("BALANCE_SNAPSHOT_INTERVAL", time.Hour),
+		ClosingLocation:         getLocation("CLOSING_TIMEZONE", time.UTC),
+		ClosingPollInterval:     getDuration("CLOSING_POLL_INTERVAL", time.Minute),
+		TransactionRulesFile:    getString("TRANSACTION_RULES_FILE", ""),
+		TransactionRulesReload:  getDuration("TRANSACTION_RULES_RELOAD_INTERVAL", 10*time.Second),
+		FraudChecksFile:         getString("FRAUD_CHECKS_FILE", ""),
+		ReviewSLA:               getDuration("REVIEW_SLA", 24*time.Hour),
+		ReviewPollInterval:      getDuration("REVIEW_POLL_INTERVAL", time.Minute),
+		BalanceLocking:          getChoice("BALANCE_LOCKING", "pessimistic", "pessimistic", "optimistic"),
+		OptimisticAttempts:      getInt("OPTIMISTIC_MAX_ATTEMPTS", 5),
+		RetryAttempts:           getInt("DB_RETRY_MAX_ATTEMPTS", 3),
+		RetryBaseDelay:          getDuration("DB_RETRY_BASE_DELAY", 10*time.Millisecond),
+		RetryMaxDelay:           getDuration("DB_RETRY_MAX_DELAY", 500*time.Millisecond),
+	}
+}
+
+func Get() *Config {
+	if Cfg == nil {
+		Cfg = Load()
+	}
+	return Cfg
+}
+
+func getString(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("%s must be an integer: %v", key, err)
+	}
+	return parsed
+}
+
+func getChoice(key, fallback string, choices ...string) string {
+	value := getString(key, fallback)
+	for _, choice := range choices {
+		if value == choice {
+			return value
+		}
+	}
+	log.Fatalf("%s must be one of %s, got %q", key, strings.Join(choices, ", "), value)
+	return ""
+}
+
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("%s must be a duration such as 720h: %v", key, err)
+	}
+	return parsed
+}
+
+func getLocation(key string, fallback *time.Location) *time.Location {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	location, err := time.LoadLocation(value)
+	if err != nil {
+		log.Fatalf("%s must be an IANA time zone such as Europe/Riga: %v", key, err)
+	}
+	return location
+}
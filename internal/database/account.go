@@ -0,0 +1,42 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+)
+
+type AccountRepository interface {
+	UpdateUserStatus(tx *gorm.DB, userID uint64, status string, excludedUntil *time.Time) error
+	CreateStatusChange(tx *gorm.DB, change *model.AccountStatusChange) error
+	GetStatusChanges(userID uint64) ([]model.AccountStatusChange, error)
+}
+
+type accountRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository() AccountRepository {
+	return &accountRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *accountRepository) UpdateUserStatus(tx *gorm.DB, userID uint64, status string, excludedUntil *time.Time) error {
+	return tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"status":         status,
+		"excluded_until": excludedUntil,
+		"version":        gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (r *accountRepository) CreateStatusChange(tx *gorm.DB, change *model.AccountStatusChange) error {
+	return tx.Create(change).Error
+}
+
+func (r *accountRepository) GetStatusChanges(userID uint64) ([]model.AccountStatusChange, error) {
+	var changes []model.AccountStatusChange
+	err := r.db.Where("user_id = ?", userID).Order("id").Find(&changes).Error
+	return changes, err
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AdjustmentRepository interface {
+	CreateAdjustment(tx *gorm.DB, adjustment *model.BalanceAdjustment) error
+	GetAdjustment(adjustmentID uint64) (*model.BalanceAdjustment, error)
+	GetAdjustmentForUpdate(tx *gorm.DB, adjustmentID uint64) (*model.BalanceAdjustment, error)
+	ListAdjustments(status string, userID uint64, offset, limit int) ([]model.BalanceAdjustment, int64, error)
+	SaveAdjustment(tx *gorm.DB, adjustment *model.BalanceAdjustment) error
+	GetDB() *gorm.DB
+}
+
+type adjustmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAdjustmentRepository() AdjustmentRepository {
+	return &adjustmentRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *adjustmentRepository) CreateAdjustment(tx *gorm.DB, adjustment *model.BalanceAdjustment) error {
+	return tx.Create(adjustment).Error
+}
+
+func (r *adjustmentRepository) GetAdjustment(adjustmentID uint64) (*model.BalanceAdjustment, error) {
+	var adjustment model.BalanceAdjustment
+	err := r.db.Where("id = ?", adjustmentID).First(&adjustment).Error
+	return &adjustment, err
+}
+
+func (r *adjustmentRepository) GetAdjustmentForUpdate(tx *gorm.DB, adjustmentID uint64) (*model.BalanceAdjustment, error) {
+	var adjustment model.BalanceAdjustment
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", adjustmentID).First(&adjustment).Error
+	return &adjustment, err
+}
+
+func (r *adjustmentRepository) ListAdjustments(status string, userID uint64, offset, limit int) ([]model.BalanceAdjustment, int64, error) {
+	var total int64
+	if err := r.filtered(status, userID).Model(&model.BalanceAdjustment{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var adjustments []model.BalanceAdjustment
+	err := r.filtered(status, userID).Order("id DESC").Offset(offset).Limit(limit).Find(&adjustments).Error
+	return adjustments, total, err
+}
+
+func (r *adjustmentRepository) filtered(status string, userID uint64) *gorm.DB {
+	query := r.db
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	return query
+}
+
+func (r *adjustmentRepository) SaveAdjustment(tx *gorm.DB, adjustment *model.BalanceAdjustment) error {
+	return tx.Save(adjustment).Error
+}
+
+func (r *adjustmentRepository) GetDB() *gorm.DB {
+	return r.db
+}
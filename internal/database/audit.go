@@ -0,0 +1,64 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+)
+
+// AuditFilter narrows an audit query. Zero values leave a field unfiltered;
+// From is inclusive and To exclusive.
+type AuditFilter struct {
+	Actor        string
+	TargetUserID uint64
+	From         *time.Time
+	To           *time.Time
+}
+
+type AuditRepository interface {
+	CreateEntry(tx *gorm.DB, entry *model.AuditEntry) error
+	ListEntries(filter AuditFilter, offset, limit int) ([]model.AuditEntry, int64, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository() AuditRepository {
+	return &auditRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *auditRepository) CreateEntry(tx *gorm.DB, entry *model.AuditEntry) error {
+	return tx.Create(entry).Error
+}
+
+func (r *auditRepository) ListEntries(filter AuditFilter, offset, limit int) ([]model.AuditEntry, int64, error) {
+	var total int64
+	if err := r.filtered(filter).Model(&model.AuditEntry{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []model.AuditEntry
+	err := r.filtered(filter).Order("id DESC").Offset(offset).Limit(limit).Find(&entries).Error
+	return entries, total, err
+}
+
+func (r *auditRepository) filtered(filter AuditFilter) *gorm.DB {
+	query := r.db
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.TargetUserID != 0 {
+		query = query.Where("target_user_id = ?", filter.TargetUserID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
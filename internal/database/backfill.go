@@ -0,0 +1,71 @@
+package database
+
+import (
+	"github.com/lielamurs/balance-transactions/internal/hashchain"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BackfillRepository interface {
+	GetUserIDsToBackfill() ([]uint64, error)
+	GetUserForUpdate(tx *gorm.DB, userID uint64) (*model.User, error)
+	GetTransactionsNewestFirst(tx *gorm.DB, userID uint64) ([]model.Transaction, error)
+	UpdateTransactionChain(tx *gorm.DB, transaction model.Transaction) error
+	GetDB() *gorm.DB
+}
+
+type backfillRepository struct {
+	db *gorm.DB
+}
+
+func NewBackfillRepository() BackfillRepository {
+	return &backfillRepository{
+		db: GetDB(),
+	}
+}
+
+// GetUserIDsToBackfill returns every user with transactions that were
+// stored before balances were recorded or linked at an older chain version.
+func (r *backfillRepository) GetUserIDsToBackfill() ([]uint64, error) {
+	var userIDs []uint64
+	err := r.db.Model(&model.Transaction{}).
+		Where("balance_before IS NULL OR balance_after IS NULL OR chain_version < ?", hashchain.Version).
+		Distinct("user_id").
+		Order("user_id").
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+func (r *backfillRepository) GetUserForUpdate(tx *gorm.DB, userID uint64) (*model.User, error) {
+	var user model.User
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&user).Error
+	return &user, err
+}
+
+// GetTransactionsNewestFirst returns the user's transactions in reverse
+// booking order. IDs are assigned under the user row lock, so they follow the
+// order the transactions were applied in.
+func (r *backfillRepository) GetTransactionsNewestFirst(tx *gorm.DB, userID uint64) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	err := tx.Where("user_id = ?", userID).Order("id DESC").Find(&transactions).Error
+	return transactions, err
+}
+
+// UpdateTransactionChain stores the balances and chain fields of a
+// backfilled transaction.
+func (r *backfillRepository) UpdateTransactionChain(tx *gorm.DB, transaction model.Transaction) error {
+	return tx.Model(&model.Transaction{}).
+		Where("id = ?", transaction.ID).
+		Updates(map[string]interface{}{
+			"balance_before": transaction.BalanceBefore,
+			"balance_after":  transaction.BalanceAfter,
+			"chain_version":  transaction.ChainVersion,
+			"previous_hash":  transaction.PreviousHash,
+			"hash":           transaction.Hash,
+		}).Error
+}
+
+func (r *backfillRepository) GetDB() *gorm.DB {
+	return r.db
+}
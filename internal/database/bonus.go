@@ -0,0 +1,91 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BonusRepository interface {
+	GetBonuses(userID uint64) ([]model.Bonus, error)
+	GetActiveBonusesForUpdate(tx *gorm.DB, userID uint64) ([]model.Bonus, error)
+	GetActiveBonusesUnlocked(tx *gorm.DB, userID uint64) ([]model.Bonus, error)
+	CreateBonus(tx *gorm.DB, bonus *model.Bonus) error
+	UpdateBonus(tx *gorm.DB, bonus *model.Bonus) error
+	ListUsersWithExpiredBonuses(now time.Time, limit int) ([]uint64, error)
+	GetBonusForUpdate(tx *gorm.DB, bonusID uint64) (*model.Bonus, error)
+	CreateBonusWager(tx *gorm.DB, wager *model.BonusWager) error
+	GetTransactionBonusWagers(tx *gorm.DB, transactionID uint64) ([]model.BonusWager, error)
+}
+
+type bonusRepository struct {
+	db *gorm.DB
+}
+
+func NewBonusRepository() BonusRepository {
+	return &bonusRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *bonusRepository) GetBonuses(userID uint64) ([]model.Bonus, error) {
+	var bonuses []model.Bonus
+	err := r.db.Where("user_id = ?", userID).Order("id").Find(&bonuses).Error
+	return bonuses, err
+}
+
+func (r *bonusRepository) GetActiveBonusesForUpdate(tx *gorm.DB, userID uint64) ([]model.Bonus, error) {
+	var bonuses []model.Bonus
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND status = ?", userID, model.BonusActive).
+		Order("id").
+		Find(&bonuses).Error
+	return bonuses, err
+}
+
+func (r *bonusRepository) GetActiveBonusesUnlocked(tx *gorm.DB, userID uint64) ([]model.Bonus, error) {
+	var bonuses []model.Bonus
+	err := tx.Where("user_id = ? AND status = ?", userID, model.BonusActive).
+		Order("id").
+		Find(&bonuses).Error
+	return bonuses, err
+}
+
+func (r *bonusRepository) CreateBonus(tx *gorm.DB, bonus *model.Bonus) error {
+	return tx.Create(bonus).Error
+}
+
+func (r *bonusRepository) UpdateBonus(tx *gorm.DB, bonus *model.Bonus) error {
+	return tx.Save(bonus).Error
+}
+
+// ListUsersWithExpiredBonuses returns the users with active bonuses whose
+// expiry has passed.
+func (r *bonusRepository) ListUsersWithExpiredBonuses(now time.Time, limit int) ([]uint64, error) {
+	var userIDs []uint64
+	err := r.db.Model(&model.Bonus{}).
+		Where("status = ? AND expires_at <= ?", model.BonusActive, now).
+		Distinct("user_id").
+		Order("user_id").
+		Limit(limit).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+func (r *bonusRepository) GetBonusForUpdate(tx *gorm.DB, bonusID uint64) (*model.Bonus, error) {
+	var bonus model.Bonus
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", bonusID).First(&bonus).Error
+	return &bonus, err
+}
+
+func (r *bonusRepository) CreateBonusWager(tx *gorm.DB, wager *model.BonusWager) error {
+	return tx.Create(wager).Error
+}
+
+func (r *bonusRepository) GetTransactionBonusWagers(tx *gorm.DB, transactionID uint64) ([]model.BonusWager, error) {
+	var wagers []model.BonusWager
+	err := tx.Where("transaction_id = ?", transactionID).Order("id").Find(&wagers).Error
+	return wagers, err
+}
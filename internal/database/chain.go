@@ -0,0 +1,56 @@
+package database
+
+import (
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+)
+
+type ChainRepository interface {
+	UserExists(userID uint64) (bool, error)
+	GetChainUserIDs() ([]uint64, error)
+	EachChainTransaction(userID uint64, fn func(model.Transaction) error) error
+}
+
+type chainRepository struct {
+	db *gorm.DB
+}
+
+func NewChainRepository() ChainRepository {
+	return &chainRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *chainRepository) UserExists(userID uint64) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.User{}).Where("id = ?", userID).Count(&count).Error
+	return count > 0, err
+}
+
+// GetChainUserIDs returns every user that has transactions.
+func (r *chainRepository) GetChainUserIDs() ([]uint64, error) {
+	var userIDs []uint64
+	err := r.db.Model(&model.Transaction{}).Distinct("user_id").Order("user_id").Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// EachChainTransaction calls fn for every transaction of the user in chain
+// order, reading rows from a cursor instead of loading them all.
+func (r *chainRepository) EachChainTransaction(userID uint64, fn func(model.Transaction) error) error {
+	rows, err := r.db.Model(&model.Transaction{}).Where("user_id = ?", userID).Order("id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transaction model.Transaction
+		if err := r.db.ScanRows(rows, &transaction); err != nil {
+			return err
+		}
+		if err := fn(transaction); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
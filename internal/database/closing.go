@@ -0,0 +1,148 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// closingLockKey is the advisory lock that orders bookings against day
+// closings: every booking holds it shared until it commits, a closing holds
+// it exclusively.
+const closingLockKey = 4127301
+
+// ClosingBalanceSummary adds up the per-user balances of a closing.
+type ClosingBalanceSummary struct {
+	Users          int64
+	OpeningBalance string
+	ClosingBalance string
+}
+
+type ClosingRepository interface {
+	GetLastClosing() (*model.DailyClosing, error)
+	CloseDay(closing *model.DailyClosing) (bool, error)
+	LockOpenPeriod(tx *gorm.DB, at time.Time) (bool, error)
+	GetClosing(businessDate time.Time) (*model.DailyClosing, error)
+	GetClosingTotals(closingID uint64) ([]model.DailyClosingTotal, error)
+	GetClosingBalances(closingID uint64, offset, limit int) ([]model.DailyClosingBalance, error)
+	SummarizeClosingBalances(closingID uint64) (*ClosingBalanceSummary, error)
+}
+
+type closingRepository struct {
+	db *gorm.DB
+}
+
+func NewClosingRepository() ClosingRepository {
+	return &closingRepository{
+		db: GetDB(),
+	}
+}
+
+// GetLastClosing returns the most recent closing, or nil when no day has
+// been closed yet.
+func (r *closingRepository) GetLastClosing() (*model.DailyClosing, error) {
+	var closings []model.DailyClosing
+	if err := r.db.Order("business_date DESC").Limit(1).Find(&closings).Error; err != nil {
+		return nil, err
+	}
+	if len(closings) == 0 {
+		return nil, nil
+	}
+	return &closings[0], nil
+}
+
+// CloseDay writes the closing of one business day together with every
+// user's opening and closing balance and the day's totals per source type
+// and state. Taking the closing lock waits for bookings in flight, so every
+// transaction created before the period end is included. It reports false
+// when the day was already closed.
+func (r *closingRepository) CloseDay(closing *model.DailyClosing) (bool, error) {
+	closed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", closingLockKey).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(closing)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		err := tx.Exec(`
+			INSERT INTO daily_closing_balances (closing_id, user_id, opening_balance, closing_balance)
+			SELECT ?, u.id,
+				u.balance - COALESCE(SUM(t.net) FILTER (WHERE t.created_at >= ?), 0),
+				u.balance - COALESCE(SUM(t.net) FILTER (WHERE t.created_at >= ?), 0)
+			FROM users u
+			LEFT JOIN (
+				SELECT user_id, created_at, CASE WHEN state = 'win' THEN amount ELSE -amount END AS net
+				FROM transactions
+				WHERE created_at >= ?
+			) t ON t.user_id = u.id
+			WHERE u.created_at < ?
+			GROUP BY u.id, u.balance`,
+			closing.ID, closing.PeriodStart, closing.PeriodEnd, closing.PeriodStart, closing.PeriodEnd).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`
+			INSERT INTO daily_closing_totals (closing_id, source_type, state, transactions, amount)
+			SELECT ?, source_type, state, COUNT(*), SUM(amount)
+			FROM transactions
+			WHERE created_at >= ? AND created_at < ?
+			GROUP BY source_type, state`,
+			closing.ID, closing.PeriodStart, closing.PeriodEnd).Error
+		if err != nil {
+			return err
+		}
+
+		closed = true
+		return nil
+	})
+	return closed, err
+}
+
+// LockOpenPeriod holds the closing lock shared until tx ends and reports
+// whether at still falls into a business day that is open.
+func (r *closingRepository) LockOpenPeriod(tx *gorm.DB, at time.Time) (bool, error) {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock_shared(?)", closingLockKey).Error; err != nil {
+		return false, err
+	}
+
+	var closed int64
+	err := tx.Model(&model.DailyClosing{}).Where("period_end > ?", at).Count(&closed).Error
+	return closed == 0, err
+}
+
+func (r *closingRepository) GetClosing(businessDate time.Time) (*model.DailyClosing, error) {
+	var closing model.DailyClosing
+	err := r.db.Where("business_date = ?", businessDate.Format("2006-01-02")).First(&closing).Error
+	return &closing, err
+}
+
+func (r *closingRepository) GetClosingTotals(closingID uint64) ([]model.DailyClosingTotal, error) {
+	var totals []model.DailyClosingTotal
+	err := r.db.Where("closing_id = ?", closingID).Order("source_type, state").Find(&totals).Error
+	return totals, err
+}
+
+func (r *closingRepository) GetClosingBalances(closingID uint64, offset, limit int) ([]model.DailyClosingBalance, error) {
+	var balances []model.DailyClosingBalance
+	err := r.db.Where("closing_id = ?", closingID).Order("user_id").Offset(offset).Limit(limit).Find(&balances).Error
+	return balances, err
+}
+
+func (r *closingRepository) SummarizeClosingBalances(closingID uint64) (*ClosingBalanceSummary, error) {
+	var summary ClosingBalanceSummary
+	err := r.db.Model(&model.DailyClosingBalance{}).
+		Select("COUNT(*) AS users, COALESCE(SUM(opening_balance), 0) AS opening_balance, COALESCE(SUM(closing_balance), 0) AS closing_balance").
+		Where("closing_id = ?", closingID).
+		Scan(&summary).Error
+	return &summary, err
+}
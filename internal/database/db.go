@@ -1,7 +1,6 @@
 package database
 
 import (
-	"fmt"
 	"log"
 	"os"
 	"time"
@@ -23,9 +22,12 @@ func Init() {
 	baseDelay := 1 * time.Second
 
 	for i := 0; i < maxRetries; i++ {
-		DB, err = gorm.Open(postgres.Open(databaseURL), &gorm.Config{})
+		DB, err = gorm.Open(postgres.Open(databaseURL), &gorm.Config{
+			NowFunc:        func() time.Time { return time.Now().UTC() },
+			TranslateError: true,
+		})
 		if err == nil {
-			fmt.Println("Database connected successfully")
+			log.Println("Database connected successfully")
 			return
 		}
 
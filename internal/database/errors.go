@@ -0,0 +1,30 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres error codes of failures that go away when the whole database
+// transaction is run again.
+const (
+	CodeSerializationFailure = "40001"
+	CodeDeadlockDetected     = "40P01"
+)
+
+// TransientErrorCode returns the Postgres error code of err when running the
+// failed database transaction again can succeed, and an empty string
+// otherwise.
+func TransientErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ""
+	}
+	switch pgErr.Code {
+	case CodeSerializationFailure, CodeDeadlockDetected:
+		return pgErr.Code
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+)
+
+type FlagRepository interface {
+	CreateFlag(tx *gorm.DB, flag *model.TransactionFlag) error
+}
+
+type flagRepository struct {
+	db *gorm.DB
+}
+
+func NewFlagRepository() FlagRepository {
+	return &flagRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *flagRepository) CreateFlag(tx *gorm.DB, flag *model.TransactionFlag) error {
+	return tx.Create(flag).Error
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+)
+
+type FraudRepository interface {
+	CountWins(tx *gorm.DB, userID uint64, sources []string, since time.Time) (int64, string, error)
+}
+
+type fraudRepository struct {
+	db *gorm.DB
+}
+
+func NewFraudRepository() FraudRepository {
+	return &fraudRepository{
+		db: GetDB(),
+	}
+}
+
+// CountWins returns the number and sum of wins since the given time, of one
+// user or of every user when userID is zero, from the given sources or from
+// all of them. Bets refunded by a round rollback are booked as wins but are
+// not winnings, so they are left out.
+func (r *fraudRepository) CountWins(tx *gorm.DB, userID uint64, sources []string, since time.Time) (int64, string, error) {
+	query := tx.Model(&model.Transaction{}).
+		Where("state = ? AND created_at >= ?", "win", since).
+		Where("transaction_id NOT LIKE ?", model.ReversalPrefix+"%")
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	if len(sources) > 0 {
+		query = query.Where("source_type IN ?", sources)
+	}
+
+	var result struct {
+		Count int64
+		Sum   string
+	}
+	err := query.Select("COUNT(*) AS count, COALESCE(SUM(amount), 0) AS sum").Scan(&result).Error
+	return result.Count, result.Sum, err
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LimitRepository interface {
+	GetLimits(userID uint64) ([]model.UserLimit, error)
+	GetLimitsForUpdate(tx *gorm.DB, userID uint64, limitType string) ([]model.UserLimit, error)
+	GetLimitForUpdate(tx *gorm.DB, userID uint64, limitType, period string) (*model.UserLimit, error)
+	SaveLimit(tx *gorm.DB, limit *model.UserLimit) error
+	DeleteLimit(tx *gorm.DB, limit *model.UserLimit) error
+	GetDB() *gorm.DB
+}
+
+type limitRepository struct {
+	db *gorm.DB
+}
+
+func NewLimitRepository() LimitRepository {
+	return &limitRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *limitRepository) GetLimits(userID uint64) ([]model.UserLimit, error) {
+	var limits []model.UserLimit
+	err := r.db.Where("user_id = ?", userID).Order("limit_type, period").Find(&limits).Error
+	return limits, err
+}
+
+func (r *limitRepository) GetLimitsForUpdate(tx *gorm.DB, userID uint64, limitType string) ([]model.UserLimit, error) {
+	var limits []model.UserLimit
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND limit_type = ?", userID, limitType).
+		Find(&limits).Error
+	return limits, err
+}
+
+func (r *limitRepository) GetLimitForUpdate(tx *gorm.DB, userID uint64, limitType, period string) (*model.UserLimit, error) {
+	var limit model.UserLimit
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND limit_type = ? AND period = ?", userID, limitType, period).
+		First(&limit).Error
+	return &limit, err
+}
+
+func (r *limitRepository) SaveLimit(tx *gorm.DB, limit *model.UserLimit) error {
+	return tx.Save(limit).Error
+}
+
+func (r *limitRepository) DeleteLimit(tx *gorm.DB, limit *model.UserLimit) error {
+	return tx.Delete(limit).Error
+}
+
+func (r *limitRepository) GetDB() *gorm.DB {
+	return r.db
+}
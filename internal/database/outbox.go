@@ -0,0 +1,68 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	CreateEvent(tx *gorm.DB, event *model.OutboxEvent) error
+	ClaimEvents(limit int, lease time.Duration) ([]model.OutboxEvent, error)
+	MarkPublished(eventID uint64) error
+	MarkFailed(eventID uint64, attempts int, lastError string, availableAt time.Time) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository() OutboxRepository {
+	return &outboxRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *outboxRepository) CreateEvent(tx *gorm.DB, event *model.OutboxEvent) error {
+	return tx.Create(event).Error
+}
+
+// ClaimEvents leases the oldest unpublished event of each aggregate so that
+// no later event of the same aggregate can be delivered before it. Leased
+// events become claimable again once the lease expires.
+func (r *outboxRepository) ClaimEvents(limit int, lease time.Duration) ([]model.OutboxEvent, error) {
+	now := time.Now().UTC()
+
+	var events []model.OutboxEvent
+	err := r.db.Raw(`
+		UPDATE outbox_events SET available_at = ?
+		WHERE id IN (
+			SELECT o.id FROM outbox_events o
+			WHERE o.published_at IS NULL
+			AND o.available_at <= ?
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox_events p
+				WHERE p.aggregate_id = o.aggregate_id
+				AND p.published_at IS NULL
+				AND p.id < o.id
+			)
+			ORDER BY o.id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), now, limit).Scan(&events).Error
+	return events, err
+}
+
+func (r *outboxRepository) MarkPublished(eventID uint64) error {
+	return r.db.Model(&model.OutboxEvent{}).Where("id = ?", eventID).Update("published_at", time.Now().UTC()).Error
+}
+
+func (r *outboxRepository) MarkFailed(eventID uint64, attempts int, lastError string, availableAt time.Time) error {
+	return r.db.Model(&model.OutboxEvent{}).Where("id = ?", eventID).Updates(map[string]interface{}{
+		"attempts":     attempts,
+		"last_error":   lastError,
+		"available_at": availableAt,
+	}).Error
+}
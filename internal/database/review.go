@@ -0,0 +1,101 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReviewRepository interface {
+	CreateReview(tx *gorm.DB, review *model.TransactionReview) error
+	ReviewExists(tx *gorm.DB, transactionID string) (bool, error)
+	GetReview(reviewID uint64) (*model.TransactionReview, error)
+	GetReviewByTransactionID(tx *gorm.DB, transactionID string) (*model.TransactionReview, error)
+	GetReviewForUpdate(tx *gorm.DB, reviewID uint64) (*model.TransactionReview, error)
+	ListReviews(status string, userID uint64, offset, limit int) ([]model.TransactionReview, int64, error)
+	ListExpiredReviews(before time.Time, limit int) ([]uint64, error)
+	SaveReview(tx *gorm.DB, review *model.TransactionReview) error
+	GetDB() *gorm.DB
+}
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository() ReviewRepository {
+	return &reviewRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *reviewRepository) CreateReview(tx *gorm.DB, review *model.TransactionReview) error {
+	return tx.Create(review).Error
+}
+
+func (r *reviewRepository) ReviewExists(tx *gorm.DB, transactionID string) (bool, error) {
+	var count int64
+	err := tx.Model(&model.TransactionReview{}).Where("transaction_id = ?", transactionID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *reviewRepository) GetReview(reviewID uint64) (*model.TransactionReview, error) {
+	var review model.TransactionReview
+	err := r.db.Where("id = ?", reviewID).First(&review).Error
+	return &review, err
+}
+
+func (r *reviewRepository) GetReviewByTransactionID(tx *gorm.DB, transactionID string) (*model.TransactionReview, error) {
+	var review model.TransactionReview
+	err := tx.Where("transaction_id = ?", transactionID).First(&review).Error
+	return &review, err
+}
+
+func (r *reviewRepository) GetReviewForUpdate(tx *gorm.DB, reviewID uint64) (*model.TransactionReview, error) {
+	var review model.TransactionReview
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", reviewID).First(&review).Error
+	return &review, err
+}
+
+func (r *reviewRepository) ListReviews(status string, userID uint64, offset, limit int) ([]model.TransactionReview, int64, error) {
+	var total int64
+	if err := r.filtered(status, userID).Model(&model.TransactionReview{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reviews []model.TransactionReview
+	err := r.filtered(status, userID).Order("id").Offset(offset).Limit(limit).Find(&reviews).Error
+	return reviews, total, err
+}
+
+// ListExpiredReviews returns the IDs of reviews still pending that were held
+// before the given time, oldest first.
+func (r *reviewRepository) ListExpiredReviews(before time.Time, limit int) ([]uint64, error) {
+	var ids []uint64
+	err := r.db.Model(&model.TransactionReview{}).
+		Where("status = ? AND created_at < ?", model.ReviewPending, before).
+		Order("created_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *reviewRepository) SaveReview(tx *gorm.DB, review *model.TransactionReview) error {
+	return tx.Save(review).Error
+}
+
+func (r *reviewRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *reviewRepository) filtered(status string, userID uint64) *gorm.DB {
+	query := r.db
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	return query
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoundRepository interface {
+	GetRound(roundID string) (*model.GameRound, error)
+	GetRoundForUpdate(tx *gorm.DB, roundID string) (*model.GameRound, error)
+	CreateRound(tx *gorm.DB, round *model.GameRound) error
+	UpdateRoundStatus(tx *gorm.DB, roundID uint64, status string, closedAt time.Time) error
+	GetRoundTransactions(tx *gorm.DB, roundID uint64) ([]model.Transaction, error)
+	GetTransactionWalletEntries(tx *gorm.DB, transactionID uint64) ([]model.WalletEntry, error)
+	GetDB() *gorm.DB
+}
+
+type roundRepository struct {
+	db *gorm.DB
+}
+
+func NewRoundRepository() RoundRepository {
+	return &roundRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *roundRepository) GetRound(roundID string) (*model.GameRound, error) {
+	var round model.GameRound
+	err := r.db.Where("round_id = ?", roundID).First(&round).Error
+	return &round, err
+}
+
+func (r *roundRepository) GetRoundForUpdate(tx *gorm.DB, roundID string) (*model.GameRound, error) {
+	var round model.GameRound
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("round_id = ?", roundID).First(&round).Error
+	return &round, err
+}
+
+func (r *roundRepository) CreateRound(tx *gorm.DB, round *model.GameRound) error {
+	return tx.Create(round).Error
+}
+
+func (r *roundRepository) UpdateRoundStatus(tx *gorm.DB, roundID uint64, status string, closedAt time.Time) error {
+	return tx.Model(&model.GameRound{}).Where("id = ?", roundID).Updates(map[string]interface{}{
+		"status":    status,
+		"closed_at": closedAt,
+	}).Error
+}
+
+func (r *roundRepository) GetRoundTransactions(tx *gorm.DB, roundID uint64) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	err := tx.Where("round_id = ?", roundID).Order("id").Find(&transactions).Error
+	return transactions, err
+}
+
+// GetTransactionWalletEntries returns the wallet movements a transaction made
+// itself, leaving out bonus conversions that only happened alongside it.
+func (r *roundRepository) GetTransactionWalletEntries(tx *gorm.DB, transactionID uint64) ([]model.WalletEntry, error) {
+	var entries []model.WalletEntry
+	err := tx.Where("transaction_id = ? AND bonus_id IS NULL", transactionID).Order("id").Find(&entries).Error
+	return entries, err
+}
+
+func (r *roundRepository) GetDB() *gorm.DB {
+	return r.db
+}
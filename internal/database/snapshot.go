@@ -0,0 +1,110 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+)
+
+type SnapshotRepository interface {
+	CreateSnapshots() (int64, error)
+	GetUser(tx *gorm.DB, userID uint64) (*model.User, error)
+	GetSnapshotAtOrBefore(tx *gorm.DB, userID, transactionID uint64) (*model.BalanceSnapshot, error)
+	GetSnapshotAfter(tx *gorm.DB, userID, transactionID uint64) (*model.BalanceSnapshot, error)
+	NetTransactions(tx *gorm.DB, userID uint64, after, through *uint64) (string, error)
+	GetLastTransaction(tx *gorm.DB, userID uint64, at time.Time) (*model.Transaction, error)
+	GetDB() *gorm.DB
+}
+
+type snapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewSnapshotRepository() SnapshotRepository {
+	return &snapshotRepository{
+		db: GetDB(),
+	}
+}
+
+// CreateSnapshots snapshots the balance of every user whose latest
+// transaction is not snapshotted yet. A single statement reads the balance
+// and the latest transaction, so both come from the same committed state.
+// Transactions are ordered by ID, the order they were applied in.
+func (r *snapshotRepository) CreateSnapshots() (int64, error) {
+	result := r.db.Exec(`
+		INSERT INTO balance_snapshots (user_id, transaction_id, balance, as_of)
+		SELECT u.id, t.id, u.balance, t.created_at
+		FROM users u
+		JOIN LATERAL (
+			SELECT id, created_at FROM transactions
+			WHERE user_id = u.id
+			ORDER BY id DESC
+			LIMIT 1
+		) t ON TRUE
+		WHERE NOT EXISTS (
+			SELECT 1 FROM balance_snapshots s
+			WHERE s.user_id = u.id AND s.transaction_id = t.id
+		)
+		ON CONFLICT (user_id, transaction_id) DO NOTHING`)
+	return result.RowsAffected, result.Error
+}
+
+func (r *snapshotRepository) GetUser(tx *gorm.DB, userID uint64) (*model.User, error) {
+	var user model.User
+	err := tx.Where("id = ?", userID).First(&user).Error
+	return &user, err
+}
+
+// GetSnapshotAtOrBefore returns the latest snapshot taken at or before the
+// transaction with transactionID was applied.
+func (r *snapshotRepository) GetSnapshotAtOrBefore(tx *gorm.DB, userID, transactionID uint64) (*model.BalanceSnapshot, error) {
+	var snapshot model.BalanceSnapshot
+	err := tx.Where("user_id = ? AND transaction_id <= ?", userID, transactionID).
+		Order("transaction_id DESC").
+		First(&snapshot).Error
+	return &snapshot, err
+}
+
+// GetSnapshotAfter returns the earliest snapshot taken after the transaction
+// with transactionID was applied.
+func (r *snapshotRepository) GetSnapshotAfter(tx *gorm.DB, userID, transactionID uint64) (*model.BalanceSnapshot, error) {
+	var snapshot model.BalanceSnapshot
+	err := tx.Where("user_id = ? AND transaction_id > ?", userID, transactionID).
+		Order("transaction_id").
+		First(&snapshot).Error
+	return &snapshot, err
+}
+
+// NetTransactions sums wins minus losses of the user's transactions with an
+// ID above after and up to and including through. A nil bound leaves that
+// end of the range open.
+func (r *snapshotRepository) NetTransactions(tx *gorm.DB, userID uint64, after, through *uint64) (string, error) {
+	query := tx.Model(&model.Transaction{}).
+		Select("COALESCE(SUM(CASE WHEN state = 'win' THEN amount ELSE -amount END), 0)").
+		Where("user_id = ?", userID)
+	if after != nil {
+		query = query.Where("id > ?", *after)
+	}
+	if through != nil {
+		query = query.Where("id <= ?", *through)
+	}
+
+	var net string
+	err := query.Scan(&net).Error
+	return net, err
+}
+
+// GetLastTransaction returns the last transaction applied among those created
+// at or before at.
+func (r *snapshotRepository) GetLastTransaction(tx *gorm.DB, userID uint64, at time.Time) (*model.Transaction, error) {
+	var transaction model.Transaction
+	err := tx.Where("user_id = ? AND created_at <= ?", userID, at).
+		Order("id DESC").
+		First(&transaction).Error
+	return &transaction, err
+}
+
+func (r *snapshotRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+)
+
+type StatementRepository interface {
+	GetUser(tx *gorm.DB, userID uint64) (*model.User, error)
+	LastTransactionIDBefore(tx *gorm.DB, userID uint64, at time.Time) (uint64, error)
+	NetTransactionsAfter(tx *gorm.DB, userID, transactionID uint64) (string, error)
+	EachTransaction(tx *gorm.DB, userID, afterID, throughID uint64, fn func(model.Transaction) error) error
+	GetDB() *gorm.DB
+}
+
+type statementRepository struct {
+	db *gorm.DB
+}
+
+func NewStatementRepository() StatementRepository {
+	return &statementRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *statementRepository) GetUser(tx *gorm.DB, userID uint64) (*model.User, error) {
+	var user model.User
+	err := tx.Where("id = ?", userID).First(&user).Error
+	return &user, err
+}
+
+// LastTransactionIDBefore returns the ID of the last transaction applied
+// among those of the user created before at, or 0 if there is none.
+// Transactions are created in the order of their IDs, so it marks where at
+// falls in the user's history.
+func (r *statementRepository) LastTransactionIDBefore(tx *gorm.DB, userID uint64, at time.Time) (uint64, error) {
+	var id uint64
+	err := tx.Model(&model.Transaction{}).
+		Select("COALESCE(MAX(id), 0)").
+		Where("user_id = ? AND created_at < ?", userID, at).
+		Scan(&id).Error
+	return id, err
+}
+
+// NetTransactionsAfter sums wins minus losses of the user's transactions
+// with an ID above transactionID.
+func (r *statementRepository) NetTransactionsAfter(tx *gorm.DB, userID, transactionID uint64) (string, error) {
+	var net string
+	err := tx.Model(&model.Transaction{}).
+		Select("COALESCE(SUM(CASE WHEN state = 'win' THEN amount ELSE -amount END), 0)").
+		Where("user_id = ? AND id > ?", userID, transactionID).
+		Scan(&net).Error
+	return net, err
+}
+
+// EachTransaction calls fn for every transaction of the user with an ID above
+// afterID and up to and including throughID, in the order they were applied,
+// reading rows from a cursor instead of loading them all.
+func (r *statementRepository) EachTransaction(tx *gorm.DB, userID, afterID, throughID uint64, fn func(model.Transaction) error) error {
+	rows, err := tx.Model(&model.Transaction{}).
+		Where("user_id = ? AND id > ? AND id <= ?", userID, afterID, throughID).
+		Order("id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transaction model.Transaction
+		if err := tx.ScanRows(rows, &transaction); err != nil {
+			return err
+		}
+		if err := fn(transaction); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *statementRepository) GetDB() *gorm.DB {
+	return r.db
+}
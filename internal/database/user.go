@@ -1,16 +1,28 @@
 package database
 
 import (
+	"time"
+
 	"github.com/lielamurs/balance-transactions/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
 	GetUser(userID uint64) (*model.User, error)
+	ListUsers(offset, limit int) ([]model.User, int64, error)
+	CreateUser(tx *gorm.DB, user *model.User) error
+	CreateUserIfMissing(tx *gorm.DB, user *model.User) error
+	AdvanceUserIDSequence(tx *gorm.DB, userID uint64) error
 	GetUserForUpdate(tx *gorm.DB, userID uint64) (*model.User, error)
+	GetUserUnlocked(tx *gorm.DB, userID uint64) (*model.User, error)
 	UpdateUserBalance(tx *gorm.DB, userID uint64, newBalance string) error
+	UpdateUserBalanceIfVersion(tx *gorm.DB, userID uint64, version int64, newBalance string) (bool, error)
 	TransactionExists(tx *gorm.DB, transactionID string) (bool, error)
+	GetTransaction(tx *gorm.DB, transactionID string) (*model.Transaction, error)
 	CreateTransaction(tx *gorm.DB, transaction *model.Transaction) error
+	GetLastTransactionHash(tx *gorm.DB, userID uint64) (string, error)
+	SumTransactions(tx *gorm.DB, userID uint64, sourceType, state string, since time.Time) (string, error)
 	GetDB() *gorm.DB
 }
 
@@ -30,14 +42,67 @@ func (r *userRepository) GetUser(userID uint64) (*model.User, error) {
 	return &user, err
 }
 
+func (r *userRepository) ListUsers(offset, limit int) ([]model.User, int64, error) {
+	var total int64
+	if err := r.db.Model(&model.User{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []model.User
+	err := r.db.Order("id").Offset(offset).Limit(limit).Find(&users).Error
+	return users, total, err
+}
+
+func (r *userRepository) CreateUser(tx *gorm.DB, user *model.User) error {
+	return tx.Create(user).Error
+}
+
+func (r *userRepository) CreateUserIfMissing(tx *gorm.DB, user *model.User) error {
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(user).Error
+}
+
+// AdvanceUserIDSequence moves the user ID sequence past an explicitly chosen
+// ID so that generated IDs never collide with it.
+func (r *userRepository) AdvanceUserIDSequence(tx *gorm.DB, userID uint64) error {
+	return tx.Exec(
+		"SELECT setval('users_id_seq', GREATEST((SELECT last_value FROM users_id_seq), ?))",
+		userID,
+	).Error
+}
+
 func (r *userRepository) GetUserForUpdate(tx *gorm.DB, userID uint64) (*model.User, error) {
 	var user model.User
-	err := tx.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", userID).First(&user).Error
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&user).Error
+	return &user, err
+}
+
+// GetUserUnlocked reads the user without locking the row. Writers check the
+// version they read with UpdateUserBalanceIfVersion instead.
+func (r *userRepository) GetUserUnlocked(tx *gorm.DB, userID uint64) (*model.User, error) {
+	var user model.User
+	err := tx.Where("id = ?", userID).First(&user).Error
 	return &user, err
 }
 
+// UpdateUserBalance writes the balance of a user whose row the caller has
+// locked. The version is bumped so optimistic writers that read the row
+// before notice the change.
 func (r *userRepository) UpdateUserBalance(tx *gorm.DB, userID uint64, newBalance string) error {
-	return tx.Model(&model.User{}).Where("id = ?", userID).Update("balance", newBalance).Error
+	return tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"balance": newBalance,
+		"version": gorm.Expr("version + 1"),
+	}).Error
+}
+
+// UpdateUserBalanceIfVersion writes the balance only if the user's version is
+// still the one that was read, and reports whether it did. A successful update
+// holds the row lock until the transaction ends.
+func (r *userRepository) UpdateUserBalanceIfVersion(tx *gorm.DB, userID uint64, version int64, newBalance string) (bool, error) {
+	result := tx.Model(&model.User{}).Where("id = ? AND version = ?", userID, version).Updates(map[string]interface{}{
+		"balance": newBalance,
+		"version": gorm.Expr("version + 1"),
+	})
+	return result.RowsAffected == 1, result.Error
 }
 
 func (r *userRepository) TransactionExists(tx *gorm.DB, transactionID string) (bool, error) {
@@ -46,10 +111,40 @@ func (r *userRepository) TransactionExists(tx *gorm.DB, transactionID string) (b
 	return count > 0, err
 }
 
+func (r *userRepository) GetTransaction(tx *gorm.DB, transactionID string) (*model.Transaction, error) {
+	var transaction model.Transaction
+	err := tx.Where("transaction_id = ?", transactionID).First(&transaction).Error
+	return &transaction, err
+}
+
 func (r *userRepository) CreateTransaction(tx *gorm.DB, transaction *model.Transaction) error {
 	return tx.Create(transaction).Error
 }
 
+// GetLastTransactionHash returns the chain hash of the user's newest
+// transaction, or an empty string when the user has none.
+func (r *userRepository) GetLastTransactionHash(tx *gorm.DB, userID uint64) (string, error) {
+	var hashes []string
+	err := tx.Model(&model.Transaction{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(1).
+		Pluck("hash", &hashes).Error
+	if err != nil || len(hashes) == 0 {
+		return "", err
+	}
+	return hashes[0], nil
+}
+
+func (r *userRepository) SumTransactions(tx *gorm.DB, userID uint64, sourceType, state string, since time.Time) (string, error) {
+	var sum string
+	err := tx.Model(&model.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND source_type = ? AND state = ? AND created_at >= ?", userID, sourceType, state, since).
+		Scan(&sum).Error
+	return sum, err
+}
+
 func (r *userRepository) GetDB() *gorm.DB {
 	return r.db
 }
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WalletRepository interface {
+	GetWallets(userID uint64) ([]model.Wallet, error)
+	GetWalletsForUpdate(tx *gorm.DB, userID uint64) ([]model.Wallet, error)
+	GetWalletsUnlocked(tx *gorm.DB, userID uint64) ([]model.Wallet, error)
+	UpsertWalletBalance(tx *gorm.DB, userID uint64, walletType string, balance string) error
+	CreateWalletEntry(tx *gorm.DB, entry *model.WalletEntry) error
+}
+
+type walletRepository struct {
+	db *gorm.DB
+}
+
+func NewWalletRepository() WalletRepository {
+	return &walletRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *walletRepository) GetWallets(userID uint64) ([]model.Wallet, error) {
+	var wallets []model.Wallet
+	err := r.db.Where("user_id = ?", userID).Find(&wallets).Error
+	return wallets, err
+}
+
+func (r *walletRepository) GetWalletsForUpdate(tx *gorm.DB, userID uint64) ([]model.Wallet, error) {
+	var wallets []model.Wallet
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).Find(&wallets).Error
+	return wallets, err
+}
+
+func (r *walletRepository) GetWalletsUnlocked(tx *gorm.DB, userID uint64) ([]model.Wallet, error) {
+	var wallets []model.Wallet
+	err := tx.Where("user_id = ?", userID).Find(&wallets).Error
+	return wallets, err
+}
+
+func (r *walletRepository) UpsertWalletBalance(tx *gorm.DB, userID uint64, walletType string, balance string) error {
+	wallet := &model.Wallet{
+		UserID:  userID,
+		Type:    walletType,
+		Balance: balance,
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"balance", "updated_at"}),
+	}).Create(wallet).Error
+}
+
+func (r *walletRepository) CreateWalletEntry(tx *gorm.DB, entry *model.WalletEntry) error {
+	return tx.Create(entry).Error
+}
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(tx *gorm.DB, subscription *model.WebhookSubscription) error
+	GetSubscription(subscriptionID uint64) (*model.WebhookSubscription, error)
+	GetSubscriptionForUpdate(tx *gorm.DB, subscriptionID uint64) (*model.WebhookSubscription, error)
+	GetSubscriptions() ([]model.WebhookSubscription, error)
+	GetActiveSubscriptions() ([]model.WebhookSubscription, error)
+	DeactivateSubscription(tx *gorm.DB, subscriptionID uint64) (bool, error)
+	CreateDelivery(delivery *model.WebhookDelivery) error
+	GetDeliveryForUpdate(tx *gorm.DB, deliveryID uint64) (*model.WebhookDelivery, error)
+	GetDeliveries(status string, subscriptionID uint64, limit int) ([]model.WebhookDelivery, error)
+	ClaimDeliveries(limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+	MarkDelivered(deliveryID uint64, statusCode int) error
+	MarkFailed(deliveryID uint64, attempts int, statusCode *int, lastError string, nextAttemptAt time.Time, dead bool) error
+	ResetDelivery(tx *gorm.DB, deliveryID uint64) error
+	GetDB() *gorm.DB
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository() WebhookRepository {
+	return &webhookRepository{
+		db: GetDB(),
+	}
+}
+
+func (r *webhookRepository) CreateSubscription(tx *gorm.DB, subscription *model.WebhookSubscription) error {
+	return tx.Create(subscription).Error
+}
+
+func (r *webhookRepository) GetSubscription(subscriptionID uint64) (*model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+	err := r.db.Where("id = ?", subscriptionID).First(&subscription).Error
+	return &subscription, err
+}
+
+func (r *webhookRepository) GetSubscriptionForUpdate(tx *gorm.DB, subscriptionID uint64) (*model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", subscriptionID).First(&subscription).Error
+	return &subscription, err
+}
+
+func (r *webhookRepository) GetSubscriptions() ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	err := r.db.Order("id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) GetActiveSubscriptions() ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	err := r.db.Where("active").Order("id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) DeactivateSubscription(tx *gorm.DB, subscriptionID uint64) (bool, error) {
+	result := tx.Model(&model.WebhookSubscription{}).Where("id = ? AND active", subscriptionID).Update("active", false)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CreateDelivery enqueues a delivery unless the subscription already has one
+// for the same event, which keeps redelivered outbox events idempotent.
+func (r *webhookRepository) CreateDelivery(delivery *model.WebhookDelivery) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "dedupe_key"}},
+		DoNothing: true,
+	}).Create(delivery).Error
+}
+
+func (r *webhookRepository) GetDeliveryForUpdate(tx *gorm.DB, deliveryID uint64) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", deliveryID).First(&delivery).Error
+	return &delivery, err
+}
+
+func (r *webhookRepository) GetDeliveries(status string, subscriptionID uint64, limit int) ([]model.WebhookDelivery, error) {
+	query := r.db.Order("id DESC").Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if subscriptionID != 0 {
+		query = query.Where("subscription_id = ?", subscriptionID)
+	}
+
+	var deliveries []model.WebhookDelivery
+	err := query.Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDeliveries leases due pending deliveries so concurrent dispatchers do
+// not send the same delivery twice while it is in flight.
+func (r *webhookRepository) ClaimDeliveries(limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	now := time.Now().UTC()
+
+	var deliveries []model.WebhookDelivery
+	err := r.db.Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), now, model.DeliveryPending, now, limit).Scan(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookRepository) MarkDelivered(deliveryID uint64, statusCode int) error {
+	now := time.Now().UTC()
+	return r.db.Model(&model.WebhookDelivery{}).Where("id = ?", deliveryID).Updates(map[string]interface{}{
+		"status":           model.DeliveryDelivered,
+		"attempts":         gorm.Expr("attempts + 1"),
+		"last_status_code": statusCode,
+		"last_error":       nil,
+		"delivered_at":     now,
+	}).Error
+}
+
+func (r *webhookRepository) MarkFailed(deliveryID uint64, attempts int, statusCode *int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := model.DeliveryPending
+	if dead {
+		status = model.DeliveryDead
+	}
+	return r.db.Model(&model.WebhookDelivery{}).Where("id = ?", deliveryID).Updates(map[string]interface{}{
+		"status":           status,
+		"attempts":         attempts,
+		"last_status_code": statusCode,
+		"last_error":       lastError,
+		"next_attempt_at":  nextAttemptAt,
+	}).Error
+}
+
+func (r *webhookRepository) ResetDelivery(tx *gorm.DB, deliveryID uint64) error {
+	return tx.Model(&model.WebhookDelivery{}).Where("id = ?", deliveryID).Updates(map[string]interface{}{
+		"status":          model.DeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now().UTC(),
+	}).Error
+}
+
+func (r *webhookRepository) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+type (
+	ChangeStatusRequest struct {
+		Status        string     `json:"status" validate:"required,oneof=active suspended self_excluded closed"`
+		Reason        string     `json:"reason" validate:"required"`
+		ExcludedUntil *time.Time `json:"excludedUntil,omitempty"`
+	}
+
+	AccountStatusResponse struct {
+		UserID        uint64     `json:"userId"`
+		Status        string     `json:"status"`
+		ExcludedUntil *time.Time `json:"excludedUntil,omitempty"`
+	}
+
+	StatusChangeResponse struct {
+		ID            uint64     `json:"id"`
+		FromStatus    string     `json:"fromStatus"`
+		ToStatus      string     `json:"toStatus"`
+		Reason        string     `json:"reason"`
+		ExcludedUntil *time.Time `json:"excludedUntil,omitempty"`
+		Actor         string     `json:"actor"`
+		CreatedAt     time.Time  `json:"createdAt"`
+	}
+
+	StatusHistoryResponse struct {
+		UserID  uint64                 `json:"userId"`
+		Changes []StatusChangeResponse `json:"changes"`
+	}
+)
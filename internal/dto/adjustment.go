@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+type (
+	CreateAdjustmentRequest struct {
+		Direction  string `json:"direction" validate:"required,oneof=credit debit"`
+		Amount     string `json:"amount" validate:"amount"`
+		ReasonCode string `json:"reasonCode" validate:"required,oneof=correction goodwill chargeback fraud other"`
+		Note       string `json:"note,omitempty"`
+	}
+
+	ReviewAdjustmentRequest struct {
+		Note string `json:"note,omitempty"`
+	}
+
+	AdjustmentResponse struct {
+		ID            uint64     `json:"id"`
+		UserID        uint64     `json:"userId"`
+		Direction     string     `json:"direction"`
+		Amount        string     `json:"amount"`
+		ReasonCode    string     `json:"reasonCode"`
+		Note          string     `json:"note,omitempty"`
+		Status        string     `json:"status"`
+		RequestedBy   string     `json:"requestedBy"`
+		ReviewedBy    *string    `json:"reviewedBy,omitempty"`
+		ReviewNote    *string    `json:"reviewNote,omitempty"`
+		ReviewedAt    *time.Time `json:"reviewedAt,omitempty"`
+		TransactionID *string    `json:"transactionId,omitempty"`
+		CreatedAt     time.Time  `json:"createdAt"`
+	}
+
+	AdjustmentListResponse struct {
+		Adjustments []AdjustmentResponse `json:"adjustments"`
+		Page        int                  `json:"page"`
+		PageSize    int                  `json:"pageSize"`
+		Total       int64                `json:"total"`
+	}
+)
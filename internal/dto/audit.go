@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type (
+	AuditEntryResponse struct {
+		ID           uint64          `json:"id"`
+		Actor        string          `json:"actor"`
+		Action       string          `json:"action"`
+		TargetUserID *uint64         `json:"targetUserId,omitempty"`
+		Resource     string          `json:"resource"`
+		Before       json.RawMessage `json:"before"`
+		After        json.RawMessage `json:"after"`
+		RequestID    string          `json:"requestId,omitempty"`
+		CreatedAt    time.Time       `json:"createdAt"`
+	}
+
+	AuditListResponse struct {
+		Entries  []AuditEntryResponse `json:"entries"`
+		Page     int                  `json:"page"`
+		PageSize int                  `json:"pageSize"`
+		Total    int64                `json:"total"`
+	}
+)
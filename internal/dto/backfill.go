@@ -0,0 +1,9 @@
+package dto
+
+type (
+	BalanceBackfillResponse struct {
+		UserID     uint64 `json:"userId"`
+		Backfilled int    `json:"backfilled"`
+		Relinked   int    `json:"relinked"`
+	}
+)
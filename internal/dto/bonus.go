@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type (
+	BonusProgressResponse struct {
+		UserID  uint64          `json:"userId"`
+		Bonuses []BonusProgress `json:"bonuses"`
+	}
+
+	BonusProgress struct {
+		ID                uint64    `json:"id"`
+		Amount            string    `json:"amount"`
+		WageringRequired  string    `json:"wageringRequired"`
+		Wagered           string    `json:"wagered"`
+		WageringRemaining string    `json:"wageringRemaining"`
+		Progress          float64   `json:"progress"`
+		Status            string    `json:"status"`
+		ExpiresAt         time.Time `json:"expiresAt"`
+		CreatedAt         time.Time `json:"createdAt"`
+	}
+)
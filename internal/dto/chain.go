@@ -0,0 +1,18 @@
+package dto
+
+type (
+	ChainVerificationResponse struct {
+		UserID     uint64      `json:"userId"`
+		Intact     bool        `json:"intact"`
+		Verified   int         `json:"verified"`
+		BrokenLink *ChainBreak `json:"brokenLink,omitempty"`
+	}
+
+	ChainBreak struct {
+		ID            uint64 `json:"id"`
+		TransactionID string `json:"transactionId"`
+		Reason        string `json:"reason"`
+		ExpectedHash  string `json:"expectedHash"`
+		ActualHash    string `json:"actualHash"`
+	}
+)
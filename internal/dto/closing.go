@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+type (
+	ClosingReportResponse struct {
+		BusinessDate   string           `json:"businessDate"`
+		TimeZone       string           `json:"timeZone"`
+		PeriodStart    time.Time        `json:"periodStart"`
+		PeriodEnd      time.Time        `json:"periodEnd"`
+		ClosedAt       time.Time        `json:"closedAt"`
+		Users          int64            `json:"users"`
+		OpeningBalance string           `json:"openingBalance"`
+		ClosingBalance string           `json:"closingBalance"`
+		Totals         []ClosingTotal   `json:"totals"`
+		Balances       []ClosingBalance `json:"balances"`
+		Page           int              `json:"page"`
+		PageSize       int              `json:"pageSize"`
+	}
+
+	ClosingTotal struct {
+		SourceType   string `json:"sourceType"`
+		State        string `json:"state"`
+		Transactions int64  `json:"transactions"`
+		Amount       string `json:"amount"`
+	}
+
+	ClosingBalance struct {
+		UserID         uint64 `json:"userId"`
+		OpeningBalance string `json:"openingBalance"`
+		ClosingBalance string `json:"closingBalance"`
+	}
+)
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+type BalanceChangedEvent struct {
+	UserID          uint64         `json:"userId"`
+	TransactionID   string         `json:"transactionId"`
+	State           string         `json:"state"`
+	Amount          string         `json:"amount"`
+	SourceType      string         `json:"sourceType"`
+	PreviousBalance string         `json:"previousBalance"`
+	Balance         string         `json:"balance"`
+	Wallets         WalletBalances `json:"wallets"`
+	OccurredAt      time.Time      `json:"occurredAt"`
+}
+
+type TransactionEvent struct {
+	UserID        uint64    `json:"userId"`
+	TransactionID string    `json:"transactionId"`
+	State         string    `json:"state"`
+	Amount        string    `json:"amount"`
+	SourceType    string    `json:"sourceType"`
+	Balance       string    `json:"balance,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+	OccurredAt    time.Time `json:"occurredAt"`
+}
+
+type BalanceUpdate struct {
+	UserID      uint64              `json:"userId"`
+	Balance     string              `json:"balance"`
+	Wallets     WalletBalances      `json:"wallets"`
+	Transaction *TransactionSummary `json:"transaction,omitempty"`
+	OccurredAt  time.Time           `json:"occurredAt"`
+}
+
+type TransactionSummary struct {
+	TransactionID string `json:"transactionId"`
+	State         string `json:"state"`
+	Amount        string `json:"amount"`
+	SourceType    string `json:"sourceType"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type (
+	SetLimitRequest struct {
+		Amount string `json:"amount" validate:"amount"`
+	}
+
+	LimitResponse struct {
+		Type               string     `json:"type"`
+		Period             string     `json:"period"`
+		Amount             string     `json:"amount"`
+		PendingAmount      *string    `json:"pendingAmount,omitempty"`
+		PendingRemoval     bool       `json:"pendingRemoval,omitempty"`
+		PendingEffectiveAt *time.Time `json:"pendingEffectiveAt,omitempty"`
+		SetBy              string     `json:"setBy"`
+		UpdatedAt          time.Time  `json:"updatedAt"`
+	}
+
+	LimitsResponse struct {
+		UserID uint64          `json:"userId"`
+		Limits []LimitResponse `json:"limits"`
+	}
+)
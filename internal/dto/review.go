@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+type (
+	DecideReviewRequest struct {
+		Note string `json:"note,omitempty"`
+	}
+
+	ReviewResponse struct {
+		ID            uint64     `json:"id"`
+		UserID        uint64     `json:"userId"`
+		TransactionID string     `json:"transactionId"`
+		SourceType    string     `json:"sourceType"`
+		State         string     `json:"state"`
+		Amount        string     `json:"amount"`
+		RoundID       *string    `json:"roundId,omitempty"`
+		GameID        *string    `json:"gameId,omitempty"`
+		Provider      *string    `json:"provider,omitempty"`
+		CloseRound    bool       `json:"closeRound,omitempty"`
+		Rule          string     `json:"rule"`
+		Reason        string     `json:"reason"`
+		Status        string     `json:"status"`
+		ReviewedBy    *string    `json:"reviewedBy,omitempty"`
+		ReviewNote    *string    `json:"reviewNote,omitempty"`
+		ReviewedAt    *time.Time `json:"reviewedAt,omitempty"`
+		CreatedAt     time.Time  `json:"createdAt"`
+	}
+
+	ReviewListResponse struct {
+		Reviews  []ReviewResponse `json:"reviews"`
+		Page     int              `json:"page"`
+		PageSize int              `json:"pageSize"`
+		Total    int64            `json:"total"`
+	}
+)
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+type (
+	RoundResponse struct {
+		RoundID      string             `json:"roundId"`
+		UserID       uint64             `json:"userId"`
+		GameID       string             `json:"gameId"`
+		Provider     string             `json:"provider"`
+		Status       string             `json:"status"`
+		Bets         string             `json:"bets"`
+		Wins         string             `json:"wins"`
+		Net          string             `json:"net"`
+		Transactions []RoundTransaction `json:"transactions"`
+		CreatedAt    time.Time          `json:"createdAt"`
+		ClosedAt     *time.Time         `json:"closedAt,omitempty"`
+	}
+
+	RoundTransaction struct {
+		TransactionID string    `json:"transactionId"`
+		State         string    `json:"state"`
+		Amount        string    `json:"amount"`
+		BalanceBefore *string   `json:"balanceBefore,omitempty"`
+		BalanceAfter  *string   `json:"balanceAfter,omitempty"`
+		CreatedAt     time.Time `json:"createdAt"`
+	}
+)
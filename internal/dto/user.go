@@ -1,24 +1,102 @@
 package dto
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type (
 	BalanceResponse struct {
-		UserID  uint64 `json:"userId"`
-		Balance string `json:"balance"`
+		UserID  uint64         `json:"userId"`
+		Balance string         `json:"balance"`
+		Wallets WalletBalances `json:"wallets"`
+	}
+
+	HistoricalBalanceResponse struct {
+		UserID          uint64                 `json:"userId"`
+		Balance         string                 `json:"balance"`
+		At              time.Time              `json:"at"`
+		LastTransaction *HistoricalTransaction `json:"lastTransaction"`
+	}
+
+	HistoricalTransaction struct {
+		TransactionID string    `json:"transactionId"`
+		State         string    `json:"state"`
+		Amount        string    `json:"amount"`
+		SourceType    string    `json:"sourceType"`
+		BalanceBefore *string   `json:"balanceBefore,omitempty"`
+		BalanceAfter  *string   `json:"balanceAfter,omitempty"`
+		CreatedAt     time.Time `json:"createdAt"`
+	}
+
+	WalletBalances struct {
+		Cash   string `json:"cash"`
+		Bonus  string `json:"bonus"`
+		Locked string `json:"locked"`
 	}
 
 	ErrorResponse struct {
-		Error   string `json:"error"`
-		Message string `json:"message,omitempty"`
+		Error   string       `json:"error"`
+		Message string       `json:"message,omitempty"`
+		Details []FieldError `json:"details,omitempty"`
+	}
+
+	FieldError struct {
+		Field   string `json:"field"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
 	}
 
 	TransactionRequest struct {
 		State         string `json:"state" validate:"required,oneof=win lose"`
-		Amount        string `json:"amount" validate:"required"`
-		TransactionID string `json:"transactionId" validate:"required"`
+		Amount        string `json:"amount" validate:"amount"`
+		TransactionID string `json:"transactionId" validate:"required,transaction_id"`
+		RoundID       string `json:"roundId,omitempty" validate:"omitempty,transaction_id"`
+		GameID        string `json:"gameId,omitempty" validate:"required_with=RoundID,max=255"`
+		Provider      string `json:"provider,omitempty" validate:"required_with=RoundID,max=255"`
+		CloseRound    bool   `json:"closeRound,omitempty" validate:"excluded_without=RoundID"`
 	}
 
 	TransactionResponse struct {
 		Success bool   `json:"success"`
+		Status  string `json:"status,omitempty"`
 		Message string `json:"message,omitempty"`
 	}
+
+	TransactionLookupResponse struct {
+		TransactionID string     `json:"transactionId"`
+		UserID        uint64     `json:"userId"`
+		Status        string     `json:"status"`
+		State         string     `json:"state"`
+		Amount        string     `json:"amount"`
+		SourceType    string     `json:"sourceType"`
+		BalanceBefore string     `json:"balanceBefore,omitempty"`
+		BalanceAfter  string     `json:"balanceAfter,omitempty"`
+		CreatedAt     time.Time  `json:"createdAt"`
+		DecidedAt     *time.Time `json:"decidedAt,omitempty"`
+	}
+
+	CreateUserRequest struct {
+		ID          *uint64         `json:"id,omitempty"`
+		ExternalRef *string         `json:"externalRef,omitempty"`
+		Metadata    json.RawMessage `json:"metadata,omitempty"`
+	}
+
+	UserDetailsResponse struct {
+		ID            uint64          `json:"id"`
+		ExternalRef   *string         `json:"externalRef,omitempty"`
+		Metadata      json.RawMessage `json:"metadata"`
+		Balance       string          `json:"balance"`
+		Status        string          `json:"status"`
+		ExcludedUntil *time.Time      `json:"excludedUntil,omitempty"`
+		CreatedAt     time.Time       `json:"createdAt"`
+		UpdatedAt     time.Time       `json:"updatedAt"`
+	}
+
+	UserListResponse struct {
+		Users    []UserDetailsResponse `json:"users"`
+		Page     int                   `json:"page"`
+		PageSize int                   `json:"pageSize"`
+		Total    int64                 `json:"total"`
+	}
 )
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type (
+	CreateWebhookRequest struct {
+		URL        string   `json:"url" validate:"required,url"`
+		EventTypes []string `json:"eventTypes" validate:"required"`
+		Secret     string   `json:"secret,omitempty"`
+	}
+
+	WebhookResponse struct {
+		ID         uint64    `json:"id"`
+		URL        string    `json:"url"`
+		EventTypes []string  `json:"eventTypes"`
+		Secret     string    `json:"secret,omitempty"`
+		Active     bool      `json:"active"`
+		CreatedBy  string    `json:"createdBy"`
+		CreatedAt  time.Time `json:"createdAt"`
+	}
+
+	WebhookListResponse struct {
+		Webhooks []WebhookResponse `json:"webhooks"`
+	}
+
+	WebhookDeliveryResponse struct {
+		ID             uint64          `json:"id"`
+		SubscriptionID uint64          `json:"subscriptionId"`
+		EventType      string          `json:"eventType"`
+		DedupeKey      string          `json:"dedupeKey"`
+		Status         string          `json:"status"`
+		Attempts       int             `json:"attempts"`
+		NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+		LastStatusCode *int            `json:"lastStatusCode,omitempty"`
+		LastError      *string         `json:"lastError,omitempty"`
+		DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+		Payload        json.RawMessage `json:"payload"`
+		CreatedAt      time.Time       `json:"createdAt"`
+	}
+
+	WebhookDeliveryListResponse struct {
+		Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	}
+)
@@ -0,0 +1,121 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	defaultOnce   sync.Once
+	defaultEngine *Engine
+)
+
+// Default returns the engine for the configured check file. It starts empty;
+// the server loads and watches it on startup, together with the transaction
+// rules.
+func Default() *Engine {
+	defaultOnce.Do(func() {
+		defaultEngine = NewEngine(config.Get().FraudChecksFile)
+	})
+	return defaultEngine
+}
+
+// Engine holds the active checks of a check file and swaps them when the file
+// changes. Without a file it holds no checks.
+type Engine struct {
+	path string
+
+	mu      sync.RWMutex
+	checks  *Checks
+	modTime time.Time
+}
+
+func NewEngine(path string) *Engine {
+	return &Engine{path: path}
+}
+
+// Load reads the check file. A file that cannot be read or parsed leaves the
+// active checks in place.
+func (e *Engine) Load() error {
+	if e.path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return fmt.Errorf("failed to read fraud check file: %w", err)
+	}
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return fmt.Errorf("failed to read fraud check file: %w", err)
+	}
+	checks, err := Parse(data)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.checks = checks
+	e.modTime = info.ModTime()
+	e.mu.Unlock()
+
+	logrus.WithFields(logrus.Fields{"file": e.path, "checks": checks.Len()}).Info("Fraud checks loaded")
+	return nil
+}
+
+// Evaluate runs the active checks against the win.
+func (e *Engine) Evaluate(store Store, in Input) ([]Hit, error) {
+	e.mu.RLock()
+	checks := e.checks
+	e.mu.RUnlock()
+	if checks == nil {
+		return nil, nil
+	}
+	return checks.Evaluate(store, in)
+}
+
+// Watch reloads the check file whenever its modification time changes, until
+// ctx is done.
+func (e *Engine) Watch(ctx context.Context, interval time.Duration) {
+	if e.path == "" {
+		return
+	}
+	logrus.WithFields(logrus.Fields{"file": e.path, "interval": interval}).Info("Fraud check watcher started")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("Fraud check watcher stopped")
+			return
+		case <-ticker.C:
+		}
+
+		if !e.changed() {
+			continue
+		}
+		if err := e.Load(); err != nil {
+			logrus.WithFields(logrus.Fields{"file": e.path, "error": err}).Error("Failed to reload fraud checks, keeping the previous checks")
+		}
+	}
+}
+
+func (e *Engine) changed() bool {
+	info, err := os.Stat(e.path)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"file": e.path, "error": err}).Warn("Failed to check fraud check file")
+		return false
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return !info.ModTime().Equal(e.modTime)
+}
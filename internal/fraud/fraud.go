@@ -0,0 +1,299 @@
+package fraud
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	ActionReject = "reject"
+	ActionHold   = "hold"
+	ActionFlag   = "flag"
+
+	ScopeUser   = "user"
+	ScopeSource = "source"
+)
+
+// actionRank orders actions by severity, so the strongest hit decides.
+var actionRank = map[string]int{
+	ActionFlag:   1,
+	ActionHold:   2,
+	ActionReject: 3,
+}
+
+// File is the JSON document fraud checks are loaded from.
+type File struct {
+	Velocity []VelocityCheck `json:"velocity"`
+	Jumps    []JumpCheck     `json:"balanceJumps"`
+}
+
+// VelocityCheck limits the wins booked in a sliding window. With the user
+// scope it counts the user's wins from Sources, with the source scope the
+// wins every user received from the transaction's source. A check hits when
+// the new win would take the count above MaxCount or the sum above MaxSum.
+type VelocityCheck struct {
+	ID       string   `json:"id"`
+	Action   string   `json:"action"`
+	Scope    string   `json:"scope,omitempty"`
+	Sources  []string `json:"sources,omitempty"`
+	Window   string   `json:"window"`
+	MaxCount int64    `json:"maxCount,omitempty"`
+	MaxSum   string   `json:"maxSum,omitempty"`
+
+	window time.Duration
+	maxSum *float64
+}
+
+// JumpCheck compares a win with the user's average win from Sources over
+// Lookback. It hits when the win is more than Factor times the average and
+// the user has at least MinHistory earlier wins to compare with.
+type JumpCheck struct {
+	ID         string   `json:"id"`
+	Action     string   `json:"action"`
+	Sources    []string `json:"sources,omitempty"`
+	Lookback   string   `json:"lookback"`
+	Factor     float64  `json:"factor"`
+	MinHistory int64    `json:"minHistory,omitempty"`
+
+	lookback time.Duration
+}
+
+// Wins is the number and total amount of wins in a period.
+type Wins struct {
+	Count int64
+	Sum   float64
+}
+
+// Store answers the history questions checks ask. An empty sources list
+// means every source; a zero userID means every user.
+type Store interface {
+	Wins(userID uint64, sources []string, since time.Time) (Wins, error)
+}
+
+// Input is the win being checked.
+type Input struct {
+	UserID     uint64
+	SourceType string
+	Amount     float64
+	At         time.Time
+}
+
+// Hit is a check the win failed.
+type Hit struct {
+	CheckID string
+	Action  string
+	Reason  string
+}
+
+// Checks is a parsed and validated check file.
+type Checks struct {
+	velocity []VelocityCheck
+	jumps    []JumpCheck
+}
+
+// Parse reads and validates a check file.
+func Parse(data []byte) (*Checks, error) {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid fraud check file: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	checkID := func(i int, id string) error {
+		if id == "" {
+			return fmt.Errorf("check %d has no id", i+1)
+		}
+		if seen[id] {
+			return fmt.Errorf("check %s is defined twice", id)
+		}
+		seen[id] = true
+		return nil
+	}
+
+	for i := range file.Velocity {
+		check := &file.Velocity[i]
+		if err := checkID(i, check.ID); err != nil {
+			return nil, err
+		}
+		if err := check.compile(); err != nil {
+			return nil, fmt.Errorf("check %s: %w", check.ID, err)
+		}
+	}
+	for i := range file.Jumps {
+		check := &file.Jumps[i]
+		if err := checkID(len(file.Velocity)+i, check.ID); err != nil {
+			return nil, err
+		}
+		if err := check.compile(); err != nil {
+			return nil, fmt.Errorf("check %s: %w", check.ID, err)
+		}
+	}
+
+	return &Checks{velocity: file.Velocity, jumps: file.Jumps}, nil
+}
+
+func validateAction(action string) error {
+	if _, ok := actionRank[action]; !ok {
+		return fmt.Errorf("action must be %s, %s or %s", ActionReject, ActionHold, ActionFlag)
+	}
+	return nil
+}
+
+func parseWindow(value string) (time.Duration, error) {
+	window, err := time.ParseDuration(value)
+	if err != nil || window <= 0 {
+		return 0, errors.New("must be a positive duration such as 10m")
+	}
+	return window, nil
+}
+
+func (c *VelocityCheck) compile() error {
+	if err := validateAction(c.Action); err != nil {
+		return err
+	}
+
+	switch c.Scope {
+	case "":
+		c.Scope = ScopeUser
+	case ScopeUser, ScopeSource:
+	default:
+		return fmt.Errorf("scope must be %s or %s", ScopeUser, ScopeSource)
+	}
+	if c.Scope == ScopeSource && len(c.Sources) > 0 {
+		return errors.New("sources cannot be combined with the source scope")
+	}
+
+	var err error
+	if c.window, err = parseWindow(c.Window); err != nil {
+		return fmt.Errorf("window %w", err)
+	}
+
+	if c.MaxSum != "" {
+		sum, err := strconv.ParseFloat(c.MaxSum, 64)
+		if err != nil || sum <= 0 {
+			return errors.New("maxSum must be a positive decimal amount")
+		}
+		c.maxSum = &sum
+	}
+	if c.MaxCount < 0 {
+		return errors.New("maxCount must be positive")
+	}
+	if c.MaxCount == 0 && c.maxSum == nil {
+		return errors.New("maxCount or maxSum is required")
+	}
+	return nil
+}
+
+func (c *JumpCheck) compile() error {
+	if err := validateAction(c.Action); err != nil {
+		return err
+	}
+
+	var err error
+	if c.lookback, err = parseWindow(c.Lookback); err != nil {
+		return fmt.Errorf("lookback %w", err)
+	}
+	if c.Factor <= 1 {
+		return errors.New("factor must be greater than 1")
+	}
+	if c.MinHistory < 0 {
+		return errors.New("minHistory must not be negative")
+	}
+	if c.MinHistory == 0 {
+		c.MinHistory = 1
+	}
+	return nil
+}
+
+// Len returns the number of checks.
+func (c *Checks) Len() int {
+	return len(c.velocity) + len(c.jumps)
+}
+
+// Evaluate runs every check that applies to the win and returns the hits in
+// file order, velocity checks first.
+func (c *Checks) Evaluate(store Store, in Input) ([]Hit, error) {
+	var hits []Hit
+	for _, check := range c.velocity {
+		if check.Scope == ScopeUser && !applies(check.Sources, in.SourceType) {
+			continue
+		}
+
+		userID, sources := in.UserID, check.Sources
+		if check.Scope == ScopeSource {
+			userID, sources = 0, []string{in.SourceType}
+		}
+		wins, err := store.Wins(userID, sources, in.At.Add(-check.window))
+		if err != nil {
+			return nil, fmt.Errorf("failed to count wins for check %s: %w", check.ID, err)
+		}
+
+		if reason := check.exceeded(wins, in.Amount); reason != "" {
+			hits = append(hits, Hit{CheckID: check.ID, Action: check.Action, Reason: reason})
+		}
+	}
+
+	for _, check := range c.jumps {
+		if !applies(check.Sources, in.SourceType) {
+			continue
+		}
+
+		wins, err := store.Wins(in.UserID, check.Sources, in.At.Add(-check.lookback))
+		if err != nil {
+			return nil, fmt.Errorf("failed to sum wins for check %s: %w", check.ID, err)
+		}
+
+		if reason := check.exceeded(wins, in.Amount); reason != "" {
+			hits = append(hits, Hit{CheckID: check.ID, Action: check.Action, Reason: reason})
+		}
+	}
+	return hits, nil
+}
+
+func (c *VelocityCheck) exceeded(wins Wins, amount float64) string {
+	if c.MaxCount > 0 && wins.Count+1 > c.MaxCount {
+		return fmt.Sprintf("%d wins within %s, at most %d allowed", wins.Count+1, c.window, c.MaxCount)
+	}
+	if c.maxSum != nil && wins.Sum+amount > *c.maxSum {
+		return fmt.Sprintf("wins of %.2f within %s, at most %.2f allowed", wins.Sum+amount, c.window, *c.maxSum)
+	}
+	return ""
+}
+
+func (c *JumpCheck) exceeded(wins Wins, amount float64) string {
+	if wins.Count < c.MinHistory {
+		return ""
+	}
+	average := wins.Sum / float64(wins.Count)
+	if amount <= average*c.Factor {
+		return ""
+	}
+	return fmt.Sprintf("win of %.2f is %.1f times the average win of %.2f within %s", amount, amount/average, average, c.lookback)
+}
+
+// Strongest returns the hit with the most severe action, the first one of
+// equal severity, or nil without hits.
+func Strongest(hits []Hit) *Hit {
+	var strongest *Hit
+	for i := range hits {
+		if strongest == nil || actionRank[hits[i].Action] > actionRank[strongest.Action] {
+			strongest = &hits[i]
+		}
+	}
+	return strongest
+}
+
+func applies(sources []string, sourceType string) bool {
+	if len(sources) == 0 {
+		return true
+	}
+	for _, source := range sources {
+		if source == sourceType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,237 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type winQuery struct {
+	userID  uint64
+	sources []string
+	since   time.Time
+}
+
+type fakeStore struct {
+	wins    map[uint64]Wins
+	queries []winQuery
+	err     error
+}
+
+func (f *fakeStore) Wins(userID uint64, sources []string, since time.Time) (Wins, error) {
+	f.queries = append(f.queries, winQuery{userID: userID, sources: sources, since: since})
+	return f.wins[userID], f.err
+}
+
+func TestParseRejectsInvalidChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		expected string
+	}{
+		{name: "invalid JSON", file: `{"velocity": [`, expected: "invalid fraud check file"},
+		{name: "missing id", file: `{"velocity": [{"action": "flag", "window": "1m", "maxCount": 5}]}`, expected: "check 1 has no id"},
+		{
+			name:     "duplicate id across kinds",
+			file:     `{"velocity": [{"id": "a", "action": "flag", "window": "1m", "maxCount": 5}], "balanceJumps": [{"id": "a", "action": "flag", "lookback": "24h", "factor": 5}]}`,
+			expected: "check a is defined twice",
+		},
+		{name: "unknown action", file: `{"velocity": [{"id": "a", "action": "block", "window": "1m", "maxCount": 5}]}`, expected: "check a: action must be"},
+		{name: "unknown scope", file: `{"velocity": [{"id": "a", "action": "flag", "scope": "game", "window": "1m", "maxCount": 5}]}`, expected: "check a: scope must be"},
+		{
+			name:     "sources with source scope",
+			file:     `{"velocity": [{"id": "a", "action": "flag", "scope": "source", "sources": ["server"], "window": "1m", "maxCount": 5}]}`,
+			expected: "check a: sources cannot be combined",
+		},
+		{name: "invalid window", file: `{"velocity": [{"id": "a", "action": "flag", "window": "soon", "maxCount": 5}]}`, expected: "check a: window must be"},
+		{name: "no threshold", file: `{"velocity": [{"id": "a", "action": "flag", "window": "1m"}]}`, expected: "check a: maxCount or maxSum is required"},
+		{name: "invalid sum", file: `{"velocity": [{"id": "a", "action": "flag", "window": "1m", "maxSum": "0"}]}`, expected: "check a: maxSum must be"},
+		{name: "invalid lookback", file: `{"balanceJumps": [{"id": "a", "action": "hold", "lookback": "-1h", "factor": 5}]}`, expected: "check a: lookback must be"},
+		{name: "factor too small", file: `{"balanceJumps": [{"id": "a", "action": "hold", "lookback": "24h", "factor": 1}]}`, expected: "check a: factor must be greater than 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.file))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestEngineReloadsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fraud.json")
+	writeChecks := func(content string, modTime time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	store := &fakeStore{wins: map[uint64]Wins{7: {Count: 5, Sum: 50}}}
+	input := Input{UserID: 7, SourceType: "game", Amount: 10, At: time.Now()}
+	start := time.Now().Add(-time.Hour)
+
+	writeChecks(`{"velocity": [{"id": "first", "action": "flag", "window": "1m", "maxCount": 1}]}`, start)
+	engine := NewEngine(path)
+	require.NoError(t, engine.Load())
+	hits := func() int {
+		found, err := engine.Evaluate(store, input)
+		require.NoError(t, err)
+		return len(found)
+	}
+	require.Equal(t, 1, hits())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go engine.Watch(ctx, 10*time.Millisecond)
+
+	writeChecks(`{"velocity": [{"id": "first", "action": "flag", "window": "1m", "maxCount": 1}, {"id": "second", "action": "hold", "window": "1h", "maxCount": 2}]}`, start.Add(time.Minute))
+	assert.Eventually(t, func() bool { return hits() == 2 }, time.Second, 10*time.Millisecond)
+
+	writeChecks(`{"velocity": [`, start.Add(2*time.Minute))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 2, hits(), "an invalid file keeps the previous checks")
+}
+
+func TestEngineWithoutFileHasNoChecks(t *testing.T) {
+	engine := NewEngine("")
+	require.NoError(t, engine.Load())
+	hits, err := engine.Evaluate(&fakeStore{}, Input{UserID: 7, SourceType: "game", Amount: 1, At: time.Now()})
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+}
+
+func TestEvaluateVelocity(t *testing.T) {
+	checks, err := Parse([]byte(`{"velocity": [
+		{"id": "server-burst", "action": "hold", "sources": ["server"], "window": "1m", "maxCount": 5},
+		{"id": "daily-volume", "action": "flag", "window": "24h", "maxSum": "1000.00"},
+		{"id": "source-burst", "action": "reject", "scope": "source", "window": "10s", "maxCount": 100}
+	]}`))
+	require.NoError(t, err)
+
+	at := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		source   string
+		amount   float64
+		wins     map[uint64]Wins
+		expected []Hit
+	}{
+		{
+			name:   "within every threshold",
+			source: "server",
+			amount: 10,
+			wins:   map[uint64]Wins{0: {Count: 99}, 7: {Count: 4, Sum: 990}},
+		},
+		{
+			name:     "count reaches the limit",
+			source:   "server",
+			amount:   10,
+			wins:     map[uint64]Wins{7: {Count: 5, Sum: 50}},
+			expected: []Hit{{CheckID: "server-burst", Action: ActionHold, Reason: "6 wins within 1m0s, at most 5 allowed"}},
+		},
+		{
+			name:     "sum goes over the limit",
+			source:   "game",
+			amount:   10.01,
+			wins:     map[uint64]Wins{7: {Count: 5, Sum: 990}},
+			expected: []Hit{{CheckID: "daily-volume", Action: ActionFlag, Reason: "wins of 1000.01 within 24h0m0s, at most 1000.00 allowed"}},
+		},
+		{
+			name:     "source-wide burst",
+			source:   "payment",
+			amount:   1,
+			wins:     map[uint64]Wins{0: {Count: 100}},
+			expected: []Hit{{CheckID: "source-burst", Action: ActionReject, Reason: "101 wins within 10s, at most 100 allowed"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{wins: tt.wins}
+			hits, err := checks.Evaluate(store, Input{UserID: 7, SourceType: tt.source, Amount: tt.amount, At: at})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, hits)
+		})
+	}
+}
+
+func TestEvaluateQueriesScopes(t *testing.T) {
+	checks, err := Parse([]byte(`{"velocity": [
+		{"id": "server-burst", "action": "hold", "sources": ["server"], "window": "1m", "maxCount": 5},
+		{"id": "source-burst", "action": "reject", "scope": "source", "window": "10s", "maxCount": 100}
+	]}`))
+	require.NoError(t, err)
+
+	at := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{}
+	_, err = checks.Evaluate(store, Input{UserID: 7, SourceType: "server", Amount: 1, At: at})
+	require.NoError(t, err)
+
+	assert.Equal(t, []winQuery{
+		{userID: 7, sources: []string{"server"}, since: at.Add(-time.Minute)},
+		{userID: 0, sources: []string{"server"}, since: at.Add(-10 * time.Second)},
+	}, store.queries)
+
+	store = &fakeStore{}
+	_, err = checks.Evaluate(store, Input{UserID: 7, SourceType: "game", Amount: 1, At: at})
+	require.NoError(t, err)
+	assert.Len(t, store.queries, 1, "user checks skip other sources")
+}
+
+func TestEvaluateJumps(t *testing.T) {
+	checks, err := Parse([]byte(`{"balanceJumps": [
+		{"id": "big-win", "action": "hold", "sources": ["game"], "lookback": "720h", "factor": 10, "minHistory": 5}
+	]}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		amount   float64
+		wins     Wins
+		expected []Hit
+	}{
+		{name: "too little history", amount: 500, wins: Wins{Count: 4, Sum: 40}},
+		{name: "at the factor", amount: 100, wins: Wins{Count: 5, Sum: 50}},
+		{
+			name:     "above the factor",
+			amount:   250,
+			wins:     Wins{Count: 5, Sum: 50},
+			expected: []Hit{{CheckID: "big-win", Action: ActionHold, Reason: "win of 250.00 is 25.0 times the average win of 10.00 within 720h0m0s"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{wins: map[uint64]Wins{7: tt.wins}}
+			hits, err := checks.Evaluate(store, Input{UserID: 7, SourceType: "game", Amount: tt.amount, At: time.Now()})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, hits)
+		})
+	}
+}
+
+func TestEvaluateReportsStoreErrors(t *testing.T) {
+	checks, err := Parse([]byte(`{"velocity": [{"id": "burst", "action": "hold", "window": "1m", "maxCount": 5}]}`))
+	require.NoError(t, err)
+
+	_, err = checks.Evaluate(&fakeStore{err: errors.New("connection reset")}, Input{UserID: 7, SourceType: "game", Amount: 1, At: time.Now()})
+	assert.EqualError(t, err, "failed to count wins for check burst: connection reset")
+}
+
+func TestStrongest(t *testing.T) {
+	flag := Hit{CheckID: "volume", Action: ActionFlag}
+	hold := Hit{CheckID: "burst", Action: ActionHold}
+	otherHold := Hit{CheckID: "jump", Action: ActionHold}
+	reject := Hit{CheckID: "source", Action: ActionReject}
+
+	assert.Nil(t, Strongest(nil))
+	assert.Equal(t, &flag, Strongest([]Hit{flag}))
+	assert.Equal(t, &hold, Strongest([]Hit{flag, hold, otherHold}))
+	assert.Equal(t, &reject, Strongest([]Hit{hold, reject, flag}))
+}
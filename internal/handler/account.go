@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/service"
+)
+
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+func NewAccountHandler() *AccountHandler {
+	return &AccountHandler{
+		accountService: service.NewAccountService(),
+	}
+}
+
+func (h *AccountHandler) ChangeStatus(c echo.Context) error {
+	userID, req, validationErr := h.validateChangeStatusRequest(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	status, err := h.accountService.ChangeStatus(userID, req, auditActor(c))
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "user_not_found",
+				Message: "User does not exist",
+			})
+		case "invalid status transition":
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "invalid_status_transition",
+				Message: "Account cannot move from its current status to the requested one",
+			})
+		case "self-exclusion still active":
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "self_exclusion_active",
+				Message: "Account cannot be reactivated before the self-exclusion ends",
+			})
+		case "excluded until must be in the future":
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_excluded_until",
+				Message: "ExcludedUntil must be in the future",
+			})
+		default:
+			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to change account status",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, status)
+}
+
+func (h *AccountHandler) GetStatusHistory(c echo.Context) error {
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		})
+	}
+
+	history, err := h.accountService.GetStatusHistory(userID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "user_not_found",
+				Message: "User does not exist",
+			})
+		default:
+			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to get status history",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+func (h *AccountHandler) validateChangeStatusRequest(c echo.Context) (uint64, dto.ChangeStatusRequest, *ValidationError) {
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return 0, dto.ChangeStatusRequest{}, &ValidationError{
+			Code:    "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		}
+	}
+
+	var req dto.ChangeStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return 0, dto.ChangeStatusRequest{}, &ValidationError{
+			Code:    "invalid_request_body",
+			Message: "Invalid JSON format",
+		}
+	}
+
+	switch req.Status {
+	case model.StatusActive, model.StatusSuspended, model.StatusSelfExcluded, model.StatusClosed:
+	case "":
+		return 0, dto.ChangeStatusRequest{}, &ValidationError{
+			Code:    "missing_status",
+			Message: "Status field is required",
+		}
+	default:
+		return 0, dto.ChangeStatusRequest{}, &ValidationError{
+			Code:    "invalid_status",
+			Message: "Status must be one of: active, suspended, self_excluded, closed",
+		}
+	}
+
+	if req.Reason == "" {
+		return 0, dto.ChangeStatusRequest{}, &ValidationError{
+			Code:    "missing_reason",
+			Message: "Reason field is required",
+		}
+	}
+
+	if req.Status == model.StatusSelfExcluded && req.ExcludedUntil == nil {
+		return 0, dto.ChangeStatusRequest{}, &ValidationError{
+			Code:    "missing_excluded_until",
+			Message: "ExcludedUntil field is required for self-exclusion",
+		}
+	}
+
+	return userID, req, nil
+}
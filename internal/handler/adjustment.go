@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/service"
+)
+
+type AdjustmentHandler struct {
+	adjustmentService *service.AdjustmentService
+}
+
+func NewAdjustmentHandler() *AdjustmentHandler {
+	return &AdjustmentHandler{
+		adjustmentService: service.NewAdjustmentService(),
+	}
+}
+
+func (h *AdjustmentHandler) RequestAdjustment(c echo.Context) error {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		})
+	}
+
+	var req dto.CreateAdjustmentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request_body",
+			Message: "Invalid JSON format",
+		})
+	}
+
+	if validationErr := validationError(c.Validate(&req)); validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+			Details: validationErr.Details,
+		})
+	}
+
+	adjustment, err := h.adjustmentService.RequestAdjustment(userID, req, auditActor(c))
+	if err != nil {
+		return adjustmentError(c, err, "Failed to request adjustment")
+	}
+
+	return c.JSON(http.StatusCreated, adjustment)
+}
+
+func (h *AdjustmentHandler) ListAdjustments(c echo.Context) error {
+	status := c.QueryParam("status")
+	switch status {
+	case "", model.AdjustmentPending, model.AdjustmentApproved, model.AdjustmentRejected:
+	default:
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_status",
+			Message: "Status must be one of: pending, approved, rejected",
+		})
+	}
+
+	var userID uint64
+	if value := c.QueryParam("userId"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_user_id",
+				Message: "User ID must be a positive integer",
+			})
+		}
+		userID = parsed
+	}
+
+	page, pageSize, validationErr := parsePagination(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	adjustments, err := h.adjustmentService.ListAdjustments(status, userID, page, pageSize)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list adjustments",
+		})
+	}
+
+	return c.JSON(http.StatusOK, adjustments)
+}
+
+func (h *AdjustmentHandler) GetAdjustment(c echo.Context) error {
+	adjustmentID, validationErr := parseAdjustmentID(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	adjustment, err := h.adjustmentService.GetAdjustment(adjustmentID)
+	if err != nil {
+		return adjustmentError(c, err, "Failed to get adjustment")
+	}
+
+	return c.JSON(http.StatusOK, adjustment)
+}
+
+func (h *AdjustmentHandler) ApproveAdjustment(c echo.Context) error {
+	adjustmentID, req, validationErr := parseReviewRequest(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	adjustment, err := h.adjustmentService.ApproveAdjustment(adjustmentID, req, auditActor(c))
+	if err != nil {
+		return adjustmentError(c, err, "Failed to approve adjustment")
+	}
+
+	return c.JSON(http.StatusOK, adjustment)
+}
+
+func (h *AdjustmentHandler) RejectAdjustment(c echo.Context) error {
+	adjustmentID, req, validationErr := parseReviewRequest(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	adjustment, err := h.adjustmentService.RejectAdjustment(adjustmentID, req, auditActor(c))
+	if err != nil {
+		return adjustmentError(c, err, "Failed to reject adjustment")
+	}
+
+	return c.JSON(http.StatusOK, adjustment)
+}
+
+// adjustmentError maps workflow errors and, for approvals, the rejections of
+// the booked transaction to responses.
+func adjustmentError(c echo.Context, err error, message string) error {
+	switch err.Error() {
+	case "adjustment not found":
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "adjustment_not_found",
+			Message: "Adjustment does not exist",
+		})
+	case "adjustment already reviewed":
+		return c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "adjustment_already_reviewed",
+			Message: "Adjustment has already been approved or rejected",
+		})
+	case "adjustment held for review":
+		return c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "adjustment_held",
+			Message: "Adjustment transaction was held for review and stays pending",
+		})
+	case "reviewer is the requester":
+		return c.JSON(http.StatusForbidden, dto.ErrorResponse{
+			Error:   "self_review",
+			Message: "Adjustment must be reviewed by a different operator than the one who requested it",
+		})
+	}
+
+	status, response := transactionErrorResponse(err)
+	if status == http.StatusInternalServerError {
+		response.Message = message
+	}
+	return c.JSON(status, response)
+}
+
+func parseAdjustmentID(c echo.Context) (uint64, *ValidationError) {
+	adjustmentID, err := strconv.ParseUint(c.Param("adjustmentId"), 10, 64)
+	if err != nil {
+		return 0, &ValidationError{
+			Code:    "invalid_adjustment_id",
+			Message: "Adjustment ID must be a positive integer",
+		}
+	}
+	return adjustmentID, nil
+}
+
+// parseReviewRequest reads the adjustment ID and the optional review note.
+func parseReviewRequest(c echo.Context) (uint64, dto.ReviewAdjustmentRequest, *ValidationError) {
+	adjustmentID, validationErr := parseAdjustmentID(c)
+	if validationErr != nil {
+		return 0, dto.ReviewAdjustmentRequest{}, validationErr
+	}
+
+	var req dto.ReviewAdjustmentRequest
+	if err := c.Bind(&req); err != nil {
+		return 0, dto.ReviewAdjustmentRequest{}, &ValidationError{
+			Code:    "invalid_request_body",
+			Message: "Invalid JSON format",
+		}
+	}
+	return adjustmentID, req, nil
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/service"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler() *AuditHandler {
+	return &AuditHandler{
+		auditService: service.NewAuditService(),
+	}
+}
+
+func (h *AuditHandler) ListEntries(c echo.Context) error {
+	filter, validationErr := parseAuditFilter(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	page, pageSize, validationErr := parsePagination(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	entries, err := h.auditService.ListEntries(filter, page, pageSize)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list audit entries",
+		})
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+// parseAuditFilter reads the actor, userId, from and to query parameters.
+// From and to are RFC 3339 timestamps bounding the range [from, to).
+func parseAuditFilter(c echo.Context) (database.AuditFilter, *ValidationError) {
+	filter := database.AuditFilter{Actor: c.QueryParam("actor")}
+
+	if value := c.QueryParam("userId"); value != "" {
+		userID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || userID == 0 {
+			return database.AuditFilter{}, &ValidationError{
+				Code:    "invalid_user_id",
+				Message: "User ID must be a positive integer",
+			}
+		}
+		filter.TargetUserID = userID
+	}
+
+	var err error
+	if filter.From, err = parseAuditTime(c.QueryParam("from")); err != nil {
+		return database.AuditFilter{}, &ValidationError{
+			Code:    "invalid_timestamp",
+			Message: "From must be an RFC 3339 timestamp",
+		}
+	}
+	if filter.To, err = parseAuditTime(c.QueryParam("to")); err != nil {
+		return database.AuditFilter{}, &ValidationError{
+			Code:    "invalid_timestamp",
+			Message: "To must be an RFC 3339 timestamp",
+		}
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return database.AuditFilter{}, &ValidationError{
+			Code:    "invalid_time_range",
+			Message: "To must be after from",
+		}
+	}
+
+	return filter, nil
+}
+
+func parseAuditTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	at, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, err
+	}
+	at = at.UTC()
+	return &at, nil
+}
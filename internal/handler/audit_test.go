@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAuditFilter(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		query         string
+		expected      database.AuditFilter
+		expectedError string
+	}{
+		{
+			name:     "no filters",
+			query:    "",
+			expected: database.AuditFilter{},
+		},
+		{
+			name:     "actor and target",
+			query:    "actor=alice&userId=42",
+			expected: database.AuditFilter{Actor: "alice", TargetUserID: 42},
+		},
+		{
+			name:     "time range normalised to UTC",
+			query:    "from=2025-06-01T02:00:00%2B02:00&to=2025-06-02T00:00:00Z",
+			expected: database.AuditFilter{From: &from, To: &to},
+		},
+		{
+			name:     "open ended range",
+			query:    "from=2025-06-01T00:00:00Z",
+			expected: database.AuditFilter{From: &from},
+		},
+		{name: "non numeric user", query: "userId=abc", expectedError: "invalid_user_id"},
+		{name: "zero user", query: "userId=0", expectedError: "invalid_user_id"},
+		{name: "date only", query: "from=2025-06-01", expectedError: "invalid_timestamp"},
+		{name: "bad upper bound", query: "to=yesterday", expectedError: "invalid_timestamp"},
+		{
+			name:          "empty range",
+			query:         "from=2025-06-01T00:00:00Z&to=2025-06-01T00:00:00Z",
+			expectedError: "invalid_time_range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin/audit?"+tt.query, nil)
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			filter, validationErr := parseAuditFilter(c)
+			if tt.expectedError != "" {
+				assert.NotNil(t, validationErr)
+				assert.Equal(t, tt.expectedError, validationErr.Code)
+				return
+			}
+			assert.Nil(t, validationErr)
+			assert.Equal(t, tt.expected, filter)
+		})
+	}
+}
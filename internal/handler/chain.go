@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/service"
+)
+
+type ChainHandler struct {
+	chainService *service.ChainService
+}
+
+func NewChainHandler() *ChainHandler {
+	return &ChainHandler{
+		chainService: service.NewChainService(),
+	}
+}
+
+func (h *ChainHandler) VerifyChain(c echo.Context) error {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		})
+	}
+
+	verification, err := h.chainService.VerifyUser(userID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "user_not_found",
+				Message: "User does not exist",
+			})
+		default:
+			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to verify transaction chain",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, verification)
+}
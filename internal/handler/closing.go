@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/service"
+)
+
+type ClosingHandler struct {
+	closingService *service.ClosingService
+}
+
+func NewClosingHandler() *ClosingHandler {
+	return &ClosingHandler{
+		closingService: service.NewClosingService(),
+	}
+}
+
+func (h *ClosingHandler) GetClosingReport(c echo.Context) error {
+	businessDate, err := time.Parse("2006-01-02", c.Param("date"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_date",
+			Message: "Date must be formatted as YYYY-MM-DD",
+		})
+	}
+
+	page, pageSize, validationErr := parsePagination(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	report, err := h.closingService.GetClosingReport(businessDate, page, pageSize)
+	if err != nil {
+		switch err.Error() {
+		case "closing not found":
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "closing_not_found",
+				Message: "Business day has not been closed",
+			})
+		default:
+			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to get closing report",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
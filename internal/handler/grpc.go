@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"runtime/debug"
+
+	balancev1 "github.com/lielamurs/balance-transactions/api/balance/v1"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/service"
+	"github.com/lielamurs/balance-transactions/internal/stream"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCHandler serves the gRPC BalanceService on top of the same UserService
+// as the REST handlers, reusing their validation and error codes.
+type GRPCHandler struct {
+	balancev1.UnimplementedBalanceServiceServer
+	userService *service.UserService
+}
+
+func NewGRPCHandler() *GRPCHandler {
+	return &GRPCHandler{
+		userService: service.NewUserService(),
+	}
+}
+
+func (h *GRPCHandler) GetBalance(ctx context.Context, req *balancev1.GetBalanceRequest) (*balancev1.Balance, error) {
+	if req.GetUserId() == 0 {
+		return nil, grpcError(codes.InvalidArgument, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		})
+	}
+
+	balance, err := h.userService.GetBalance(req.GetUserId())
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			return nil, grpcError(codes.NotFound, dto.ErrorResponse{
+				Error:   "user_not_found",
+				Message: "User does not exist",
+			})
+		default:
+			return nil, grpcError(codes.Internal, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to get balance",
+			})
+		}
+	}
+
+	return &balancev1.Balance{
+		UserId:  balance.UserID,
+		Balance: balance.Balance,
+		Wallets: walletBalancesMessage(balance.Wallets),
+	}, nil
+}
+
+func (h *GRPCHandler) ProcessTransaction(ctx context.Context, req *balancev1.TransactionRequest) (*balancev1.TransactionResponse, error) {
+	status, code, errResponse := h.processTransaction(req)
+	if errResponse != nil {
+		return nil, grpcError(code, *errResponse)
+	}
+
+	return transactionMessage(req.GetTransactionId(), status), nil
+}
+
+func transactionMessage(transactionID, status string) *balancev1.TransactionResponse {
+	result := transactionResult(status)
+	return &balancev1.TransactionResponse{
+		TransactionId: transactionID,
+		Success:       result.Success,
+		Message:       result.Message,
+		Status:        result.Status,
+	}
+}
+
+func (h *GRPCHandler) StreamBalance(req *balancev1.StreamBalanceRequest, server grpc.ServerStreamingServer[balancev1.BalanceUpdate]) error {
+	if req.GetUserId() == 0 {
+		return grpcError(codes.InvalidArgument, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		})
+	}
+
+	subscription, initial, err := h.userService.SubscribeBalance(req.GetUserId(), req.GetLastEventId())
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			return grpcError(codes.NotFound, dto.ErrorResponse{
+				Error:   "user_not_found",
+				Message: "User does not exist",
+			})
+		default:
+			return grpcError(codes.Internal, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to open balance stream",
+			})
+		}
+	}
+	defer subscription.Close()
+
+	for _, message := range initial {
+		if err := sendBalanceUpdate(server, message); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-server.Context().Done():
+			return nil
+		case message, ok := <-subscription.C():
+			if !ok {
+				return grpcError(codes.Unavailable, dto.ErrorResponse{
+					Error:   "stream_lagging",
+					Message: "Client fell behind; reconnect with the last event ID",
+				})
+			}
+			if err := sendBalanceUpdate(server, message); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (h *GRPCHandler) ProcessTransactions(server grpc.BidiStreamingServer[balancev1.TransactionRequest, balancev1.TransactionResponse]) error {
+	for {
+		req, err := server.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		status, _, errResponse := h.processTransaction(req)
+		response := transactionMessage(req.GetTransactionId(), status)
+		if errResponse != nil {
+			response = &balancev1.TransactionResponse{
+				TransactionId: req.GetTransactionId(),
+				Error:         errorMessage(*errResponse),
+			}
+		}
+
+		if err := server.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+func (h *GRPCHandler) processTransaction(req *balancev1.TransactionRequest) (string, codes.Code, *dto.ErrorResponse) {
+	if req.GetUserId() == 0 {
+		return "", codes.InvalidArgument, &dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		}
+	}
+
+	if validationErr := validateSourceType(req.GetSourceType()); validationErr != nil {
+		return "", codes.InvalidArgument, &dto.ErrorResponse{Error: validationErr.Code, Message: validationErr.Message}
+	}
+
+	transaction := dto.TransactionRequest{
+		State:         req.GetState(),
+		Amount:        req.GetAmount(),
+		TransactionID: req.GetTransactionId(),
+		RoundID:       req.GetRoundId(),
+		GameID:        req.GetGameId(),
+		Provider:      req.GetProvider(),
+		CloseRound:    req.GetCloseRound(),
+	}
+	if validationErr := validateTransactionFields(transaction); validationErr != nil {
+		return "", codes.InvalidArgument, &dto.ErrorResponse{Error: validationErr.Code, Message: validationErr.Message, Details: validationErr.Details}
+	}
+
+	status, err := h.userService.ProcessTransaction(req.GetUserId(), transaction, req.GetSourceType())
+	if err != nil {
+		httpStatus, response := transactionErrorResponse(err)
+		return "", grpcCode(httpStatus, response.Error), &response
+	}
+
+	return status, codes.OK, nil
+}
+
+// grpcCode translates the REST status of an error to the closest gRPC code.
+func grpcCode(httpStatus int, errorCode string) codes.Code {
+	switch errorCode {
+	case "insufficient_balance", "business_day_closed", "round_has_no_bet", "round_closed", "round_mismatch":
+		return codes.FailedPrecondition
+	case "concurrent_update":
+		return codes.Aborted
+	case "retry_exhausted":
+		return codes.Unavailable
+	}
+
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	default:
+		return codes.Internal
+	}
+}
+
+// grpcError builds a status error carrying the REST error body as detail.
+func grpcError(code codes.Code, response dto.ErrorResponse) error {
+	st := status.New(code, response.Message)
+	if detailed, err := st.WithDetails(errorMessage(response)); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func errorMessage(response dto.ErrorResponse) *balancev1.Error {
+	message := &balancev1.Error{Error: response.Error, Message: response.Message}
+	for _, detail := range response.Details {
+		message.Details = append(message.Details, &balancev1.FieldError{
+			Field:   detail.Field,
+			Code:    detail.Code,
+			Message: detail.Message,
+		})
+	}
+	return message
+}
+
+func sendBalanceUpdate(server grpc.ServerStreamingServer[balancev1.BalanceUpdate], message stream.Message) error {
+	var update dto.BalanceUpdate
+	if err := json.Unmarshal(message.Data, &update); err != nil {
+		return grpcError(codes.Internal, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to decode balance update",
+		})
+	}
+
+	response := &balancev1.BalanceUpdate{
+		EventId:    message.ID,
+		UserId:     update.UserID,
+		Balance:    update.Balance,
+		Wallets:    walletBalancesMessage(update.Wallets),
+		OccurredAt: timestamppb.New(update.OccurredAt),
+	}
+	if update.Transaction != nil {
+		response.Transaction = &balancev1.TransactionSummary{
+			TransactionId: update.Transaction.TransactionID,
+			State:         update.Transaction.State,
+			Amount:        update.Transaction.Amount,
+			SourceType:    update.Transaction.SourceType,
+		}
+	}
+	return server.Send(response)
+}
+
+func walletBalancesMessage(wallets dto.WalletBalances) *balancev1.WalletBalances {
+	return &balancev1.WalletBalances{
+		Cash:   wallets.Cash,
+		Bonus:  wallets.Bonus,
+		Locked: wallets.Locked,
+	}
+}
+
+// GRPCRecover turns a panic in a unary handler into an internal error, as
+// the Recover middleware does for HTTP.
+func GRPCRecover(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.WithFields(logrus.Fields{"method": info.FullMethod, "panic": r, "stack": string(debug.Stack())}).Error("Recovered from panic in gRPC handler")
+			err = grpcError(codes.Internal, dto.ErrorResponse{Error: "internal_error", Message: "Internal server error"})
+		}
+	}()
+	return next(ctx, req)
+}
+
+// GRPCStreamRecover is the streaming counterpart of GRPCRecover.
+func GRPCStreamRecover(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, next grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.WithFields(logrus.Fields{"method": info.FullMethod, "panic": r, "stack": string(debug.Stack())}).Error("Recovered from panic in gRPC handler")
+			err = grpcError(codes.Internal, dto.ErrorResponse{Error: "internal_error", Message: "Internal server error"})
+		}
+	}()
+	return next(srv, ss)
+}
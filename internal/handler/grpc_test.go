@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	balancev1 "github.com/lielamurs/balance-transactions/api/balance/v1"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCCode(t *testing.T) {
+	tests := []struct {
+		errorCode  string
+		httpStatus int
+		expected   codes.Code
+	}{
+		{errorCode: "user_not_found", httpStatus: http.StatusNotFound, expected: codes.NotFound},
+		{errorCode: "duplicate_transaction", httpStatus: http.StatusConflict, expected: codes.AlreadyExists},
+		{errorCode: "insufficient_balance", httpStatus: http.StatusBadRequest, expected: codes.FailedPrecondition},
+		{errorCode: "business_day_closed", httpStatus: http.StatusConflict, expected: codes.FailedPrecondition},
+		{errorCode: "round_closed", httpStatus: http.StatusConflict, expected: codes.FailedPrecondition},
+		{errorCode: "round_mismatch", httpStatus: http.StatusConflict, expected: codes.FailedPrecondition},
+		{errorCode: "concurrent_update", httpStatus: http.StatusConflict, expected: codes.Aborted},
+		{errorCode: "retry_exhausted", httpStatus: http.StatusServiceUnavailable, expected: codes.Unavailable},
+		{errorCode: "invalid_amount", httpStatus: http.StatusBadRequest, expected: codes.InvalidArgument},
+		{errorCode: "loss_limit_exceeded", httpStatus: http.StatusForbidden, expected: codes.PermissionDenied},
+		{errorCode: "account_closed", httpStatus: http.StatusForbidden, expected: codes.PermissionDenied},
+		{errorCode: "internal_error", httpStatus: http.StatusInternalServerError, expected: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.errorCode, func(t *testing.T) {
+			assert.Equal(t, tt.expected, grpcCode(tt.httpStatus, tt.errorCode))
+		})
+	}
+}
+
+func TestGRPCErrorCarriesErrorResponse(t *testing.T) {
+	err := grpcError(codes.NotFound, dto.ErrorResponse{Error: "user_not_found", Message: "User does not exist"})
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "User does not exist", st.Message())
+	require.Len(t, st.Details(), 1)
+	detail, ok := st.Details()[0].(*balancev1.Error)
+	require.True(t, ok)
+	assert.Equal(t, "user_not_found", detail.GetError())
+}
+
+func newGRPCTestClient(t *testing.T) balancev1.BalanceServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	balancev1.RegisterBalanceServiceServer(server, &GRPCHandler{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return balancev1.NewBalanceServiceClient(conn)
+}
+
+func TestGRPCProcessTransactionValidation(t *testing.T) {
+	client := newGRPCTestClient(t)
+
+	tests := []struct {
+		name         string
+		request      *balancev1.TransactionRequest
+		expectedCode string
+	}{
+		{
+			name:         "missing user",
+			request:      &balancev1.TransactionRequest{SourceType: "game", State: "win", Amount: "1.00", TransactionId: "tx-1"},
+			expectedCode: "invalid_user_id",
+		},
+		{
+			name:         "invalid source type",
+			request:      &balancev1.TransactionRequest{UserId: 1, SourceType: "casino", State: "win", Amount: "1.00", TransactionId: "tx-1"},
+			expectedCode: "invalid_source_type",
+		},
+		{
+			name:         "invalid state",
+			request:      &balancev1.TransactionRequest{UserId: 1, SourceType: "game", State: "draw", Amount: "1.00", TransactionId: "tx-1"},
+			expectedCode: "invalid_state",
+		},
+		{
+			name:         "too many decimals",
+			request:      &balancev1.TransactionRequest{UserId: 1, SourceType: "game", State: "win", Amount: "1.001", TransactionId: "tx-1"},
+			expectedCode: "invalid_amount",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ProcessTransaction(context.Background(), tt.request)
+
+			st := status.Convert(err)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+			require.Len(t, st.Details(), 1)
+			assert.Equal(t, tt.expectedCode, st.Details()[0].(*balancev1.Error).GetError())
+		})
+	}
+}
+
+func TestGRPCProcessTransactionsReportsRejectionsInline(t *testing.T) {
+	client := newGRPCTestClient(t)
+
+	stream, err := client.ProcessTransactions(context.Background())
+	require.NoError(t, err)
+
+	requests := []*balancev1.TransactionRequest{
+		{UserId: 1, SourceType: "game", State: "win", Amount: "-1", TransactionId: "tx-1"},
+		{UserId: 1, SourceType: "game", State: "win", Amount: "1.00"},
+	}
+	for _, req := range requests {
+		require.NoError(t, stream.Send(req))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "tx-1", first.GetTransactionId())
+	assert.False(t, first.GetSuccess())
+	assert.Equal(t, "invalid_amount", first.GetError().GetError())
+
+	second, err := stream.Recv()
+	require.NoError(t, err)
+	assert.False(t, second.GetSuccess())
+	assert.Equal(t, "missing_transaction_id", second.GetError().GetError())
+}
+
+func TestTransactionMessage(t *testing.T) {
+	booked := transactionMessage("tx-1", "booked")
+	assert.True(t, booked.GetSuccess())
+	assert.Equal(t, "booked", booked.GetStatus())
+	assert.Equal(t, "Transaction processed successfully", booked.GetMessage())
+
+	held := transactionMessage("tx-2", "pending")
+	assert.True(t, held.GetSuccess())
+	assert.Equal(t, "tx-2", held.GetTransactionId())
+	assert.Equal(t, "pending", held.GetStatus())
+	assert.Equal(t, "Transaction is held for review", held.GetMessage())
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/service"
+)
+
+type LimitHandler struct {
+	limitService *service.LimitService
+}
+
+func NewLimitHandler() *LimitHandler {
+	return &LimitHandler{
+		limitService: service.NewLimitService(),
+	}
+}
+
+func (h *LimitHandler) GetLimits(c echo.Context) error {
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		})
+	}
+
+	limits, err := h.limitService.GetLimits(userID)
+	if err != nil {
+		return h.limitError(c, err, "Failed to get limits")
+	}
+
+	return c.JSON(http.StatusOK, limits)
+}
+
+func (h *LimitHandler) SetLimit(c echo.Context) error {
+	userID, limitType, period, validationErr := h.validateLimitPath(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	var req dto.SetLimitRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request_body",
+			Message: "Invalid JSON format",
+		})
+	}
+
+	if validationErr := validationError(c.Validate(&req)); validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+			Details: validationErr.Details,
+		})
+	}
+
+	limit, err := h.limitService.SetLimit(userID, limitType, period, req.Amount, limitActor(c))
+	if err != nil {
+		return h.limitError(c, err, "Failed to set limit")
+	}
+
+	return c.JSON(http.StatusOK, limit)
+}
+
+func (h *LimitHandler) RemoveLimit(c echo.Context) error {
+	userID, limitType, period, validationErr := h.validateLimitPath(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	limit, err := h.limitService.RemoveLimit(userID, limitType, period, limitActor(c))
+	if err != nil {
+		return h.limitError(c, err, "Failed to remove limit")
+	}
+
+	return c.JSON(http.StatusOK, limit)
+}
+
+func (h *LimitHandler) limitError(c echo.Context, err error, message string) error {
+	switch err.Error() {
+	case "user not found":
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "user_not_found",
+			Message: "User does not exist",
+		})
+	case "limit not found":
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "limit_not_found",
+			Message: "User has no such limit",
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: message,
+		})
+	}
+}
+
+func (h *LimitHandler) validateLimitPath(c echo.Context) (uint64, string, string, *ValidationError) {
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return 0, "", "", &ValidationError{
+			Code:    "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		}
+	}
+
+	limitType := c.Param("type")
+	if limitType != model.LimitLoss && limitType != model.LimitDeposit {
+		return 0, "", "", &ValidationError{
+			Code:    "invalid_limit_type",
+			Message: "Limit type must be 'loss' or 'deposit'",
+		}
+	}
+
+	period := c.Param("period")
+	if period != model.PeriodDaily && period != model.PeriodWeekly && period != model.PeriodMonthly {
+		return 0, "", "", &ValidationError{
+			Code:    "invalid_limit_period",
+			Message: "Limit period must be one of: daily, weekly, monthly",
+		}
+	}
+
+	return userID, limitType, period, nil
+}
+
+// limitActor identifies the operator changing a limit on the user's behalf
+// through the Operator-ID header. The actor has no ID when the user changes
+// their own limit.
+func limitActor(c echo.Context) service.Actor {
+	actor := auditActor(c)
+	actor.ID = c.Request().Header.Get("Operator-ID")
+	return actor
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLimitPath(t *testing.T) {
+	handler := &LimitHandler{}
+
+	tests := []struct {
+		name           string
+		userID         string
+		limitType      string
+		period         string
+		expectedUserID uint64
+		expectedError  *ValidationError
+	}{
+		{
+			name:           "valid loss limit",
+			userID:         "1",
+			limitType:      "loss",
+			period:         "daily",
+			expectedUserID: 1,
+		},
+		{
+			name:           "valid deposit limit",
+			userID:         "2",
+			limitType:      "deposit",
+			period:         "monthly",
+			expectedUserID: 2,
+		},
+		{
+			name:      "invalid user ID",
+			userID:    "abc",
+			limitType: "loss",
+			period:    "daily",
+			expectedError: &ValidationError{
+				Code:    "invalid_user_id",
+				Message: "User ID must be a positive integer",
+			},
+		},
+		{
+			name:      "invalid limit type",
+			userID:    "1",
+			limitType: "wager",
+			period:    "daily",
+			expectedError: &ValidationError{
+				Code:    "invalid_limit_type",
+				Message: "Limit type must be 'loss' or 'deposit'",
+			},
+		},
+		{
+			name:      "invalid period",
+			userID:    "1",
+			limitType: "loss",
+			period:    "yearly",
+			expectedError: &ValidationError{
+				Code:    "invalid_limit_period",
+				Message: "Limit period must be one of: daily, weekly, monthly",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("userId", "type", "period")
+			c.SetParamValues(tt.userID, tt.limitType, tt.period)
+
+			userID, limitType, period, validationErr := handler.validateLimitPath(c)
+
+			if tt.expectedError != nil {
+				assert.NotNil(t, validationErr)
+				assert.Equal(t, tt.expectedError.Code, validationErr.Code)
+				assert.Equal(t, tt.expectedError.Message, validationErr.Message)
+			} else {
+				assert.Nil(t, validationErr)
+				assert.Equal(t, tt.expectedUserID, userID)
+				assert.Equal(t, tt.limitType, limitType)
+				assert.Equal(t, tt.period, period)
+			}
+		})
+	}
+}
+
+func TestLimitActor(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPut, "/", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+	assert.Equal(t, service.Actor{}, limitActor(c))
+
+	req = httptest.NewRequest(http.MethodPut, "/", nil)
+	req.Header.Set("Operator-ID", "alice")
+	req.Header.Set(echo.HeaderXRequestID, "req-1")
+	c = e.NewContext(req, httptest.NewRecorder())
+	assert.Equal(t, service.Actor{ID: "alice", RequestID: "req-1"}, limitActor(c))
+}
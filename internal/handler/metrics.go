@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"expvar"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Metrics serves the expvar counters, among them the transaction retries, as
+// JSON.
+func Metrics(c echo.Context) error {
+	expvar.Handler().ServeHTTP(c.Response(), c.Request())
+	return nil
+}
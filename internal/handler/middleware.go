@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/service"
+)
+
+const operatorContextKey = "operatorID"
+
+// RequireOperator rejects admin requests that do not identify the operator
+// performing them through the Operator-ID header.
+func RequireOperator(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		operatorID := c.Request().Header.Get("Operator-ID")
+		if operatorID == "" {
+			return c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error:   "missing_operator",
+				Message: "Operator-ID header is required",
+			})
+		}
+
+		c.Set(operatorContextKey, operatorID)
+		return next(c)
+	}
+}
+
+func operatorID(c echo.Context) string {
+	operatorID, _ := c.Get(operatorContextKey).(string)
+	return operatorID
+}
+
+// auditActor identifies the operator and the request for the audit log. The
+// request ID is the one assigned by the RequestID middleware, falling back to
+// the one the client sent.
+func auditActor(c echo.Context) service.Actor {
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+	if requestID == "" {
+		requestID = c.Request().Header.Get(echo.HeaderXRequestID)
+	}
+	return service.Actor{ID: operatorID(c), RequestID: requestID}
+}
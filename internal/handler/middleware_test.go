@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireOperator(t *testing.T) {
+	e := echo.New()
+	next := func(c echo.Context) error {
+		return c.String(http.StatusOK, operatorID(c))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/users/1/status-history", nil)
+	rec := httptest.NewRecorder()
+	err := RequireOperator(next)(e.NewContext(req, rec))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "missing_operator")
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/users/1/status-history", nil)
+	req.Header.Set("Operator-ID", "alice")
+	rec = httptest.NewRecorder()
+	err = RequireOperator(next)(e.NewContext(req, rec))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "alice", rec.Body.String())
+}
+
+func TestAuditActor(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/users", nil)
+	req.Header.Set(echo.HeaderXRequestID, "client-request")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set(operatorContextKey, "alice")
+
+	actor := auditActor(c)
+	assert.Equal(t, "alice", actor.ID)
+	assert.Equal(t, "client-request", actor.RequestID)
+
+	rec.Header().Set(echo.HeaderXRequestID, "server-request")
+	assert.Equal(t, "server-request", auditActor(c).RequestID)
+}
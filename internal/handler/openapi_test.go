@@ -0,0 +1,572 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/api"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	openapi3filter.RegisterBodyDecoder("text/html", decodeText)
+	openapi3filter.RegisterBodyDecoder("text/event-stream", decodeText)
+}
+
+func decodeText(body io.Reader, _ http.Header, _ *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (any, error) {
+	data, err := io.ReadAll(body)
+	return string(data), err
+}
+
+func loadOpenAPIRouter(t *testing.T) routers.Router {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromData(api.OpenAPI)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+	return router
+}
+
+func requestValidationInput(t *testing.T, router routers.Router, req *http.Request) *openapi3filter.RequestValidationInput {
+	t.Helper()
+
+	route, pathParams, err := router.FindRoute(req)
+	require.NoError(t, err, "%s %s is not documented", req.Method, req.URL.Path)
+	return &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+	}
+}
+
+// serveAgainstSpec runs a request through the handler and checks the
+// response, and the request when validRequest is set, against the document.
+func serveAgainstSpec(t *testing.T, router routers.Router, handler echo.HandlerFunc, route, method, target, body string, headers map[string]string, validRequest bool) *httptest.ResponseRecorder {
+	t.Helper()
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(method, "http://localhost:8080"+target, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		return req
+	}
+
+	input := requestValidationInput(t, router, newRequest())
+	requestErr := openapi3filter.ValidateRequest(context.Background(), input)
+	if validRequest {
+		assert.NoError(t, requestErr)
+	} else {
+		assert.Error(t, requestErr)
+	}
+
+	e := echo.New()
+	e.Validator = NewRequestValidator()
+	e.Add(method, route, handler)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, newRequest())
+
+	responseErr := openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 rec.Code,
+		Header:                 rec.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+	})
+	assert.NoError(t, responseErr, "response %d %s", rec.Code, rec.Body.String())
+	return rec
+}
+
+func TestOpenAPIErrorResponses(t *testing.T) {
+	router := loadOpenAPIRouter(t)
+	userHandler := &UserHandler{}
+	limitHandler := &LimitHandler{}
+	webhookHandler := &WebhookHandler{}
+	statementHandler := &StatementHandler{}
+	closingHandler := &ClosingHandler{}
+	auditHandler := &AuditHandler{}
+	adjustmentHandler := &AdjustmentHandler{}
+	reviewHandler := &ReviewHandler{}
+	operator := map[string]string{"Operator-ID": "alice"}
+
+	tests := []struct {
+		name         string
+		handler      echo.HandlerFunc
+		route        string
+		method       string
+		target       string
+		body         string
+		headers      map[string]string
+		validRequest bool
+		expectedCode int
+	}{
+		{
+			name:         "balance with non-numeric user",
+			handler:      userHandler.GetBalance,
+			route:        "/user/:userId/balance",
+			method:       http.MethodGet,
+			target:       "/user/abc/balance",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "balance at an unparseable time",
+			handler:      userHandler.GetBalance,
+			route:        "/user/:userId/balance",
+			method:       http.MethodGet,
+			target:       "/user/1/balance?at=yesterday",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "transaction without Source-Type",
+			handler:      userHandler.ProcessTransaction,
+			route:        "/user/:userId/transaction",
+			method:       http.MethodPost,
+			target:       "/user/1/transaction",
+			body:         `{"state": "win", "amount": "10.00", "transactionId": "tx-1"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "transaction with unknown state",
+			handler:      userHandler.ProcessTransaction,
+			route:        "/user/:userId/transaction",
+			method:       http.MethodPost,
+			target:       "/user/1/transaction",
+			body:         `{"state": "draw", "amount": "10.00", "transactionId": "tx-1"}`,
+			headers:      map[string]string{"Source-Type": "game"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "transaction with three decimals",
+			handler:      userHandler.ProcessTransaction,
+			route:        "/user/:userId/transaction",
+			method:       http.MethodPost,
+			target:       "/user/1/transaction",
+			body:         `{"state": "win", "amount": "10.001", "transactionId": "tx-1"}`,
+			headers:      map[string]string{"Source-Type": "game"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "stream with invalid Last-Event-ID",
+			handler:      userHandler.StreamBalance,
+			route:        "/user/:userId/balance/stream",
+			method:       http.MethodGet,
+			target:       "/user/1/balance/stream?lastEventId=abc",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "limit with unknown type",
+			handler:      limitHandler.SetLimit,
+			route:        "/user/:userId/limits/:type/:period",
+			method:       http.MethodPut,
+			target:       "/user/1/limits/bet/daily",
+			body:         `{"amount": "100.00"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "admin request without operator",
+			handler:      RequireOperator(userHandler.ListUsers),
+			route:        "/admin/users",
+			method:       http.MethodGet,
+			target:       "/admin/users",
+			validRequest: true,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "users page out of range",
+			handler:      RequireOperator(userHandler.ListUsers),
+			route:        "/admin/users",
+			method:       http.MethodGet,
+			target:       "/admin/users?pageSize=1000",
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "webhook with relative URL",
+			handler:      RequireOperator(webhookHandler.CreateWebhook),
+			route:        "/admin/webhooks",
+			method:       http.MethodPost,
+			target:       "/admin/webhooks",
+			body:         `{"url": "/hooks", "eventTypes": ["transaction.processed"]}`,
+			headers:      operator,
+			validRequest: true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "deliveries with unknown status",
+			handler:      RequireOperator(webhookHandler.ListDeliveries),
+			route:        "/admin/webhooks/deliveries",
+			method:       http.MethodGet,
+			target:       "/admin/webhooks/deliveries?status=lost",
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "statement with unknown format",
+			handler:      RequireOperator(statementHandler.GetStatement),
+			route:        "/admin/users/:userId/statement",
+			method:       http.MethodGet,
+			target:       "/admin/users/1/statement?from=2025-06-01&to=2025-06-30&format=pdf",
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "statement with reversed range",
+			handler:      RequireOperator(statementHandler.GetStatement),
+			route:        "/admin/users/:userId/statement",
+			method:       http.MethodGet,
+			target:       "/admin/users/1/statement?from=2025-06-30&to=2025-06-01",
+			headers:      operator,
+			validRequest: true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "closing with malformed date",
+			handler:      RequireOperator(closingHandler.GetClosingReport),
+			route:        "/admin/closings/:date",
+			method:       http.MethodGet,
+			target:       "/admin/closings/30-06-2025",
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "round with overlong ID",
+			handler:      userHandler.GetRound,
+			route:        "/rounds/:roundId",
+			method:       http.MethodGet,
+			target:       "/rounds/" + strings.Repeat("r", 256),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "transaction lookup without source type",
+			handler:      userHandler.GetTransaction,
+			route:        "/transactions/:transactionId",
+			method:       http.MethodGet,
+			target:       "/transactions/tx-1",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "user transaction lookup with overlong ID",
+			handler:      userHandler.GetUserTransaction,
+			route:        "/user/:userId/transactions/:transactionId",
+			method:       http.MethodGet,
+			target:       "/user/1/transactions/" + strings.Repeat("t", 256),
+			headers:      map[string]string{"Source-Type": "game"},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "adjustment with unknown reason code",
+			handler:      RequireOperator(adjustmentHandler.RequestAdjustment),
+			route:        "/admin/users/:userId/adjustments",
+			method:       http.MethodPost,
+			target:       "/admin/users/1/adjustments",
+			body:         `{"direction":"credit","amount":"10.00","reasonCode":"bored"}`,
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "adjustment with malformed amount",
+			handler:      RequireOperator(adjustmentHandler.RequestAdjustment),
+			route:        "/admin/users/:userId/adjustments",
+			method:       http.MethodPost,
+			target:       "/admin/users/1/adjustments",
+			body:         `{"direction":"debit","amount":"1.005","reasonCode":"correction"}`,
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "adjustments with unknown status",
+			handler:      RequireOperator(adjustmentHandler.ListAdjustments),
+			route:        "/admin/adjustments",
+			method:       http.MethodGet,
+			target:       "/admin/adjustments?status=done",
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "approve with malformed adjustment ID",
+			handler:      RequireOperator(adjustmentHandler.ApproveAdjustment),
+			route:        "/admin/adjustments/:adjustmentId/approve",
+			method:       http.MethodPost,
+			target:       "/admin/adjustments/abc/approve",
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "reviews with unknown status",
+			handler:      RequireOperator(reviewHandler.ListReviews),
+			route:        "/admin/reviews",
+			method:       http.MethodGet,
+			target:       "/admin/reviews?status=held",
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "review with malformed ID",
+			handler:      RequireOperator(reviewHandler.GetReview),
+			route:        "/admin/reviews/:reviewId",
+			method:       http.MethodGet,
+			target:       "/admin/reviews/abc",
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "decline with malformed review ID",
+			handler:      RequireOperator(reviewHandler.DeclineReview),
+			route:        "/admin/reviews/:reviewId/decline",
+			method:       http.MethodPost,
+			target:       "/admin/reviews/abc/decline",
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "audit with malformed from",
+			handler:      RequireOperator(auditHandler.ListEntries),
+			route:        "/admin/audit",
+			method:       http.MethodGet,
+			target:       "/admin/audit?from=2025-06-01",
+			headers:      operator,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "audit with empty time range",
+			handler:      RequireOperator(auditHandler.ListEntries),
+			route:        "/admin/audit",
+			method:       http.MethodGet,
+			target:       "/admin/audit?from=2025-06-02T00:00:00Z&to=2025-06-01T00:00:00Z",
+			headers:      operator,
+			validRequest: true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "metrics",
+			handler:      RequireOperator(Metrics),
+			route:        "/admin/metrics",
+			method:       http.MethodGet,
+			target:       "/admin/metrics",
+			headers:      operator,
+			validRequest: true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "OpenAPI document",
+			handler:      OpenAPISpec,
+			route:        "/openapi.json",
+			method:       http.MethodGet,
+			target:       "/openapi.json",
+			validRequest: true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "docs page",
+			handler:      Docs,
+			route:        "/docs",
+			method:       http.MethodGet,
+			target:       "/docs",
+			validRequest: true,
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveAgainstSpec(t, router, tt.handler, tt.route, tt.method, tt.target, tt.body, tt.headers, tt.validRequest)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestOpenAPITransactionRequestMatchesHandlerValidation(t *testing.T) {
+	router := loadOpenAPIRouter(t)
+
+	bodies := []string{
+		`{"state": "win", "amount": "10.00", "transactionId": "tx-1"}`,
+		`{"state": "lose", "amount": "5", "transactionId": "tx-2"}`,
+		`{"state": "lose", "amount": "0.5", "transactionId": "tx-3"}`,
+		`{"state": "draw", "amount": "10.00", "transactionId": "tx-4"}`,
+		`{"amount": "10.00", "transactionId": "tx-5"}`,
+		`{"state": "win", "amount": "10.00"}`,
+		`{"state": "win", "amount": "10.001", "transactionId": "tx-6"}`,
+		`{"state": "win", "amount": "-1", "transactionId": "tx-7"}`,
+		`{"state": "win", "amount": "", "transactionId": "tx-8"}`,
+		`{"state": "win", "amount": "1.00", "transactionId": "round 9"}`,
+		`{"state": "win", "amount": "1.00", "transactionId": "-tx-10"}`,
+		`{"state": "win", "amount": "1.00", "transactionId": "game:42/round.7_bet-1"}`,
+		`{"state": "win", "amount": "1.00", "transactionId": "game:42:round.7_bet-1"}`,
+		`{"state": "lose", "amount": "1.00", "transactionId": "bet-11", "roundId": "r-1", "gameId": "starburst", "provider": "netent"}`,
+		`{"state": "win", "amount": "1.00", "transactionId": "win-12", "roundId": "r-1", "gameId": "starburst", "provider": "netent", "closeRound": true}`,
+		`{"state": "lose", "amount": "1.00", "transactionId": "bet-13", "roundId": "r-1", "gameId": "starburst"}`,
+		`{"state": "lose", "amount": "1.00", "transactionId": "bet-14", "roundId": "r 1", "gameId": "starburst", "provider": "netent"}`,
+		`{"state": "win", "amount": "1.00", "transactionId": "win-15", "closeRound": true}`,
+		`{"state": "win", "amount": "1.00", "transactionId": "win-16", "closeRound": false}`,
+		`{"state": "win", "amount": "1.00", "transactionId": "win-17", "gameId": "starburst"}`,
+		`{"state": "win", "amount": "NaN", "transactionId": "tx-18"}`,
+		`{"state": "win", "amount": "Inf", "transactionId": "tx-19"}`,
+		`{"state": "win", "amount": "1e3", "transactionId": "tx-20"}`,
+		`{"state": "win", "amount": "+5", "transactionId": "tx-21"}`,
+		`{"state": "win", "amount": "9999999999999.99", "transactionId": "tx-22"}`,
+		`{"state": "win", "amount": "10000000000000", "transactionId": "tx-23"}`,
+	}
+
+	for _, body := range bodies {
+		t.Run(body, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/user/1/transaction", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("Source-Type", "game")
+			specErr := openapi3filter.ValidateRequest(context.Background(), requestValidationInput(t, router, req))
+
+			var parsed dto.TransactionRequest
+			require.NoError(t, json.Unmarshal([]byte(body), &parsed))
+			handlerErr := validateTransactionFields(parsed)
+
+			assert.Equal(t, handlerErr == nil, specErr == nil, "handler: %v, spec: %v", handlerErr, specErr)
+		})
+	}
+}
+
+func TestOpenAPISuccessResponses(t *testing.T) {
+	router := loadOpenAPIRouter(t)
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	wallets := dto.WalletBalances{Cash: "10.00", Bonus: "5.00", Locked: "0.00"}
+	pending := "50.00"
+	userID := uint64(1)
+	reviewer := "bob"
+	adjustmentTransaction := "adjustment-3"
+
+	tests := []struct {
+		method string
+		target string
+		status int
+		body   interface{}
+	}{
+		{http.MethodGet, "/user/1/balance", http.StatusOK, dto.BalanceResponse{UserID: 1, Balance: "15.00", Wallets: wallets}},
+		{http.MethodGet, "/user/1/balance?at=2025-07-02T12:00:00Z", http.StatusOK, dto.HistoricalBalanceResponse{UserID: 1, Balance: "0.00", At: now}},
+		{http.MethodGet, "/user/1/balance?at=2025-07-02T12:00:00Z", http.StatusOK, dto.HistoricalBalanceResponse{UserID: 1, Balance: "15.00", At: now, LastTransaction: &dto.HistoricalTransaction{
+			TransactionID: "tx-1", State: "win", Amount: "15.00", SourceType: "game", CreatedAt: now,
+		}}},
+		{http.MethodGet, "/transactions/tx-1", http.StatusOK, dto.TransactionLookupResponse{
+			TransactionID: "tx-1", UserID: 1, Status: "booked", State: "win", Amount: "50.00", SourceType: "game", BalanceAfter: "150.00", CreatedAt: now,
+		}},
+		{http.MethodGet, "/user/1/transactions/tx-77", http.StatusOK, dto.TransactionLookupResponse{
+			TransactionID: "tx-77", UserID: 1, Status: "declined", State: "win", Amount: "2500.00", SourceType: "server", CreatedAt: now, DecidedAt: &now,
+		}},
+		{http.MethodGet, "/rounds/r-1", http.StatusOK, dto.RoundResponse{
+			RoundID: "r-1", UserID: 1, GameID: "starburst", Provider: "netent", Status: "closed", Bets: "1.00", Wins: "2.50", Net: "1.50",
+			Transactions: []dto.RoundTransaction{
+				{TransactionID: "bet-1", State: "lose", Amount: "1.00", CreatedAt: now},
+				{TransactionID: "win-1", State: "win", Amount: "2.50", CreatedAt: now},
+			},
+			CreatedAt: now, ClosedAt: &now,
+		}},
+		{http.MethodPost, "/admin/rounds/r-1/rollback", http.StatusOK, dto.RoundResponse{
+			RoundID: "r-1", UserID: 1, GameID: "starburst", Provider: "netent", Status: "rolled_back", Bets: "1.00", Wins: "1.00", Net: "0.00",
+			Transactions: []dto.RoundTransaction{
+				{TransactionID: "bet-1", State: "lose", Amount: "1.00", CreatedAt: now},
+				{TransactionID: "rollback:1", State: "win", Amount: "1.00", CreatedAt: now},
+			},
+			CreatedAt: now, ClosedAt: &now,
+		}},
+		{http.MethodPost, "/user/1/transaction", http.StatusOK, dto.TransactionResponse{Success: true, Status: "booked", Message: "Transaction processed successfully"}},
+		{http.MethodPost, "/user/1/transaction", http.StatusAccepted, dto.TransactionResponse{Success: true, Status: "pending", Message: "Transaction is held for review"}},
+		{http.MethodGet, "/user/1/bonuses", http.StatusOK, dto.BonusProgressResponse{UserID: 1, Bonuses: []dto.BonusProgress{{
+			ID: 1, Amount: "5.00", WageringRequired: "150.00", Wagered: "15.00", WageringRemaining: "135.00",
+			Progress: 10, Status: "active", ExpiresAt: now, CreatedAt: now,
+		}}}},
+		{http.MethodGet, "/user/1/limits", http.StatusOK, dto.LimitsResponse{UserID: 1, Limits: []dto.LimitResponse{{
+			Type: "loss", Period: "daily", Amount: "20.00", PendingAmount: &pending, PendingEffectiveAt: &now, SetBy: "user", UpdatedAt: now,
+		}}}},
+		{http.MethodGet, "/admin/users/1", http.StatusOK, dto.UserDetailsResponse{
+			ID: 1, Metadata: json.RawMessage(`{"country":"LV"}`), Balance: "15.00", Status: "active", CreatedAt: now, UpdatedAt: now,
+		}},
+		{http.MethodPut, "/admin/users/1/status", http.StatusOK, dto.AccountStatusResponse{UserID: 1, Status: "self_excluded", ExcludedUntil: &now}},
+		{http.MethodGet, "/admin/users/1/status-history", http.StatusOK, dto.StatusHistoryResponse{UserID: 1, Changes: []dto.StatusChangeResponse{{
+			ID: 1, FromStatus: "active", ToStatus: "suspended", Reason: "review", Actor: "alice", CreatedAt: now,
+		}}}},
+		{http.MethodGet, "/admin/users/1/chain/verify", http.StatusOK, dto.ChainVerificationResponse{UserID: 1, Intact: true, Verified: 12}},
+		{http.MethodGet, "/admin/users/1/chain/verify", http.StatusOK, dto.ChainVerificationResponse{UserID: 1, Verified: 3, BrokenLink: &dto.ChainBreak{
+			ID: 4, TransactionID: "tx-4", Reason: "hash_mismatch", ExpectedHash: "ab12", ActualHash: "cd34",
+		}}},
+		{http.MethodGet, "/admin/closings/2025-06-30", http.StatusOK, dto.ClosingReportResponse{
+			BusinessDate: "2025-06-30", TimeZone: "UTC", PeriodStart: now, PeriodEnd: now, ClosedAt: now, Users: 1,
+			OpeningBalance: "10.00", ClosingBalance: "15.00",
+			Totals:   []dto.ClosingTotal{{SourceType: "game", State: "win", Transactions: 1, Amount: "5.00"}},
+			Balances: []dto.ClosingBalance{{UserID: 1, OpeningBalance: "10.00", ClosingBalance: "15.00"}},
+			Page:     1, PageSize: 50,
+		}},
+		{http.MethodPost, "/admin/users/1/adjustments", http.StatusCreated, dto.AdjustmentResponse{
+			ID: 3, UserID: 1, Direction: "credit", Amount: "25.00", ReasonCode: "goodwill", Status: "pending", RequestedBy: "alice", CreatedAt: now,
+		}},
+		{http.MethodPost, "/admin/adjustments/3/approve", http.StatusOK, dto.AdjustmentResponse{
+			ID: 3, UserID: 1, Direction: "credit", Amount: "25.00", ReasonCode: "goodwill", Status: "approved", RequestedBy: "alice",
+			ReviewedBy: &reviewer, ReviewedAt: &now, TransactionID: &adjustmentTransaction, CreatedAt: now,
+		}},
+		{http.MethodGet, "/admin/adjustments?status=pending", http.StatusOK, dto.AdjustmentListResponse{Adjustments: []dto.AdjustmentResponse{{
+			ID: 4, UserID: 2, Direction: "debit", Amount: "5.00", ReasonCode: "correction", Note: "double payout", Status: "pending", RequestedBy: "alice", CreatedAt: now,
+		}}, Page: 1, PageSize: 50, Total: 1}},
+		{http.MethodGet, "/admin/reviews?status=pending", http.StatusOK, dto.ReviewListResponse{Reviews: []dto.ReviewResponse{{
+			ID: 5, UserID: 1, TransactionID: "tx-77", SourceType: "server", State: "win", Amount: "2500.00",
+			Rule: "server-win-burst", Reason: "6 wins within 1m0s, at most 5 allowed", Status: "pending", CreatedAt: now,
+		}}, Page: 1, PageSize: 50, Total: 1}},
+		{http.MethodPost, "/admin/reviews/5/decline", http.StatusOK, dto.ReviewResponse{
+			ID: 5, UserID: 1, TransactionID: "tx-77", SourceType: "server", State: "win", Amount: "2500.00",
+			Rule: "server-win-burst", Reason: "6 wins within 1m0s, at most 5 allowed", Status: "declined",
+			ReviewedBy: &reviewer, ReviewedAt: &now, CreatedAt: now,
+		}},
+		{http.MethodGet, "/admin/audit?actor=alice&userId=1", http.StatusOK, dto.AuditListResponse{Entries: []dto.AuditEntryResponse{
+			{
+				ID: 2, Actor: "alice", Action: "account.status_change", TargetUserID: &userID, Resource: "account_status_changes/1",
+				Before: json.RawMessage(`{"userId":1,"status":"active"}`), After: json.RawMessage(`{"userId":1,"status":"suspended"}`),
+				RequestID: "3f2a", CreatedAt: now,
+			},
+			{
+				ID: 1, Actor: "alice", Action: "user.create", TargetUserID: &userID, Resource: "users/1",
+				After: json.RawMessage(`{"id":1,"status":"active"}`), CreatedAt: now,
+			},
+		}, Page: 1, PageSize: 50, Total: 2}},
+		{http.MethodPost, "/admin/webhooks", http.StatusCreated, dto.WebhookResponse{
+			ID: 1, URL: "https://partner.example.com/hooks", EventTypes: []string{"*"}, Secret: "s3cret", Active: true, CreatedBy: "alice", CreatedAt: now,
+		}},
+		{http.MethodPost, "/admin/webhooks/deliveries/1/redeliver", http.StatusAccepted, dto.WebhookDeliveryResponse{
+			ID: 1, SubscriptionID: 1, EventType: "balance.changed", DedupeKey: "balance.changed:tx-1", Status: "pending",
+			NextAttemptAt: now, Payload: json.RawMessage(`{"id":1}`), CreatedAt: now,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			encoded, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tt.method, "http://localhost:8080"+tt.target, nil)
+			header := http.Header{}
+			header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: requestValidationInput(t, router, req),
+				Status:                 tt.status,
+				Header:                 header,
+				Body:                   io.NopCloser(bytes.NewReader(encoded)),
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
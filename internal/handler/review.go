@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/service"
+)
+
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+}
+
+func NewReviewHandler() *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: service.NewReviewService(),
+	}
+}
+
+func (h *ReviewHandler) ListReviews(c echo.Context) error {
+	status := c.QueryParam("status")
+	switch status {
+	case "", model.ReviewPending, model.ReviewApproved, model.ReviewDeclined:
+	default:
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_status",
+			Message: "Status must be one of: pending, approved, declined",
+		})
+	}
+
+	var userID uint64
+	if value := c.QueryParam("userId"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_user_id",
+				Message: "User ID must be a positive integer",
+			})
+		}
+		userID = parsed
+	}
+
+	page, pageSize, validationErr := parsePagination(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	reviews, err := h.reviewService.ListReviews(status, userID, page, pageSize)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list reviews",
+		})
+	}
+
+	return c.JSON(http.StatusOK, reviews)
+}
+
+func (h *ReviewHandler) GetReview(c echo.Context) error {
+	reviewID, validationErr := parseReviewID(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	review, err := h.reviewService.GetReview(reviewID)
+	if err != nil {
+		return reviewError(c, err, "Failed to get review")
+	}
+
+	return c.JSON(http.StatusOK, review)
+}
+
+func (h *ReviewHandler) ApproveReview(c echo.Context) error {
+	reviewID, req, validationErr := parseDecisionRequest(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	review, err := h.reviewService.ApproveReview(reviewID, req, auditActor(c))
+	if err != nil {
+		return reviewError(c, err, "Failed to approve review")
+	}
+
+	return c.JSON(http.StatusOK, review)
+}
+
+func (h *ReviewHandler) DeclineReview(c echo.Context) error {
+	reviewID, req, validationErr := parseDecisionRequest(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	review, err := h.reviewService.DeclineReview(reviewID, req, auditActor(c))
+	if err != nil {
+		return reviewError(c, err, "Failed to decline review")
+	}
+
+	return c.JSON(http.StatusOK, review)
+}
+
+// reviewError maps queue errors and, for approvals, the rejections of the
+// booked transaction to responses.
+func reviewError(c echo.Context, err error, message string) error {
+	switch err.Error() {
+	case "review not found":
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "review_not_found",
+			Message: "Review does not exist",
+		})
+	case "review already decided":
+		return c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "review_already_decided",
+			Message: "Review has already been approved or declined",
+		})
+	}
+
+	status, response := transactionErrorResponse(err)
+	if status == http.StatusInternalServerError {
+		response.Message = message
+	}
+	return c.JSON(status, response)
+}
+
+func parseReviewID(c echo.Context) (uint64, *ValidationError) {
+	reviewID, err := strconv.ParseUint(c.Param("reviewId"), 10, 64)
+	if err != nil {
+		return 0, &ValidationError{
+			Code:    "invalid_review_id",
+			Message: "Review ID must be a positive integer",
+		}
+	}
+	return reviewID, nil
+}
+
+// parseDecisionRequest reads the review ID and the optional decision note.
+func parseDecisionRequest(c echo.Context) (uint64, dto.DecideReviewRequest, *ValidationError) {
+	reviewID, validationErr := parseReviewID(c)
+	if validationErr != nil {
+		return 0, dto.DecideReviewRequest{}, validationErr
+	}
+
+	var req dto.DecideReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return 0, dto.DecideReviewRequest{}, &ValidationError{
+			Code:    "invalid_request_body",
+			Message: "Invalid JSON format",
+		}
+	}
+	return reviewID, req, nil
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+)
+
+func (h *UserHandler) GetRound(c echo.Context) error {
+	roundID := c.Param("roundId")
+	if !validLookupID(roundID) {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_round_id",
+			Message: "Round ID must be 1 to 255 characters long",
+		})
+	}
+
+	round, err := h.userService.GetRound(roundID)
+	if err != nil {
+		return roundError(c, err, "Failed to get round")
+	}
+
+	return c.JSON(http.StatusOK, round)
+}
+
+func (h *UserHandler) RollbackRound(c echo.Context) error {
+	roundID := c.Param("roundId")
+	if !validLookupID(roundID) {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_round_id",
+			Message: "Round ID must be 1 to 255 characters long",
+		})
+	}
+
+	round, err := h.userService.RollbackRound(roundID, auditActor(c))
+	if err != nil {
+		return roundError(c, err, "Failed to roll back round")
+	}
+
+	return c.JSON(http.StatusOK, round)
+}
+
+func roundError(c echo.Context, err error, message string) error {
+	switch err.Error() {
+	case "round not found":
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "round_not_found",
+			Message: "Round does not exist",
+		})
+	case "round already rolled back":
+		return c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "round_rolled_back",
+			Message: "Round has already been rolled back",
+		})
+	case "bonus already converted":
+		return c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "bonus_converted",
+			Message: "A bet of the round counted towards a bonus that has already converted to cash",
+		})
+	}
+
+	status, response := transactionErrorResponse(err)
+	if status == http.StatusInternalServerError {
+		response.Message = message
+	}
+	return c.JSON(status, response)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/service"
+	"github.com/lielamurs/balance-transactions/internal/statement"
+)
+
+type StatementHandler struct {
+	statementService *service.StatementService
+}
+
+func NewStatementHandler() *StatementHandler {
+	return &StatementHandler{
+		statementService: service.NewStatementService(),
+	}
+}
+
+// GetStatement streams a statement straight into the response. Errors found
+// before the first byte is written get a JSON error; later ones can only cut
+// the response short, and are logged by the service.
+func (h *StatementHandler) GetStatement(c echo.Context) error {
+	userID, format, validationErr := validateStatementQuery(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	from, to, err := statement.ParseRange(c.QueryParam("from"), c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_date_range",
+			Message: err.Error(),
+		})
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, statement.ContentType(format))
+	if format == statement.FormatCSV {
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="statement-%d-%s.csv"`, userID, from.Format("20060102")))
+	}
+
+	writer, err := statement.NewWriter(format, res)
+	if err == nil {
+		err = h.statementService.WriteStatement(userID, from, to, writer)
+	}
+	if err == nil || res.Committed {
+		return nil
+	}
+
+	res.Header().Del(echo.HeaderContentType)
+	res.Header().Del(echo.HeaderContentDisposition)
+	switch err.Error() {
+	case "user not found":
+		return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "user_not_found",
+			Message: "User does not exist",
+		})
+	default:
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to generate statement",
+		})
+	}
+}
+
+func validateStatementQuery(c echo.Context) (uint64, string, *ValidationError) {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		return 0, "", &ValidationError{
+			Code:    "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		}
+	}
+
+	format := c.QueryParam("format")
+	switch format {
+	case "":
+		format = statement.FormatJSON
+	case statement.FormatCSV, statement.FormatJSON, statement.FormatHTML:
+	default:
+		return 0, "", &ValidationError{
+			Code:    "invalid_format",
+			Message: "Format must be one of: csv, json, html",
+		}
+	}
+
+	return userID, format, nil
+}
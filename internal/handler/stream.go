@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/config"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+)
+
+// StreamBalance pushes the user's balance as Server-Sent Events each time a
+// transaction for the user commits, with a comment line as heartbeat so
+// proxies keep the connection open.
+func (h *UserHandler) StreamBalance(c echo.Context) error {
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		})
+	}
+
+	lastEventID, validationErr := parseLastEventID(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	subscription, initial, err := h.userService.SubscribeBalance(userID, lastEventID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "user_not_found",
+				Message: "User does not exist",
+			})
+		default:
+			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to open balance stream",
+			})
+		}
+	}
+	defer subscription.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	for _, message := range initial {
+		if _, err := res.Write(message.Encode()); err != nil {
+			return nil
+		}
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(config.Get().StreamHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-subscription.C():
+			if !ok {
+				return nil
+			}
+			if _, err := res.Write(message.Encode()); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-heartbeat.C:
+			if _, err := res.Write([]byte(": heartbeat\n\n")); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// parseLastEventID reads the Last-Event-ID header sent by reconnecting
+// EventSource clients, or the lastEventId query parameter for clients that
+// cannot set headers.
+func parseLastEventID(c echo.Context) (uint64, *ValidationError) {
+	value := c.Request().Header.Get("Last-Event-ID")
+	if value == "" {
+		value = c.QueryParam("lastEventId")
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	lastEventID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, &ValidationError{
+			Code:    "invalid_last_event_id",
+			Message: "Last-Event-ID must be a positive integer",
+		}
+	}
+	return lastEventID, nil
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLastEventID(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		query        string
+		expected     uint64
+		expectedCode string
+	}{
+		{name: "fresh connection", expected: 0},
+		{name: "header", header: "42", expected: 42},
+		{name: "query parameter", query: "?lastEventId=7", expected: 7},
+		{name: "header wins over query", header: "42", query: "?lastEventId=7", expected: 42},
+		{name: "not a number", header: "abc", expectedCode: "invalid_last_event_id"},
+		{name: "negative", query: "?lastEventId=-1", expectedCode: "invalid_last_event_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/user/1/balance/stream"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("Last-Event-ID", tt.header)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			lastEventID, validationErr := parseLastEventID(c)
+
+			if tt.expectedCode != "" {
+				assert.NotNil(t, validationErr)
+				assert.Equal(t, tt.expectedCode, validationErr.Code)
+			} else {
+				assert.Nil(t, validationErr)
+				assert.Equal(t, tt.expected, lastEventID)
+			}
+		})
+	}
+}
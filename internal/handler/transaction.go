@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+)
+
+func (h *UserHandler) GetTransaction(c echo.Context) error {
+	return h.lookupTransaction(c, 0)
+}
+
+func (h *UserHandler) GetUserTransaction(c echo.Context) error {
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		})
+	}
+	return h.lookupTransaction(c, userID)
+}
+
+// lookupTransaction answers for the source named in the Source-Type header;
+// transactions of other sources are not found.
+func (h *UserHandler) lookupTransaction(c echo.Context, userID uint64) error {
+	transactionID := c.Param("transactionId")
+	if !validLookupID(transactionID) {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_transaction_id",
+			Message: "Transaction ID must be 1 to 255 characters long",
+		})
+	}
+
+	sourceType := c.Request().Header.Get("Source-Type")
+	if sourceType == "" {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "missing_header",
+			Message: "Source-Type header is required",
+		})
+	}
+	if validationErr := validateSourceType(sourceType); validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	transaction, err := h.userService.GetTransaction(transactionID, sourceType, userID)
+	if err != nil {
+		switch err.Error() {
+		case "transaction not found":
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "transaction_not_found",
+				Message: "Transaction does not exist",
+			})
+		default:
+			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to get transaction",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, transaction)
+}
@@ -2,12 +2,16 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
 	"github.com/lielamurs/balance-transactions/internal/service"
 )
 
@@ -31,6 +35,10 @@ func (h *UserHandler) GetBalance(c echo.Context) error {
 		})
 	}
 
+	if c.QueryParam("at") != "" {
+		return h.getBalanceAt(c, userID)
+	}
+
 	balance, err := h.userService.GetBalance(userID)
 	if err != nil {
 		switch err.Error() {
@@ -50,8 +58,8 @@ func (h *UserHandler) GetBalance(c echo.Context) error {
 	return c.JSON(http.StatusOK, balance)
 }
 
-func (h *UserHandler) ProcessTransaction(c echo.Context) error {
-	userID, sourceType, req, validationErr := h.validateTransactionRequest(c)
+func (h *UserHandler) getBalanceAt(c echo.Context, userID uint64) error {
+	at, validationErr := parseBalanceAt(c.QueryParam("at"))
 	if validationErr != nil {
 		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error:   validationErr.Code,
@@ -59,40 +67,225 @@ func (h *UserHandler) ProcessTransaction(c echo.Context) error {
 		})
 	}
 
-	if err := h.userService.ProcessTransaction(userID, req, sourceType); err != nil {
+	balance, err := h.userService.GetBalanceAt(userID, at)
+	if err != nil {
 		switch err.Error() {
 		case "user not found":
 			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
 				Error:   "user_not_found",
 				Message: "User does not exist",
 			})
-		case "transaction already processed":
-			return c.JSON(http.StatusConflict, dto.ErrorResponse{
-				Error:   "duplicate_transaction",
-				Message: "Transaction with this ID has already been processed",
+		default:
+			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to get balance",
 			})
-		case "insufficient balance":
-			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				Error:   "insufficient_balance",
-				Message: "Account balance cannot be negative",
+		}
+	}
+
+	return c.JSON(http.StatusOK, balance)
+}
+
+func parseBalanceAt(value string) (time.Time, *ValidationError) {
+	at, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, &ValidationError{
+			Code:    "invalid_timestamp",
+			Message: "At must be an RFC 3339 timestamp such as 2025-06-01T12:00:00Z",
+		}
+	}
+	return at.UTC(), nil
+}
+
+func (h *UserHandler) GetBonuses(c echo.Context) error {
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		})
+	}
+
+	progress, err := h.userService.GetBonusProgress(userID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "user_not_found",
+				Message: "User does not exist",
 			})
 		default:
 			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 				Error:   "internal_error",
-				Message: "Failed to process transaction",
+				Message: "Failed to get bonus progress",
 			})
 		}
 	}
 
-	return c.JSON(http.StatusOK, dto.TransactionResponse{
-		Success: true,
-		Message: "Transaction processed successfully",
-	})
+	return c.JSON(http.StatusOK, progress)
+}
+
+func (h *UserHandler) ProcessTransaction(c echo.Context) error {
+	userID, sourceType, req, validationErr := h.validateTransactionRequest(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+			Details: validationErr.Details,
+		})
+	}
+
+	result, err := h.userService.ProcessTransaction(userID, req, sourceType)
+	if err != nil {
+		status, response := transactionErrorResponse(err)
+		return c.JSON(status, response)
+	}
+
+	if result == model.TransactionPending {
+		return c.JSON(http.StatusAccepted, transactionResult(result))
+	}
+	return c.JSON(http.StatusOK, transactionResult(result))
+}
+
+// transactionResult is the success body of a booked or held transaction.
+func transactionResult(status string) dto.TransactionResponse {
+	if status == model.TransactionPending {
+		return dto.TransactionResponse{Success: true, Status: status, Message: "Transaction is held for review"}
+	}
+	return dto.TransactionResponse{Success: true, Status: status, Message: "Transaction processed successfully"}
+}
+
+// transactionErrorResponse maps a ProcessTransaction error to its HTTP status
+// and error body. The gRPC API reports the same codes.
+func transactionErrorResponse(err error) (int, dto.ErrorResponse) {
+	if errors.Is(err, service.ErrRetriesExhausted) {
+		return http.StatusServiceUnavailable, dto.ErrorResponse{
+			Error:   "retry_exhausted",
+			Message: "Database kept failing with serialization failures or deadlocks, retry the transaction later",
+		}
+	}
+
+	switch err.Error() {
+	case "user not found":
+		return http.StatusNotFound, dto.ErrorResponse{
+			Error:   "user_not_found",
+			Message: "User does not exist",
+		}
+	case "transaction already processed":
+		return http.StatusConflict, dto.ErrorResponse{
+			Error:   "duplicate_transaction",
+			Message: "Transaction with this ID has already been processed",
+		}
+	case "business day closed":
+		return http.StatusConflict, dto.ErrorResponse{
+			Error:   "business_day_closed",
+			Message: "Transaction falls into a business day that is already closed",
+		}
+	case "balance changed concurrently":
+		return http.StatusConflict, dto.ErrorResponse{
+			Error:   "concurrent_update",
+			Message: "Balance kept changing concurrently, retry the transaction",
+		}
+	case "round has no bet":
+		return http.StatusConflict, dto.ErrorResponse{
+			Error:   "round_has_no_bet",
+			Message: "A round's win cannot precede its bet",
+		}
+	case "round closed":
+		return http.StatusConflict, dto.ErrorResponse{
+			Error:   "round_closed",
+			Message: "Round is closed and accepts no further transactions",
+		}
+	case "round mismatch":
+		return http.StatusConflict, dto.ErrorResponse{
+			Error:   "round_mismatch",
+			Message: "Round belongs to a different user, game or provider",
+		}
+	case "round requires game source":
+		return http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_round_source",
+			Message: "Only game transactions can belong to a round",
+		}
+	case "insufficient balance":
+		return http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "insufficient_balance",
+			Message: "Account balance cannot be negative",
+		}
+	case "loss limit exceeded":
+		return http.StatusForbidden, dto.ErrorResponse{
+			Error:   "loss_limit_exceeded",
+			Message: "Transaction would exceed the user's loss limit",
+		}
+	case "deposit limit exceeded":
+		return http.StatusForbidden, dto.ErrorResponse{
+			Error:   "deposit_limit_exceeded",
+			Message: "Transaction would exceed the user's deposit limit",
+		}
+	case "account suspended":
+		return http.StatusForbidden, dto.ErrorResponse{
+			Error:   "account_suspended",
+			Message: "Account is suspended and does not accept this transaction",
+		}
+	case "account self-excluded":
+		return http.StatusForbidden, dto.ErrorResponse{
+			Error:   "account_self_excluded",
+			Message: "Account is self-excluded and does not accept this transaction",
+		}
+	case "account closed":
+		return http.StatusForbidden, dto.ErrorResponse{
+			Error:   "account_closed",
+			Message: "Account is closed",
+		}
+	case "transaction rejected by rule":
+		return http.StatusForbidden, ruleErrorResponse(err)
+	case "transaction rejected by fraud check":
+		return http.StatusForbidden, fraudErrorResponse(err)
+	default:
+		return http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to process transaction",
+		}
+	}
+}
+
+// ruleErrorResponse reports which transaction rule rejected a transaction.
+// The rule's own message is used when it has one.
+func ruleErrorResponse(err error) dto.ErrorResponse {
+	var ruleErr *service.RuleError
+	if !errors.As(err, &ruleErr) {
+		return dto.ErrorResponse{Error: "transaction_rejected", Message: "Transaction was rejected by a rule"}
+	}
+
+	message := ruleErr.Rule.Message
+	if message == "" {
+		message = fmt.Sprintf("Transaction was rejected by rule %s", ruleErr.Rule.ID)
+	}
+	return dto.ErrorResponse{
+		Error:   "transaction_rejected",
+		Message: message,
+		Details: []dto.FieldError{{Field: "rule", Code: ruleErr.Rule.ID, Message: message}},
+	}
+}
+
+// fraudErrorResponse reports which fraud check rejected a transaction and
+// why.
+func fraudErrorResponse(err error) dto.ErrorResponse {
+	response := dto.ErrorResponse{
+		Error:   "fraud_rejected",
+		Message: "Transaction was rejected by a fraud check",
+	}
+	var fraudErr *service.FraudError
+	if errors.As(err, &fraudErr) {
+		response.Details = []dto.FieldError{{Field: "check", Code: fraudErr.Hit.CheckID, Message: fraudErr.Hit.Reason}}
+	}
+	return response
 }
 
 type ValidationError struct {
 	Code    string
 	Message string
+	Details []dto.FieldError
 }
 
 func (e ValidationError) Error() string {
@@ -117,12 +310,8 @@ func (h *UserHandler) validateTransactionRequest(c echo.Context) (uint64, string
 		}
 	}
 
-	validSources := map[string]bool{"game": true, "server": true, "payment": true}
-	if !validSources[sourceType] {
-		return 0, "", dto.TransactionRequest{}, &ValidationError{
-			Code:    "invalid_source_type",
-			Message: "Source-Type must be one of: game, server, payment",
-		}
+	if validationErr := validateSourceType(sourceType); validationErr != nil {
+		return 0, "", dto.TransactionRequest{}, validationErr
 	}
 
 	var req dto.TransactionRequest
@@ -133,55 +322,65 @@ func (h *UserHandler) validateTransactionRequest(c echo.Context) (uint64, string
 		}
 	}
 
-	if req.State == "" {
-		return 0, "", dto.TransactionRequest{}, &ValidationError{
-			Code:    "missing_state",
-			Message: "State field is required",
-		}
+	if validationErr := validationError(c.Validate(&req)); validationErr != nil {
+		return 0, "", dto.TransactionRequest{}, validationErr
 	}
 
-	if req.State != "win" && req.State != "lose" {
-		return 0, "", dto.TransactionRequest{}, &ValidationError{
-			Code:    "invalid_state",
-			Message: "State must be 'win' or 'lose'",
-		}
-	}
-
-	if req.TransactionID == "" {
-		return 0, "", dto.TransactionRequest{}, &ValidationError{
-			Code:    "missing_transaction_id",
-			Message: "TransactionId field is required",
-		}
-	}
+	return userID, sourceType, req, nil
+}
 
-	if err := h.validateAmount(req.Amount); err != nil {
-		return 0, "", dto.TransactionRequest{}, &ValidationError{
-			Code:    "invalid_amount",
-			Message: err.Error(),
+func validateSourceType(sourceType string) *ValidationError {
+	validSources := map[string]bool{"game": true, "server": true, "payment": true}
+	if !validSources[sourceType] {
+		return &ValidationError{
+			Code:    "invalid_source_type",
+			Message: "Source-Type must be one of: game, server, payment",
 		}
 	}
+	return nil
+}
 
-	return userID, sourceType, req, nil
+// validateTransactionFields checks a transaction body outside of an Echo
+// request, as the gRPC handler does.
+func validateTransactionFields(req dto.TransactionRequest) *ValidationError {
+	return validationError(defaultValidator.Validate(&req))
 }
 
-func (h *UserHandler) validateAmount(amount string) error {
+// amountPattern is the shape of a money amount: plain digits with an optional
+// fraction, no sign, exponent, spaces or special values such as NaN.
+var amountPattern = regexp.MustCompile(`^(\d+)(?:\.(\d+))?$`)
+
+// maxAmountDigits is the number of digits before the decimal point that fit
+// the DECIMAL(15,2) balance and amount columns.
+const maxAmountDigits = 13
+
+func validateAmount(amount string) error {
 	if amount == "" {
 		return errors.New("amount is required")
 	}
 
+	unsigned := strings.TrimPrefix(amount, "-")
+	parts := amountPattern.FindStringSubmatch(unsigned)
+	if parts == nil {
+		return errors.New("invalid amount format")
+	}
+	if unsigned != amount {
+		return errors.New("amount must be positive")
+	}
+	if len(parts[2]) > 2 {
+		return errors.New("amount can have at most 2 decimal places")
+	}
+	if len(parts[1]) > maxAmountDigits {
+		return fmt.Errorf("amount can have at most %d digits before the decimal point", maxAmountDigits)
+	}
+
 	value, err := strconv.ParseFloat(amount, 64)
 	if err != nil {
 		return errors.New("invalid amount format")
 	}
-
 	if value <= 0 {
 		return errors.New("amount must be positive")
 	}
 
-	parts := strings.Split(amount, ".")
-	if len(parts) == 2 && len(parts[1]) > 2 {
-		return errors.New("amount can have at most 2 decimal places")
-	}
-
 	return nil
 }
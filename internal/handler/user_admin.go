@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+func (h *UserHandler) CreateUser(c echo.Context) error {
+	req, validationErr := h.validateCreateUserRequest(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	user, err := h.userService.CreateUser(req, auditActor(c))
+	if err != nil {
+		switch err.Error() {
+		case "user already exists":
+			return c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "user_exists",
+				Message: "A user with this ID or external reference already exists",
+			})
+		default:
+			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to create user",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, user)
+}
+
+func (h *UserHandler) GetUser(c echo.Context) error {
+	userIDStr := c.Param("userId")
+	userID, err := strconv.ParseUint(userIDStr, 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		})
+	}
+
+	user, err := h.userService.GetUserDetails(userID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "user_not_found",
+				Message: "User does not exist",
+			})
+		default:
+			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to get user",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, user)
+}
+
+func (h *UserHandler) ListUsers(c echo.Context) error {
+	page, pageSize, validationErr := parsePagination(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	users, err := h.userService.ListUsers(page, pageSize)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list users",
+		})
+	}
+
+	return c.JSON(http.StatusOK, users)
+}
+
+func (h *UserHandler) validateCreateUserRequest(c echo.Context) (dto.CreateUserRequest, *ValidationError) {
+	var req dto.CreateUserRequest
+	if err := c.Bind(&req); err != nil {
+		return dto.CreateUserRequest{}, &ValidationError{
+			Code:    "invalid_request_body",
+			Message: "Invalid JSON format",
+		}
+	}
+
+	if req.ID != nil && *req.ID == 0 {
+		return dto.CreateUserRequest{}, &ValidationError{
+			Code:    "invalid_user_id",
+			Message: "User ID must be a positive integer",
+		}
+	}
+
+	if req.ExternalRef != nil && (*req.ExternalRef == "" || len(*req.ExternalRef) > 255) {
+		return dto.CreateUserRequest{}, &ValidationError{
+			Code:    "invalid_external_ref",
+			Message: "ExternalRef must be between 1 and 255 characters",
+		}
+	}
+
+	if len(req.Metadata) > 0 {
+		var metadata map[string]interface{}
+		if err := json.Unmarshal(req.Metadata, &metadata); err != nil || metadata == nil {
+			return dto.CreateUserRequest{}, &ValidationError{
+				Code:    "invalid_metadata",
+				Message: "Metadata must be a JSON object",
+			}
+		}
+	}
+
+	return req, nil
+}
+
+func parsePagination(c echo.Context) (int, int, *ValidationError) {
+	page := 1
+	if value := c.QueryParam("page"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return 0, 0, &ValidationError{
+				Code:    "invalid_pagination",
+				Message: "Page must be a positive integer",
+			}
+		}
+		page = parsed
+	}
+
+	pageSize := defaultPageSize
+	if value := c.QueryParam("pageSize"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPageSize {
+			return 0, 0, &ValidationError{
+				Code:    "invalid_pagination",
+				Message: "PageSize must be between 1 and 500",
+			}
+		}
+		pageSize = parsed
+	}
+
+	return page, pageSize, nil
+}
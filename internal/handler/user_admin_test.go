@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateUserRequest(t *testing.T) {
+	handler := &UserHandler{}
+
+	tests := []struct {
+		name          string
+		jsonBody      string
+		expectedError *ValidationError
+	}{
+		{
+			name:     "empty body",
+			jsonBody: `{}`,
+		},
+		{
+			name:     "all fields",
+			jsonBody: `{"id": 42, "externalRef": "crm-42", "metadata": {"country": "LV"}}`,
+		},
+		{
+			name:     "invalid JSON",
+			jsonBody: `{"id": }`,
+			expectedError: &ValidationError{
+				Code:    "invalid_request_body",
+				Message: "Invalid JSON format",
+			},
+		},
+		{
+			name:     "zero ID",
+			jsonBody: `{"id": 0}`,
+			expectedError: &ValidationError{
+				Code:    "invalid_user_id",
+				Message: "User ID must be a positive integer",
+			},
+		},
+		{
+			name:     "empty external reference",
+			jsonBody: `{"externalRef": ""}`,
+			expectedError: &ValidationError{
+				Code:    "invalid_external_ref",
+				Message: "ExternalRef must be between 1 and 255 characters",
+			},
+		},
+		{
+			name:     "metadata is not an object",
+			jsonBody: `{"metadata": [1, 2]}`,
+			expectedError: &ValidationError{
+				Code:    "invalid_metadata",
+				Message: "Metadata must be a JSON object",
+			},
+		},
+		{
+			name:     "metadata is null",
+			jsonBody: `{"metadata": null}`,
+			expectedError: &ValidationError{
+				Code:    "invalid_metadata",
+				Message: "Metadata must be a JSON object",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/users", strings.NewReader(tt.jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			_, validationErr := handler.validateCreateUserRequest(c)
+
+			if tt.expectedError != nil {
+				assert.NotNil(t, validationErr)
+				assert.Equal(t, tt.expectedError.Code, validationErr.Code)
+				assert.Equal(t, tt.expectedError.Message, validationErr.Message)
+			} else {
+				assert.Nil(t, validationErr)
+			}
+		})
+	}
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name             string
+		query            string
+		expectedPage     int
+		expectedPageSize int
+		expectError      bool
+	}{
+		{name: "defaults", query: "", expectedPage: 1, expectedPageSize: 50},
+		{name: "explicit values", query: "?page=3&pageSize=20", expectedPage: 3, expectedPageSize: 20},
+		{name: "zero page", query: "?page=0", expectError: true},
+		{name: "non-numeric page", query: "?page=abc", expectError: true},
+		{name: "page size too large", query: "?pageSize=501", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/admin/users"+tt.query, nil)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			page, pageSize, validationErr := parsePagination(c)
+
+			if tt.expectError {
+				assert.NotNil(t, validationErr)
+				assert.Equal(t, "invalid_pagination", validationErr.Code)
+			} else {
+				assert.Nil(t, validationErr)
+				assert.Equal(t, tt.expectedPage, page)
+				assert.Equal(t, tt.expectedPageSize, pageSize)
+			}
+		})
+	}
+}
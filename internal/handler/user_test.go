@@ -1,19 +1,21 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/service"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestValidateAmount(t *testing.T) {
-	handler := &UserHandler{}
-
 	tests := []struct {
 		name        string
 		amount      string
@@ -111,11 +113,94 @@ func TestValidateAmount(t *testing.T) {
 			expectError: true,
 			errorMsg:    "invalid amount format",
 		},
+		{
+			name:        "NaN",
+			amount:      "NaN",
+			expectError: true,
+			errorMsg:    "invalid amount format",
+		},
+		{
+			name:        "lowercase nan",
+			amount:      "nan",
+			expectError: true,
+			errorMsg:    "invalid amount format",
+		},
+		{
+			name:        "infinity",
+			amount:      "Inf",
+			expectError: true,
+			errorMsg:    "invalid amount format",
+		},
+		{
+			name:        "signed infinity",
+			amount:      "+Inf",
+			expectError: true,
+			errorMsg:    "invalid amount format",
+		},
+		{
+			name:        "exponent",
+			amount:      "1e3",
+			expectError: true,
+			errorMsg:    "invalid amount format",
+		},
+		{
+			name:        "decimal exponent",
+			amount:      "1.5E2",
+			expectError: true,
+			errorMsg:    "invalid amount format",
+		},
+		{
+			name:        "plus sign",
+			amount:      "+5",
+			expectError: true,
+			errorMsg:    "invalid amount format",
+		},
+		{
+			name:        "negative zero",
+			amount:      "-0",
+			expectError: true,
+			errorMsg:    "amount must be positive",
+		},
+		{
+			name:        "hexadecimal",
+			amount:      "0x10",
+			expectError: true,
+			errorMsg:    "invalid amount format",
+		},
+		{
+			name:        "underscore separator",
+			amount:      "1_000",
+			expectError: true,
+			errorMsg:    "invalid amount format",
+		},
+		{
+			name:        "missing fraction digits",
+			amount:      "5.",
+			expectError: true,
+			errorMsg:    "invalid amount format",
+		},
+		{
+			name:        "missing integer digits",
+			amount:      ".50",
+			expectError: true,
+			errorMsg:    "invalid amount format",
+		},
+		{
+			name:        "too many digits",
+			amount:      "12345678901234",
+			expectError: true,
+			errorMsg:    "amount can have at most 13 digits before the decimal point",
+		},
+		{
+			name:        "largest amount",
+			amount:      "9999999999999.99",
+			expectError: false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := handler.validateAmount(tt.amount)
+			err := validateAmount(tt.amount)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -330,6 +415,7 @@ func TestValidateTransactionRequest(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create Echo context
 			e := echo.New()
+			e.Validator = NewRequestValidator()
 			req := httptest.NewRequest(http.MethodPost, "/user/"+tt.userID+"/transaction", strings.NewReader(tt.jsonBody))
 			req.Header.Set("Content-Type", "application/json")
 			if tt.sourceType != "" {
@@ -360,3 +446,79 @@ func TestValidateTransactionRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBalanceAt(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{
+			name:     "UTC timestamp",
+			value:    "2025-06-01T12:00:00Z",
+			expected: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "offset is normalised to UTC",
+			value:    "2025-06-01T14:00:00+02:00",
+			expected: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "fractional seconds",
+			value:    "2025-06-01T12:00:00.250Z",
+			expected: time.Date(2025, 6, 1, 12, 0, 0, 250000000, time.UTC),
+		},
+		{name: "date only", value: "2025-06-01", expectError: true},
+		{name: "unix seconds", value: "1748779200", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, validationErr := parseBalanceAt(tt.value)
+			if tt.expectError {
+				assert.NotNil(t, validationErr)
+				assert.Equal(t, "invalid_timestamp", validationErr.Code)
+				return
+			}
+			assert.Nil(t, validationErr)
+			assert.Equal(t, tt.expected, at)
+		})
+	}
+}
+
+func TestTransactionErrorResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "lost optimistic race",
+			err:            errors.New("balance changed concurrently"),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   "concurrent_update",
+		},
+		{
+			name:           "retries exhausted",
+			err:            fmt.Errorf("%w: %w", service.ErrRetriesExhausted, errors.New("deadlock detected")),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   "retry_exhausted",
+		},
+		{
+			name:           "unexpected database error",
+			err:            errors.New("failed to update balance: connection reset"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   "internal_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := transactionErrorResponse(tt.err)
+			assert.Equal(t, tt.expectedStatus, status)
+			assert.Equal(t, tt.expectedCode, response.Error)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+)
+
+var transactionIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]{0,254}$`)
+
+// maxIDLength is the size of the VARCHAR(255) transaction and round ID
+// columns.
+const maxIDLength = 255
+
+// validLookupID reports whether id can name a stored transaction or round.
+// transactionIDPattern only applies to new submissions: IDs stored before it
+// was enforced must still be found, so lookups only check the length.
+func validLookupID(id string) bool {
+	return id != "" && len(id) <= maxIDLength
+}
+
+// RequestValidator enforces the validate tags on request DTOs. It is
+// registered as the Echo validator and also used by the gRPC handler.
+type RequestValidator struct {
+	validate *validator.Validate
+}
+
+var defaultValidator = NewRequestValidator()
+
+func NewRequestValidator() *RequestValidator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	validate.RegisterValidation("amount", func(fl validator.FieldLevel) bool {
+		return validateAmount(fl.Field().String()) == nil
+	})
+	validate.RegisterValidation("transaction_id", func(fl validator.FieldLevel) bool {
+		return transactionIDPattern.MatchString(fl.Field().String())
+	})
+
+	return &RequestValidator{validate: validate}
+}
+
+func (v *RequestValidator) Validate(i interface{}) error {
+	return v.validate.Struct(i)
+}
+
+// validationError converts the result of Validate into a ValidationError
+// listing every failed field. Code and Message repeat the first failure so
+// clients that only read those keep working.
+func validationError(err error) *ValidationError {
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return &ValidationError{
+			Code:    "invalid_request_body",
+			Message: err.Error(),
+		}
+	}
+
+	details := make([]dto.FieldError, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		details = append(details, fieldError(fieldErr))
+	}
+
+	return &ValidationError{
+		Code:    details[0].Code,
+		Message: details[0].Message,
+		Details: details,
+	}
+}
+
+func fieldError(fieldErr validator.FieldError) dto.FieldError {
+	field := fieldErr.Field()
+	label := capitalize(field)
+	value := fmt.Sprint(fieldErr.Value())
+
+	switch fieldErr.Tag() {
+	case "required":
+		return dto.FieldError{
+			Field:   field,
+			Code:    "missing_" + snakeCase(field),
+			Message: label + " field is required",
+		}
+	case "oneof":
+		options := strings.Fields(fieldErr.Param())
+		message := label + " must be one of: " + strings.Join(options, ", ")
+		if len(options) == 2 {
+			message = fmt.Sprintf("%s must be '%s' or '%s'", label, options[0], options[1])
+		}
+		return dto.FieldError{
+			Field:   field,
+			Code:    "invalid_" + snakeCase(field),
+			Message: message,
+		}
+	case "required_with":
+		return dto.FieldError{
+			Field:   field,
+			Code:    "missing_" + snakeCase(field),
+			Message: fmt.Sprintf("%s field is required when %s is set", label, jsonFieldName(fieldErr.Param())),
+		}
+	case "excluded_without":
+		return dto.FieldError{
+			Field:   field,
+			Code:    "invalid_" + snakeCase(field),
+			Message: fmt.Sprintf("%s is only allowed when %s is set", label, jsonFieldName(fieldErr.Param())),
+		}
+	case "max":
+		return dto.FieldError{
+			Field:   field,
+			Code:    "invalid_" + snakeCase(field),
+			Message: fmt.Sprintf("%s must be at most %s characters", label, fieldErr.Param()),
+		}
+	case "amount":
+		return dto.FieldError{
+			Field:   field,
+			Code:    "invalid_" + snakeCase(field),
+			Message: validateAmount(value).Error(),
+		}
+	case "transaction_id":
+		return dto.FieldError{
+			Field:   field,
+			Code:    "invalid_" + snakeCase(field),
+			Message: label + " must be at most 255 letters, digits, '.', '_', ':' or '-' and start with a letter or digit",
+		}
+	default:
+		return dto.FieldError{
+			Field:   field,
+			Code:    "invalid_" + snakeCase(field),
+			Message: fmt.Sprintf("%s failed the %s check", label, fieldErr.Tag()),
+		}
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// jsonFieldName turns a Go field name used as a tag parameter, such as
+// RoundID, into its JSON name roundId.
+func jsonFieldName(s string) string {
+	s = strings.ReplaceAll(s, "ID", "Id")
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// snakeCase turns a JSON field name such as transactionId into
+// transaction_id for use in error codes.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTransactionFieldsReportsEveryField(t *testing.T) {
+	validationErr := validateTransactionFields(dto.TransactionRequest{
+		State:         "draw",
+		Amount:        "1.234",
+		TransactionID: "has space",
+	})
+
+	assert.NotNil(t, validationErr)
+	assert.Equal(t, "invalid_state", validationErr.Code)
+	assert.Equal(t, "State must be 'win' or 'lose'", validationErr.Message)
+	assert.Equal(t, []dto.FieldError{
+		{Field: "state", Code: "invalid_state", Message: "State must be 'win' or 'lose'"},
+		{Field: "amount", Code: "invalid_amount", Message: "amount can have at most 2 decimal places"},
+		{Field: "transactionId", Code: "invalid_transaction_id", Message: "TransactionId must be at most 255 letters, digits, '.', '_', ':' or '-' and start with a letter or digit"},
+	}, validationErr.Details)
+}
+
+func TestValidateTransactionFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       dto.TransactionRequest
+		expectedCodes []string
+	}{
+		{
+			name:    "valid",
+			request: dto.TransactionRequest{State: "win", Amount: "10.50", TransactionID: "tx-001"},
+		},
+		{
+			name:    "namespaced transaction ID",
+			request: dto.TransactionRequest{State: "lose", Amount: "1", TransactionID: "game:42:round.7_bet-1"},
+		},
+		{
+			name:          "empty body",
+			request:       dto.TransactionRequest{},
+			expectedCodes: []string{"missing_state", "invalid_amount", "missing_transaction_id"},
+		},
+		{
+			name:          "transaction ID too long",
+			request:       dto.TransactionRequest{State: "win", Amount: "1.00", TransactionID: strings.Repeat("a", 256)},
+			expectedCodes: []string{"invalid_transaction_id"},
+		},
+		{
+			name:          "transaction ID starting with a separator",
+			request:       dto.TransactionRequest{State: "win", Amount: "1.00", TransactionID: ".tx"},
+			expectedCodes: []string{"invalid_transaction_id"},
+		},
+		{
+			name: "round transaction",
+			request: dto.TransactionRequest{
+				State: "lose", Amount: "1.00", TransactionID: "bet-1",
+				RoundID: "r-1", GameID: "starburst", Provider: "netent", CloseRound: true,
+			},
+		},
+		{
+			name:          "round without game and provider",
+			request:       dto.TransactionRequest{State: "lose", Amount: "1.00", TransactionID: "bet-1", RoundID: "r-1"},
+			expectedCodes: []string{"missing_game_id", "missing_provider"},
+		},
+		{
+			name:          "closing without a round",
+			request:       dto.TransactionRequest{State: "win", Amount: "1.00", TransactionID: "win-1", CloseRound: true},
+			expectedCodes: []string{"invalid_close_round"},
+		},
+		{
+			name:          "round ID with a space",
+			request:       dto.TransactionRequest{State: "lose", Amount: "1.00", TransactionID: "bet-1", RoundID: "r 1", GameID: "g", Provider: "p"},
+			expectedCodes: []string{"invalid_round_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validationErr := validateTransactionFields(tt.request)
+
+			if len(tt.expectedCodes) == 0 {
+				assert.Nil(t, validationErr)
+				return
+			}
+
+			assert.NotNil(t, validationErr)
+			var codes []string
+			for _, detail := range validationErr.Details {
+				codes = append(codes, detail.Code)
+			}
+			assert.Equal(t, tt.expectedCodes, codes)
+		})
+	}
+}
+
+func TestRoundFieldMessages(t *testing.T) {
+	validationErr := validateTransactionFields(dto.TransactionRequest{
+		State: "win", Amount: "1.00", TransactionID: "win-1", GameID: strings.Repeat("g", 256), CloseRound: true,
+	})
+
+	assert.NotNil(t, validationErr)
+	assert.Equal(t, []dto.FieldError{
+		{Field: "gameId", Code: "invalid_game_id", Message: "GameId must be at most 255 characters"},
+		{Field: "closeRound", Code: "invalid_close_round", Message: "CloseRound is only allowed when roundId is set"},
+	}, validationErr.Details)
+
+	validationErr = validateTransactionFields(dto.TransactionRequest{State: "win", Amount: "1.00", TransactionID: "win-1", RoundID: "r-1", Provider: "p"})
+	assert.Equal(t, "GameId field is required when roundId is set", validationErr.Message)
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "transaction_id", snakeCase("transactionId"))
+	assert.Equal(t, "state", snakeCase("state"))
+	assert.Equal(t, "excluded_until", snakeCase("excludedUntil"))
+}
+
+func TestValidLookupID(t *testing.T) {
+	assert.True(t, validLookupID("tx-1"))
+	assert.True(t, validLookupID("-legacy id/1"), "IDs stored before the pattern was enforced can still be looked up")
+	assert.True(t, validLookupID(strings.Repeat("t", 255)))
+	assert.False(t, validLookupID(""))
+	assert.False(t, validLookupID(strings.Repeat("t", 256)))
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/service"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: service.NewWebhookService(),
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	req, validationErr := h.validateCreateWebhookRequest(c)
+	if validationErr != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   validationErr.Code,
+			Message: validationErr.Message,
+		})
+	}
+
+	webhook, err := h.webhookService.CreateSubscription(req, auditActor(c))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to create webhook",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, webhook)
+}
+
+func (h *WebhookHandler) ListWebhooks(c echo.Context) error {
+	webhooks, err := h.webhookService.ListSubscriptions()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list webhooks",
+		})
+	}
+
+	return c.JSON(http.StatusOK, webhooks)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	webhookID, err := strconv.ParseUint(c.Param("webhookId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_webhook_id",
+			Message: "Webhook ID must be a positive integer",
+		})
+	}
+
+	if err := h.webhookService.DeleteSubscription(webhookID, auditActor(c)); err != nil {
+		switch err.Error() {
+		case "webhook not found":
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "webhook_not_found",
+				Message: "Webhook does not exist or is already inactive",
+			})
+		default:
+			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to delete webhook",
+			})
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	status := c.QueryParam("status")
+	switch status {
+	case "", model.DeliveryPending, model.DeliveryDelivered, model.DeliveryDead:
+	default:
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_status",
+			Message: "Status must be one of: pending, delivered, dead",
+		})
+	}
+
+	var subscriptionID uint64
+	if value := c.QueryParam("webhookId"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_webhook_id",
+				Message: "Webhook ID must be a positive integer",
+			})
+		}
+		subscriptionID = parsed
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(status, subscriptionID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list deliveries",
+		})
+	}
+
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	deliveryID, err := strconv.ParseUint(c.Param("deliveryId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_delivery_id",
+			Message: "Delivery ID must be a positive integer",
+		})
+	}
+
+	delivery, err := h.webhookService.Redeliver(deliveryID, auditActor(c))
+	if err != nil {
+		switch err.Error() {
+		case "delivery not found":
+			return c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "delivery_not_found",
+				Message: "Delivery does not exist",
+			})
+		default:
+			return c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to redeliver webhook",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusAccepted, delivery)
+}
+
+func (h *WebhookHandler) validateCreateWebhookRequest(c echo.Context) (dto.CreateWebhookRequest, *ValidationError) {
+	var req dto.CreateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return dto.CreateWebhookRequest{}, &ValidationError{
+			Code:    "invalid_request_body",
+			Message: "Invalid JSON format",
+		}
+	}
+
+	parsed, err := url.Parse(req.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return dto.CreateWebhookRequest{}, &ValidationError{
+			Code:    "invalid_url",
+			Message: "URL must be an absolute http or https URL",
+		}
+	}
+
+	if len(req.EventTypes) == 0 {
+		return dto.CreateWebhookRequest{}, &ValidationError{
+			Code:    "missing_event_types",
+			Message: "EventTypes must list at least one event type",
+		}
+	}
+
+	for _, eventType := range req.EventTypes {
+		if !validEventType(eventType) {
+			return dto.CreateWebhookRequest{}, &ValidationError{
+				Code:    "invalid_event_type",
+				Message: "Unknown event type: " + eventType,
+			}
+		}
+	}
+
+	return req, nil
+}
+
+func validEventType(eventType string) bool {
+	if eventType == "*" {
+		return true
+	}
+	for _, known := range service.EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateWebhookRequest(t *testing.T) {
+	handler := &WebhookHandler{}
+
+	tests := []struct {
+		name         string
+		jsonBody     string
+		expectedCode string
+	}{
+		{
+			name:     "valid subscription",
+			jsonBody: `{"url": "https://partner.example.com/hooks", "eventTypes": ["transaction.processed"]}`,
+		},
+		{
+			name:     "wildcard subscription",
+			jsonBody: `{"url": "http://localhost:9000", "eventTypes": ["*"], "secret": "s3cret"}`,
+		},
+		{
+			name:         "invalid JSON",
+			jsonBody:     `{"url": }`,
+			expectedCode: "invalid_request_body",
+		},
+		{
+			name:         "relative URL",
+			jsonBody:     `{"url": "/hooks", "eventTypes": ["transaction.processed"]}`,
+			expectedCode: "invalid_url",
+		},
+		{
+			name:         "unsupported scheme",
+			jsonBody:     `{"url": "ftp://partner.example.com", "eventTypes": ["transaction.processed"]}`,
+			expectedCode: "invalid_url",
+		},
+		{
+			name:         "no event types",
+			jsonBody:     `{"url": "https://partner.example.com/hooks", "eventTypes": []}`,
+			expectedCode: "missing_event_types",
+		},
+		{
+			name:         "unknown event type",
+			jsonBody:     `{"url": "https://partner.example.com/hooks", "eventTypes": ["user.deleted"]}`,
+			expectedCode: "invalid_event_type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(tt.jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			_, validationErr := handler.validateCreateWebhookRequest(c)
+
+			if tt.expectedCode != "" {
+				assert.NotNil(t, validationErr)
+				assert.Equal(t, tt.expectedCode, validationErr.Code)
+			} else {
+				assert.Nil(t, validationErr)
+			}
+		})
+	}
+}
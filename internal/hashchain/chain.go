@@ -0,0 +1,191 @@
+// Package hashchain links every user's transactions into a hash chain, so
+// that editing, deleting or reordering a stored transaction breaks the chain
+// from that row onwards.
+package hashchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+)
+
+const (
+	ReasonPreviousMismatch = "previous_hash_mismatch"
+	ReasonHashMismatch     = "hash_mismatch"
+)
+
+// Version is the chain version new transactions are linked with. Version 1
+// rows, linked before the round and balance columns existed, keep verifying
+// with the content they were hashed with.
+const Version = 2
+
+// canonical is the hashed content of a version 1 transaction. Field order is
+// fixed by the struct, amounts are normalised to two decimals and times to
+// UTC microseconds, which is what the database stores.
+type canonical struct {
+	PreviousHash  string `json:"previousHash"`
+	UserID        uint64 `json:"userId"`
+	TransactionID string `json:"transactionId"`
+	Amount        string `json:"amount"`
+	State         string `json:"state"`
+	SourceType    string `json:"sourceType"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+// canonicalV2 also covers the round and the balances around the transaction,
+// which history is reconstructed from. The version is hashed too, so a row
+// cannot be passed off as version 1 to leave them out.
+type canonicalV2 struct {
+	Version       int     `json:"version"`
+	PreviousHash  string  `json:"previousHash"`
+	UserID        uint64  `json:"userId"`
+	TransactionID string  `json:"transactionId"`
+	Amount        string  `json:"amount"`
+	State         string  `json:"state"`
+	SourceType    string  `json:"sourceType"`
+	RoundID       *uint64 `json:"roundId"`
+	BalanceBefore *string `json:"balanceBefore"`
+	BalanceAfter  *string `json:"balanceAfter"`
+	CreatedAt     string  `json:"createdAt"`
+}
+
+// Hash returns the hex SHA-256 of the transaction's canonical content for its
+// chain version, including the hash of the user's previous transaction.
+func Hash(previousHash string, transaction model.Transaction) (string, error) {
+	amount, err := normaliseAmount(transaction.Amount)
+	if err != nil {
+		return "", fmt.Errorf("invalid amount: %w", err)
+	}
+	createdAt := transaction.CreatedAt.UTC().Format("2006-01-02T15:04:05.000000Z")
+
+	var content []byte
+	switch transaction.ChainVersion {
+	case 0, 1:
+		content, err = json.Marshal(canonical{
+			PreviousHash:  previousHash,
+			UserID:        transaction.UserID,
+			TransactionID: transaction.TransactionID,
+			Amount:        amount,
+			State:         transaction.State,
+			SourceType:    transaction.SourceType,
+			CreatedAt:     createdAt,
+		})
+	case 2:
+		var before, after *string
+		if before, err = optionalAmount(transaction.BalanceBefore); err != nil {
+			return "", fmt.Errorf("invalid balance before: %w", err)
+		}
+		if after, err = optionalAmount(transaction.BalanceAfter); err != nil {
+			return "", fmt.Errorf("invalid balance after: %w", err)
+		}
+		content, err = json.Marshal(canonicalV2{
+			Version:       2,
+			PreviousHash:  previousHash,
+			UserID:        transaction.UserID,
+			TransactionID: transaction.TransactionID,
+			Amount:        amount,
+			State:         transaction.State,
+			SourceType:    transaction.SourceType,
+			RoundID:       transaction.RoundID,
+			BalanceBefore: before,
+			BalanceAfter:  after,
+			CreatedAt:     createdAt,
+		})
+	default:
+		return "", fmt.Errorf("unknown chain version %d", transaction.ChainVersion)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func normaliseAmount(amount string) (string, error) {
+	value, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%.2f", value), nil
+}
+
+func optionalAmount(amount *string) (*string, error) {
+	if amount == nil {
+		return nil, nil
+	}
+	normalised, err := normaliseAmount(*amount)
+	return &normalised, err
+}
+
+// Link sets the chain fields of a transaction at the current version. It is
+// used for new transactions and to re-link rows of an intact chain. CreatedAt
+// is truncated to the precision the database keeps, so the stored row hashes
+// the same.
+func Link(previousHash string, transaction *model.Transaction) error {
+	if transaction.CreatedAt.IsZero() {
+		transaction.CreatedAt = time.Now().UTC()
+	}
+	transaction.CreatedAt = transaction.CreatedAt.Truncate(time.Microsecond)
+	transaction.ChainVersion = Version
+
+	hash, err := Hash(previousHash, *transaction)
+	if err != nil {
+		return err
+	}
+
+	transaction.PreviousHash = nil
+	if previousHash != "" {
+		transaction.PreviousHash = &previousHash
+	}
+	transaction.Hash = hash
+	return nil
+}
+
+// Break describes the first link of a chain that does not verify.
+type Break struct {
+	Transaction model.Transaction
+	Reason      string
+	Expected    string
+	Actual      string
+}
+
+// Verifier checks one user's transactions, fed in chain order.
+type Verifier struct {
+	previous string
+	checked  int
+}
+
+// Check verifies the next transaction of the chain and returns the break it
+// finds, or nil when the link holds.
+func (v *Verifier) Check(transaction model.Transaction) (*Break, error) {
+	previous := ""
+	if transaction.PreviousHash != nil {
+		previous = *transaction.PreviousHash
+	}
+	if previous != v.previous {
+		return &Break{Transaction: transaction, Reason: ReasonPreviousMismatch, Expected: v.previous, Actual: previous}, nil
+	}
+
+	expected, err := Hash(previous, transaction)
+	if err != nil {
+		return nil, err
+	}
+	if expected != transaction.Hash {
+		return &Break{Transaction: transaction, Reason: ReasonHashMismatch, Expected: expected, Actual: transaction.Hash}, nil
+	}
+
+	v.previous = expected
+	v.checked++
+	return nil, nil
+}
+
+// Checked returns how many transactions verified so far.
+func (v *Verifier) Checked() int {
+	return v.checked
+}
@@ -0,0 +1,232 @@
+package hashchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildChain(t *testing.T, transactions ...model.Transaction) []model.Transaction {
+	t.Helper()
+
+	previous := ""
+	for i := range transactions {
+		transactions[i].ID = uint64(i + 1)
+		require.NoError(t, Link(previous, &transactions[i]))
+		previous = transactions[i].Hash
+	}
+	return transactions
+}
+
+func sampleChain(t *testing.T) []model.Transaction {
+	created := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	roundID := uint64(7)
+	return buildChain(t,
+		model.Transaction{UserID: 1, TransactionID: "tx-1", Amount: "50.00", State: "win", SourceType: "payment", BalanceBefore: amount("0.00"), BalanceAfter: amount("50.00"), CreatedAt: created},
+		model.Transaction{UserID: 1, TransactionID: "tx-2", Amount: "10.5", State: "lose", SourceType: "game", RoundID: &roundID, BalanceBefore: amount("50.00"), BalanceAfter: amount("39.50"), CreatedAt: created.Add(time.Minute)},
+		model.Transaction{UserID: 1, TransactionID: "tx-3", Amount: "5", State: "win", SourceType: "game", RoundID: &roundID, BalanceBefore: amount("39.50"), BalanceAfter: amount("44.50"), CreatedAt: created.Add(2 * time.Minute)},
+	)
+}
+
+func amount(value string) *string {
+	return &value
+}
+
+func verify(t *testing.T, transactions []model.Transaction) (*Break, int) {
+	t.Helper()
+
+	verifier := &Verifier{}
+	for _, transaction := range transactions {
+		broken, err := verifier.Check(transaction)
+		require.NoError(t, err)
+		if broken != nil {
+			return broken, verifier.Checked()
+		}
+	}
+	return nil, verifier.Checked()
+}
+
+func TestHashNormalisesStoredRepresentation(t *testing.T) {
+	created := time.Date(2025, 7, 2, 15, 0, 0, 123456000, time.FixedZone("EEST", 3*60*60))
+	transaction := model.Transaction{UserID: 1, TransactionID: "tx-1", Amount: "10.5", State: "win", SourceType: "game", CreatedAt: created}
+
+	stored := transaction
+	stored.Amount = "10.50"
+	stored.CreatedAt = created.UTC()
+
+	expected, err := Hash("", transaction)
+	require.NoError(t, err)
+	actual, err := Hash("", stored)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+	assert.Len(t, expected, 64)
+
+	withPrevious, err := Hash(expected, transaction)
+	require.NoError(t, err)
+	assert.NotEqual(t, expected, withPrevious)
+}
+
+func TestLink(t *testing.T) {
+	first := model.Transaction{UserID: 1, TransactionID: "tx-1", Amount: "1.00", State: "win", SourceType: "game",
+		CreatedAt: time.Date(2025, 7, 2, 12, 0, 0, 123456789, time.UTC)}
+	require.NoError(t, Link("", &first))
+
+	assert.Nil(t, first.PreviousHash)
+	assert.Equal(t, 123456000, first.CreatedAt.Nanosecond(), "created at is truncated to microseconds")
+
+	second := model.Transaction{UserID: 1, TransactionID: "tx-2", Amount: "1.00", State: "lose", SourceType: "game"}
+	require.NoError(t, Link(first.Hash, &second))
+
+	require.NotNil(t, second.PreviousHash)
+	assert.Equal(t, first.Hash, *second.PreviousHash)
+	assert.False(t, second.CreatedAt.IsZero())
+}
+
+func TestVerifier(t *testing.T) {
+	tests := []struct {
+		name             string
+		tamper           func([]model.Transaction) []model.Transaction
+		expectedReason   string
+		expectedID       uint64
+		expectedVerified int
+	}{
+		{
+			name:             "intact chain",
+			tamper:           func(chain []model.Transaction) []model.Transaction { return chain },
+			expectedVerified: 3,
+		},
+		{
+			name: "edited amount",
+			tamper: func(chain []model.Transaction) []model.Transaction {
+				chain[1].Amount = "1.50"
+				return chain
+			},
+			expectedReason:   ReasonHashMismatch,
+			expectedID:       2,
+			expectedVerified: 1,
+		},
+		{
+			name: "edited timestamp",
+			tamper: func(chain []model.Transaction) []model.Transaction {
+				chain[0].CreatedAt = chain[0].CreatedAt.Add(-24 * time.Hour)
+				return chain
+			},
+			expectedReason: ReasonHashMismatch,
+			expectedID:     1,
+		},
+		{
+			name: "edited balance after",
+			tamper: func(chain []model.Transaction) []model.Transaction {
+				chain[1].BalanceAfter = amount("139.50")
+				return chain
+			},
+			expectedReason:   ReasonHashMismatch,
+			expectedID:       2,
+			expectedVerified: 1,
+		},
+		{
+			name: "removed balance before",
+			tamper: func(chain []model.Transaction) []model.Transaction {
+				chain[2].BalanceBefore = nil
+				return chain
+			},
+			expectedReason:   ReasonHashMismatch,
+			expectedID:       3,
+			expectedVerified: 2,
+		},
+		{
+			name: "moved to another round",
+			tamper: func(chain []model.Transaction) []model.Transaction {
+				other := uint64(8)
+				chain[2].RoundID = &other
+				return chain
+			},
+			expectedReason:   ReasonHashMismatch,
+			expectedID:       3,
+			expectedVerified: 2,
+		},
+		{
+			name: "downgraded to version 1",
+			tamper: func(chain []model.Transaction) []model.Transaction {
+				chain[1].ChainVersion = 1
+				return chain
+			},
+			expectedReason:   ReasonHashMismatch,
+			expectedID:       2,
+			expectedVerified: 1,
+		},
+		{
+			name: "deleted row",
+			tamper: func(chain []model.Transaction) []model.Transaction {
+				return append(chain[:1], chain[2:]...)
+			},
+			expectedReason:   ReasonPreviousMismatch,
+			expectedID:       3,
+			expectedVerified: 1,
+		},
+		{
+			name: "swapped rows",
+			tamper: func(chain []model.Transaction) []model.Transaction {
+				chain[1], chain[2] = chain[2], chain[1]
+				return chain
+			},
+			expectedReason:   ReasonPreviousMismatch,
+			expectedID:       3,
+			expectedVerified: 1,
+		},
+		{
+			name: "rehashed row without relinking the next",
+			tamper: func(chain []model.Transaction) []model.Transaction {
+				chain[1].Amount = "1.50"
+				rehashed, _ := Hash(*chain[1].PreviousHash, chain[1])
+				chain[1].Hash = rehashed
+				return chain
+			},
+			expectedReason:   ReasonPreviousMismatch,
+			expectedID:       3,
+			expectedVerified: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broken, verified := verify(t, tt.tamper(sampleChain(t)))
+			assert.Equal(t, tt.expectedVerified, verified)
+			if tt.expectedReason == "" {
+				assert.Nil(t, broken)
+				return
+			}
+			require.NotNil(t, broken)
+			assert.Equal(t, tt.expectedReason, broken.Reason)
+			assert.Equal(t, tt.expectedID, broken.Transaction.ID)
+		})
+	}
+}
+
+func TestVersionOneRowsStillVerify(t *testing.T) {
+	created := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	legacy := model.Transaction{ID: 1, UserID: 1, TransactionID: "tx-1", Amount: "50.00", State: "win", SourceType: "payment", ChainVersion: 1, CreatedAt: created}
+	hash, err := Hash("", legacy)
+	require.NoError(t, err)
+	legacy.Hash = hash
+
+	// A backfill stores balances on the row without re-linking it; version 1
+	// does not cover them.
+	legacy.BalanceBefore, legacy.BalanceAfter = amount("0.00"), amount("50.00")
+
+	current := model.Transaction{ID: 2, UserID: 1, TransactionID: "tx-2", Amount: "5.00", State: "lose", SourceType: "game", CreatedAt: created.Add(time.Minute)}
+	require.NoError(t, Link(legacy.Hash, &current))
+	assert.Equal(t, Version, current.ChainVersion)
+
+	broken, verified := verify(t, []model.Transaction{legacy, current})
+	assert.Nil(t, broken)
+	assert.Equal(t, 2, verified)
+}
+
+func TestHashRejectsUnknownVersion(t *testing.T) {
+	_, err := Hash("", model.Transaction{Amount: "1.00", ChainVersion: Version + 1})
+	assert.EqualError(t, err, "unknown chain version 3")
+}
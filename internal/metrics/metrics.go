@@ -0,0 +1,14 @@
+// Package metrics holds the service's counters. They are published through
+// expvar and served as JSON by GET /admin/metrics.
+package metrics
+
+import "expvar"
+
+var (
+	// TransactionRetries counts database transactions that were run again,
+	// by reason.
+	TransactionRetries = expvar.NewMap("transaction_retries")
+	// TransactionRetriesExhausted counts database transactions that failed on
+	// their last attempt, by reason.
+	TransactionRetriesExhausted = expvar.NewMap("transaction_retries_exhausted")
+)
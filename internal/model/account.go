@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+const (
+	StatusActive       = "active"
+	StatusSuspended    = "suspended"
+	StatusSelfExcluded = "self_excluded"
+	StatusClosed       = "closed"
+)
+
+type AccountStatusChange struct {
+	ID            uint64
+	UserID        uint64
+	FromStatus    string
+	ToStatus      string
+	Reason        string
+	ExcludedUntil *time.Time
+	Actor         string
+	CreatedAt     time.Time
+}
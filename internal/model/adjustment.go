@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+const (
+	AdjustmentPending  = "pending"
+	AdjustmentApproved = "approved"
+	AdjustmentRejected = "rejected"
+
+	AdjustmentCredit = "credit"
+	AdjustmentDebit  = "debit"
+)
+
+// BalanceAdjustment is a manual credit or debit proposed by one operator. It
+// only reaches the ledger once a different operator approves it.
+type BalanceAdjustment struct {
+	ID            uint64
+	UserID        uint64
+	Direction     string
+	Amount        string
+	ReasonCode    string
+	Note          string
+	Status        string
+	RequestedBy   string
+	ReviewedBy    *string
+	ReviewNote    *string
+	ReviewedAt    *time.Time
+	TransactionID *string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
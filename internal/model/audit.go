@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+const (
+	AuditUserCreate          = "user.create"
+	AuditAccountStatusChange = "account.status_change"
+	AuditWebhookCreate       = "webhook.create"
+	AuditWebhookDelete       = "webhook.delete"
+	AuditWebhookRedeliver    = "webhook.redeliver"
+	AuditAdjustmentRequest   = "adjustment.request"
+	AuditAdjustmentApprove   = "adjustment.approve"
+	AuditAdjustmentReject    = "adjustment.reject"
+	AuditReviewApprove       = "review.approve"
+	AuditReviewDecline       = "review.decline"
+	AuditRoundRollback       = "round.rollback"
+	AuditLimitSet            = "limit.set"
+	AuditLimitRemove         = "limit.remove"
+)
+
+type AuditEntry struct {
+	ID           uint64
+	Actor        string
+	Action       string
+	TargetUserID *uint64
+	Resource     string
+	Before       *string
+	After        *string
+	RequestID    string
+	CreatedAt    time.Time
+}
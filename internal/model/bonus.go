@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const (
+	BonusActive    = "active"
+	BonusCompleted = "completed"
+	BonusForfeited = "forfeited"
+)
+
+// Bonus is a bonus granted to a user. Balance is the part of the user's bonus
+// wallet the bonus still holds; bets and forfeits take from the bonuses
+// holding the wallet, never from the pool as a whole.
+type Bonus struct {
+	ID                uint64
+	UserID            uint64
+	TransactionID     uint64
+	Amount            string
+	Balance           string
+	WageringRequired  string
+	WageringRemaining string
+	Status            string
+	ExpiresAt         time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// BonusWager is the wagering a game bet counted towards one bonus, kept so a
+// rolled back bet can give it back.
+type BonusWager struct {
+	ID            uint64
+	TransactionID uint64
+	BonusID       uint64
+	Amount        string
+	CreatedAt     time.Time
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type (
+	// DailyClosing marks a business day as closed. The period covers the
+	// day in the closing time zone, stored as UTC instants.
+	DailyClosing struct {
+		ID           uint64
+		BusinessDate time.Time
+		TimeZone     string
+		PeriodStart  time.Time
+		PeriodEnd    time.Time
+		ClosedAt     time.Time
+	}
+
+	DailyClosingBalance struct {
+		ClosingID      uint64
+		UserID         uint64
+		OpeningBalance string
+		ClosingBalance string
+	}
+
+	DailyClosingTotal struct {
+		ClosingID    uint64
+		SourceType   string
+		State        string
+		Transactions int64
+		Amount       string
+	}
+)
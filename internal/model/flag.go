@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// TransactionFlag marks a booked transaction that matched a flag rule or
+// fraud check, so it can be followed up without holding up the booking.
+type TransactionFlag struct {
+	ID            uint64
+	TransactionID uint64
+	UserID        uint64
+	RuleID        string
+	Message       string
+	CreatedAt     time.Time
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+const (
+	LimitLoss    = "loss"
+	LimitDeposit = "deposit"
+
+	PeriodDaily   = "daily"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+)
+
+type UserLimit struct {
+	ID                 uint64
+	UserID             uint64
+	LimitType          string
+	Period             string
+	Amount             string
+	PendingAmount      *string
+	PendingEffectiveAt *time.Time
+	SetBy              string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type OutboxEvent struct {
+	ID          uint64
+	AggregateID uint64
+	EventType   string
+	DedupeKey   string
+	Payload     string
+	Attempts    int
+	LastError   *string
+	AvailableAt time.Time
+	PublishedAt *time.Time
+	CreatedAt   time.Time
+}
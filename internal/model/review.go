@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewDeclined = "declined"
+)
+
+// TransactionReview is a transaction held back for operator review. It is
+// stored as submitted and not applied to any balance until it is approved.
+// RuleID names the rule or check that held it; Flags keeps, as a JSON array,
+// the flag rules and checks that also matched. Approving it books the
+// transaction as if it had just been submitted; declining it never touches
+// the balance.
+type TransactionReview struct {
+	ID            uint64
+	UserID        uint64
+	TransactionID string
+	SourceType    string
+	State         string
+	Amount        string
+	RoundID       *string
+	GameID        *string
+	Provider      *string
+	CloseRound    bool
+	RuleID        string
+	Reason        string
+	Flags         string
+	Status        string
+	ReviewedBy    *string
+	ReviewNote    *string
+	ReviewedAt    *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
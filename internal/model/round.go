@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	RoundOpen       = "open"
+	RoundClosed     = "closed"
+	RoundRolledBack = "rolled_back"
+)
+
+// ReversalPrefix starts the transaction ID of every transaction booked to
+// reverse another one when a round is rolled back.
+const ReversalPrefix = "rollback:"
+
+// GameRound groups the bets and wins a game provider books for one round.
+// RoundID is the provider's identifier; transactions reference the row ID.
+type GameRound struct {
+	ID        uint64
+	RoundID   string
+	UserID    uint64
+	GameID    string
+	Provider  string
+	Status    string
+	ClosedAt  *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
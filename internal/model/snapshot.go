@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// BalanceSnapshot records a user's balance right after the transaction it
+// references was applied. AsOf is that transaction's creation time.
+type BalanceSnapshot struct {
+	ID            uint64
+	UserID        uint64
+	TransactionID uint64
+	Balance       string
+	AsOf          time.Time
+	CreatedAt     time.Time
+}
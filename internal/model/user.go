@@ -2,12 +2,23 @@ package model
 
 import "time"
 
+const (
+	TransactionBooked   = "booked"
+	TransactionPending  = "pending"
+	TransactionDeclined = "declined"
+)
+
 type (
 	User struct {
-		ID        uint64
-		Balance   string
-		CreatedAt time.Time
-		UpdatedAt time.Time
+		ID            uint64
+		ExternalRef   *string
+		Metadata      string
+		Balance       string
+		Status        string
+		ExcludedUntil *time.Time
+		Version       int64
+		CreatedAt     time.Time
+		UpdatedAt     time.Time
 	}
 
 	Transaction struct {
@@ -17,6 +28,12 @@ type (
 		Amount        string
 		State         string
 		SourceType    string
+		RoundID       *uint64
+		BalanceBefore *string
+		BalanceAfter  *string
+		ChainVersion  int
+		PreviousHash  *string
+		Hash          string
 		CreatedAt     time.Time
 	}
 )
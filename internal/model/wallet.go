@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+const (
+	WalletCash   = "cash"
+	WalletBonus  = "bonus"
+	WalletLocked = "locked"
+)
+
+var WalletTypes = []string{WalletCash, WalletBonus, WalletLocked}
+
+type (
+	Wallet struct {
+		ID        uint64
+		UserID    uint64
+		Type      string
+		Balance   string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+
+	WalletEntry struct {
+		ID            uint64
+		UserID        uint64
+		TransactionID *uint64
+		BonusID       *uint64
+		WalletType    string
+		Amount        string
+		CreatedAt     time.Time
+	}
+)
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+type (
+	WebhookSubscription struct {
+		ID         uint64
+		URL        string
+		EventTypes string
+		Secret     string
+		Active     bool
+		CreatedBy  string
+		CreatedAt  time.Time
+		UpdatedAt  time.Time
+	}
+
+	WebhookDelivery struct {
+		ID             uint64
+		SubscriptionID uint64
+		EventID        uint64
+		EventType      string
+		DedupeKey      string
+		Payload        string
+		Status         string
+		Attempts       int
+		NextAttemptAt  time.Time
+		LastStatusCode *int
+		LastError      *string
+		DeliveredAt    *time.Time
+		CreatedAt      time.Time
+		UpdatedAt      time.Time
+	}
+)
@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriterPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := NewWriterPublisher(&buf)
+
+	event := Event{ID: 1, Type: "balance.changed", DedupeKey: "balance.changed:tx-1", AggregateID: 7, Payload: json.RawMessage(`{"balance":"1.00"}`)}
+	assert.NoError(t, publisher.Publish(context.Background(), event))
+	assert.NoError(t, publisher.Publish(context.Background(), event))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var decoded Event
+	assert.NoError(t, json.Unmarshal(lines[0], &decoded))
+	assert.Equal(t, "balance.changed:tx-1", decoded.DedupeKey)
+	assert.JSONEq(t, `{"balance":"1.00"}`, string(decoded.Payload))
+}
+
+func TestMemoryPublisher(t *testing.T) {
+	publisher := NewMemoryPublisher()
+	assert.NoError(t, publisher.Publish(context.Background(), Event{ID: 1}))
+	assert.NoError(t, publisher.Publish(context.Background(), Event{ID: 2}))
+
+	events := publisher.Events()
+	assert.Len(t, events, 2)
+	assert.Equal(t, uint64(2), events[1].ID)
+}
@@ -0,0 +1,136 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	claimLease  = 30 * time.Second
+	baseBackoff = time.Second
+)
+
+// Store is the part of the outbox repository the relay depends on.
+type Store interface {
+	ClaimEvents(limit int, lease time.Duration) ([]model.OutboxEvent, error)
+	MarkPublished(eventID uint64) error
+	MarkFailed(eventID uint64, attempts int, lastError string, availableAt time.Time) error
+}
+
+// Relay delivers outbox events to a publisher. Events of the same aggregate
+// are delivered strictly in the order they were written; a failed event is
+// retried with exponential backoff and holds back the events behind it.
+type Relay struct {
+	store        Store
+	publisher    Publisher
+	batchSize    int
+	pollInterval time.Duration
+	maxBackoff   time.Duration
+	now          func() time.Time
+}
+
+func NewRelay(store Store, publisher Publisher, batchSize int, pollInterval, maxBackoff time.Duration) *Relay {
+	return &Relay{
+		store:        store,
+		publisher:    publisher,
+		batchSize:    batchSize,
+		pollInterval: pollInterval,
+		maxBackoff:   maxBackoff,
+		now:          func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	logrus.WithFields(logrus.Fields{"batchSize": r.batchSize, "pollInterval": r.pollInterval}).Info("Outbox relay started")
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			delivered, err := r.ProcessBatch(ctx)
+			if err != nil {
+				logrus.WithField("error", err).Error("Failed to process outbox batch")
+				break
+			}
+			if delivered == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			logrus.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch claims and publishes one batch of events and returns how many
+// were published.
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	events, err := r.store.ClaimEvents(r.batchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+
+	published := 0
+	for _, event := range events {
+		if err := r.publisher.Publish(ctx, toEvent(event)); err != nil {
+			attempts := event.Attempts + 1
+			retryAt := r.now().Add(backoff(attempts, r.maxBackoff))
+			logrus.WithFields(logrus.Fields{
+				"eventID":  event.ID,
+				"type":     event.EventType,
+				"attempts": attempts,
+				"retryAt":  retryAt,
+				"error":    err,
+			}).Warn("Failed to publish outbox event")
+
+			if err := r.store.MarkFailed(event.ID, attempts, err.Error(), retryAt); err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		if err := r.store.MarkPublished(event.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+func toEvent(event model.OutboxEvent) Event {
+	return Event{
+		ID:          event.ID,
+		Type:        event.EventType,
+		DedupeKey:   event.DedupeKey,
+		AggregateID: event.AggregateID,
+		Payload:     json.RawMessage(event.Payload),
+		CreatedAt:   event.CreatedAt,
+	}
+}
+
+func backoff(attempts int, maximum time.Duration) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maximum {
+			return maximum
+		}
+	}
+	if delay > maximum {
+		return maximum
+	}
+	return delay
+}
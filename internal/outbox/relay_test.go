@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	claimable []model.OutboxEvent
+	published []uint64
+	failed    map[uint64]int
+	retryAt   map[uint64]time.Time
+}
+
+func (s *fakeStore) ClaimEvents(limit int, lease time.Duration) ([]model.OutboxEvent, error) {
+	events := s.claimable
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	s.claimable = nil
+	return events, nil
+}
+
+func (s *fakeStore) MarkPublished(eventID uint64) error {
+	s.published = append(s.published, eventID)
+	return nil
+}
+
+func (s *fakeStore) MarkFailed(eventID uint64, attempts int, lastError string, availableAt time.Time) error {
+	s.failed[eventID] = attempts
+	s.retryAt[eventID] = availableAt
+	return nil
+}
+
+type failingPublisher struct {
+	failIDs   map[uint64]bool
+	published []uint64
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, event Event) error {
+	if p.failIDs[event.ID] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+func TestRelayProcessBatch(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{
+		claimable: []model.OutboxEvent{
+			{ID: 3, AggregateID: 2, EventType: "balance.changed", Payload: `{}`},
+			{ID: 1, AggregateID: 1, EventType: "balance.changed", Payload: `{}`},
+			{ID: 2, AggregateID: 3, EventType: "balance.changed", Payload: `{}`, Attempts: 2},
+		},
+		failed:  map[uint64]int{},
+		retryAt: map[uint64]time.Time{},
+	}
+	publisher := &failingPublisher{failIDs: map[uint64]bool{2: true}}
+
+	relay := NewRelay(store, publisher, 10, time.Second, time.Minute)
+	relay.now = func() time.Time { return now }
+
+	published, err := relay.ProcessBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []uint64{1, 3}, publisher.published)
+	assert.Equal(t, []uint64{1, 3}, store.published)
+	assert.Equal(t, 3, store.failed[2])
+	assert.Equal(t, now.Add(4*time.Second), store.retryAt[2])
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, backoff(1, time.Minute))
+	assert.Equal(t, 2*time.Second, backoff(2, time.Minute))
+	assert.Equal(t, 8*time.Second, backoff(4, time.Minute))
+	assert.Equal(t, time.Minute, backoff(10, time.Minute))
+	assert.Equal(t, 500*time.Millisecond, backoff(1, 500*time.Millisecond))
+}
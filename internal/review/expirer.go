@@ -0,0 +1,63 @@
+package review
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Store is the part of the review service the expirer depends on.
+type Store interface {
+	DeclineExpired(before time.Time) (int, error)
+}
+
+// Expirer declines held transactions nobody reviewed within the SLA, so a
+// source is never left waiting on a transaction indefinitely.
+type Expirer struct {
+	store    Store
+	sla      time.Duration
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewExpirer(store Store, sla, interval time.Duration) *Expirer {
+	return &Expirer{
+		store:    store,
+		sla:      sla,
+		interval: interval,
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (e *Expirer) Run(ctx context.Context) {
+	logrus.WithFields(logrus.Fields{"sla": e.sla, "interval": e.interval}).Info("Review expirer started")
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.DeclineExpired()
+
+		select {
+		case <-ctx.Done():
+			logrus.Info("Review expirer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeclineExpired declines the reviews held for longer than the SLA and
+// returns how many it declined.
+func (e *Expirer) DeclineExpired() int {
+	declined, err := e.store.DeclineExpired(e.now().Add(-e.sla))
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"declined": declined, "error": err}).Error("Failed to decline expired reviews")
+		return declined
+	}
+	if declined > 0 {
+		logrus.WithField("declined", declined).Info("Expired reviews declined")
+	}
+	return declined
+}
@@ -0,0 +1,76 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	mu       sync.Mutex
+	before   []time.Time
+	declined int
+	err      error
+}
+
+func (f *fakeStore) DeclineExpired(before time.Time) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.before = append(f.before, before)
+	return f.declined, f.err
+}
+
+func (f *fakeStore) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.before)
+}
+
+func TestDeclineExpired(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		store    *fakeStore
+		expected int
+	}{
+		{name: "reviews declined", store: &fakeStore{declined: 2}, expected: 2},
+		{name: "nothing overdue", store: &fakeStore{}, expected: 0},
+		{name: "store failure after some declines", store: &fakeStore{declined: 1, err: errors.New("connection refused")}, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expirer := NewExpirer(tt.store, 24*time.Hour, time.Minute)
+			expirer.now = func() time.Time { return now }
+
+			assert.Equal(t, tt.expected, expirer.DeclineExpired())
+			assert.Equal(t, []time.Time{now.Add(-24 * time.Hour)}, tt.store.before)
+		})
+	}
+}
+
+func TestRunDeclinesImmediatelyAndOnEveryTick(t *testing.T) {
+	store := &fakeStore{}
+	expirer := NewExpirer(store, time.Hour, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		expirer.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return store.Calls() >= 3 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expirer did not stop after the context was cancelled")
+	}
+}
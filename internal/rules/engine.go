@@ -0,0 +1,117 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	defaultOnce   sync.Once
+	defaultEngine *Engine
+)
+
+// Default returns the engine for the configured rule file. It starts empty;
+// the server loads and watches it on startup.
+func Default() *Engine {
+	defaultOnce.Do(func() {
+		defaultEngine = NewEngine(config.Get().TransactionRulesFile)
+	})
+	return defaultEngine
+}
+
+// Engine holds the active rule set of a rule file and swaps it when the file
+// changes. Without a file it holds no rules.
+type Engine struct {
+	path string
+
+	mu      sync.RWMutex
+	set     *Set
+	modTime time.Time
+}
+
+func NewEngine(path string) *Engine {
+	return &Engine{path: path}
+}
+
+// Load reads the rule file. A file that cannot be read or parsed leaves the
+// active rules in place.
+func (e *Engine) Load() error {
+	if e.path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return fmt.Errorf("failed to read rule file: %w", err)
+	}
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return fmt.Errorf("failed to read rule file: %w", err)
+	}
+	set, err := Parse(data)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.set = set
+	e.modTime = info.ModTime()
+	e.mu.Unlock()
+
+	logrus.WithFields(logrus.Fields{"file": e.path, "rules": set.Len()}).Info("Transaction rules loaded")
+	return nil
+}
+
+// Evaluate returns the active rules matching in.
+func (e *Engine) Evaluate(in Input) []Rule {
+	e.mu.RLock()
+	set := e.set
+	e.mu.RUnlock()
+	return set.Evaluate(in)
+}
+
+// Watch reloads the rule file whenever its modification time changes, until
+// ctx is done.
+func (e *Engine) Watch(ctx context.Context, interval time.Duration) {
+	if e.path == "" {
+		return
+	}
+	logrus.WithFields(logrus.Fields{"file": e.path, "interval": interval}).Info("Transaction rule watcher started")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("Transaction rule watcher stopped")
+			return
+		case <-ticker.C:
+		}
+
+		if !e.changed() {
+			continue
+		}
+		if err := e.Load(); err != nil {
+			logrus.WithFields(logrus.Fields{"file": e.path, "error": err}).Error("Failed to reload transaction rules, keeping the previous rules")
+		}
+	}
+}
+
+func (e *Engine) changed() bool {
+	info, err := os.Stat(e.path)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"file": e.path, "error": err}).Warn("Failed to check transaction rule file")
+		return false
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return !info.ModTime().Equal(e.modTime)
+}
@@ -0,0 +1,314 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+)
+
+const (
+	ActionReject = "reject"
+	ActionFlag   = "flag"
+	ActionReview = "review"
+)
+
+// The values sources, states and statuses conditions can name. A value
+// outside them would never match, so it is rejected as a typo.
+var (
+	sourceTypes = []string{"game", "server", "payment", "adjustment"}
+	states      = []string{"win", "lose"}
+	statuses    = []string{model.StatusActive, model.StatusSuspended, model.StatusSelfExcluded, model.StatusClosed}
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// File is the JSON document rules are loaded from. Time windows are read in
+// TimeZone, UTC when it is empty.
+type File struct {
+	TimeZone string `json:"timeZone"`
+	Rules    []Rule `json:"rules"`
+}
+
+// Rule matches a transaction when every condition it sets holds. Conditions
+// left empty match everything, so a rule with only an action matches every
+// transaction.
+type Rule struct {
+	ID              string   `json:"id"`
+	Action          string   `json:"action"`
+	Message         string   `json:"message,omitempty"`
+	Sources         []string `json:"sources,omitempty"`
+	States          []string `json:"states,omitempty"`
+	AmountOver      string   `json:"amountOver,omitempty"`
+	AmountUnder     string   `json:"amountUnder,omitempty"`
+	UserIDs         []uint64 `json:"userIds,omitempty"`
+	Statuses        []string `json:"statuses,omitempty"`
+	AccountAgeUnder string   `json:"accountAgeUnder,omitempty"`
+	Window          *Window  `json:"window,omitempty"`
+
+	amountOver      *float64
+	amountUnder     *float64
+	accountAgeUnder time.Duration
+}
+
+// Window limits a rule to a time of day, optionally on some days of the week
+// only. A window whose From is after its To wraps past midnight.
+type Window struct {
+	Days []string `json:"days,omitempty"`
+	From string   `json:"from"`
+	To   string   `json:"to"`
+
+	days     map[time.Weekday]bool
+	from, to time.Duration
+}
+
+// Input is the transaction a rule set is evaluated against.
+type Input struct {
+	UserID           uint64
+	SourceType       string
+	State            string
+	Amount           float64
+	Status           string
+	AccountCreatedAt time.Time
+	At               time.Time
+}
+
+// Set is a parsed and validated rule file.
+type Set struct {
+	location *time.Location
+	rules    []Rule
+}
+
+// Parse reads and validates a rule file. A file that fails validation is
+// rejected as a whole, so a typo never leaves half of the rules active.
+// Unknown fields are rejected too: a misspelled condition would otherwise be
+// dropped and widen the rule to every transaction.
+func Parse(data []byte) (*Set, error) {
+	var file File
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid rule file: %w", err)
+	}
+
+	location := time.UTC
+	if file.TimeZone != "" {
+		var err error
+		location, err = time.LoadLocation(file.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", file.TimeZone, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range file.Rules {
+		rule := &file.Rules[i]
+		if rule.ID == "" {
+			return nil, fmt.Errorf("rule %d has no id", i+1)
+		}
+		if seen[rule.ID] {
+			return nil, fmt.Errorf("rule %s is defined twice", rule.ID)
+		}
+		seen[rule.ID] = true
+
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+	}
+
+	return &Set{location: location, rules: file.Rules}, nil
+}
+
+func (r *Rule) compile() error {
+	switch r.Action {
+	case ActionReject, ActionFlag, ActionReview:
+	default:
+		return fmt.Errorf("action must be %s, %s or %s", ActionReject, ActionFlag, ActionReview)
+	}
+
+	if err := checkValues(r.Sources, sourceTypes); err != nil {
+		return fmt.Errorf("sources: %w", err)
+	}
+	if err := checkValues(r.States, states); err != nil {
+		return fmt.Errorf("states: %w", err)
+	}
+	if err := checkValues(r.Statuses, statuses); err != nil {
+		return fmt.Errorf("statuses: %w", err)
+	}
+
+	var err error
+	if r.amountOver, err = parseThreshold(r.AmountOver); err != nil {
+		return fmt.Errorf("amountOver: %w", err)
+	}
+	if r.amountUnder, err = parseThreshold(r.AmountUnder); err != nil {
+		return fmt.Errorf("amountUnder: %w", err)
+	}
+
+	if r.AccountAgeUnder != "" {
+		if r.accountAgeUnder, err = time.ParseDuration(r.AccountAgeUnder); err != nil || r.accountAgeUnder <= 0 {
+			return errors.New("accountAgeUnder must be a positive duration such as 72h")
+		}
+	}
+
+	if r.Window != nil {
+		if err := r.Window.compile(); err != nil {
+			return fmt.Errorf("window: %w", err)
+		}
+	}
+	return nil
+}
+
+func checkValues(values, known []string) error {
+	for _, value := range values {
+		if !contains(known, value) {
+			return fmt.Errorf("unknown value %q, expected one of %s", value, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+func parseThreshold(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return nil, errors.New("must be a non-negative decimal amount")
+	}
+	return &amount, nil
+}
+
+func (w *Window) compile() error {
+	var err error
+	if w.from, err = parseClock(w.From); err != nil {
+		return fmt.Errorf("from: %w", err)
+	}
+	if w.to, err = parseClock(w.To); err != nil {
+		return fmt.Errorf("to: %w", err)
+	}
+	if w.from == w.to {
+		return errors.New("from and to must differ")
+	}
+
+	if len(w.Days) > 0 {
+		w.days = make(map[time.Weekday]bool)
+		for _, day := range w.Days {
+			weekday, ok := weekdays[strings.ToLower(day)]
+			if !ok {
+				return fmt.Errorf("unknown day %q, expected mon to sun", day)
+			}
+			w.days[weekday] = true
+		}
+	}
+	return nil
+}
+
+// parseClock reads an HH:MM time of day as the offset from midnight.
+// 24:00 is accepted as the end of the day.
+func parseClock(value string) (time.Duration, error) {
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, errors.New("must be a time of day such as 22:00")
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// Len returns the number of rules in the set.
+func (s *Set) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.rules)
+}
+
+// Evaluate returns the rules matching in, in the order they appear in the
+// rule file.
+func (s *Set) Evaluate(in Input) []Rule {
+	if s == nil {
+		return nil
+	}
+
+	var matched []Rule
+	for _, rule := range s.rules {
+		if rule.matches(in, s.location) {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+func (r *Rule) matches(in Input, location *time.Location) bool {
+	if len(r.Sources) > 0 && !contains(r.Sources, in.SourceType) {
+		return false
+	}
+	if len(r.States) > 0 && !contains(r.States, in.State) {
+		return false
+	}
+	if r.amountOver != nil && !(in.Amount > *r.amountOver) {
+		return false
+	}
+	if r.amountUnder != nil && !(in.Amount < *r.amountUnder) {
+		return false
+	}
+	if len(r.UserIDs) > 0 && !contains(r.UserIDs, in.UserID) {
+		return false
+	}
+	if len(r.Statuses) > 0 && !contains(r.Statuses, in.Status) {
+		return false
+	}
+	if r.accountAgeUnder > 0 && in.At.Sub(in.AccountCreatedAt) >= r.accountAgeUnder {
+		return false
+	}
+	if r.Window != nil && !r.Window.contains(in.At.In(location)) {
+		return false
+	}
+	return true
+}
+
+// contains reports whether local falls into the window. The day of a window
+// wrapping past midnight is the day it started on.
+func (w *Window) contains(local time.Time) bool {
+	year, month, day := local.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, local.Location())
+	offset := local.Sub(midnight)
+	weekday := local.Weekday()
+
+	switch {
+	case w.from < w.to:
+		if offset < w.from || offset >= w.to {
+			return false
+		}
+	case offset >= w.from:
+	case offset < w.to:
+		weekday = (weekday + 6) % 7
+	default:
+		return false
+	}
+
+	return w.days == nil || w.days[weekday]
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
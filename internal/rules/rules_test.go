@@ -0,0 +1,171 @@
+package rules
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		expected string
+	}{
+		{name: "invalid JSON", file: `{"rules": [`, expected: "invalid rule file"},
+		{name: "unknown time zone", file: `{"timeZone": "Mars/Base", "rules": []}`, expected: "invalid time zone"},
+		{name: "missing id", file: `{"rules": [{"action": "reject"}]}`, expected: "rule 1 has no id"},
+		{name: "duplicate id", file: `{"rules": [{"id": "a", "action": "flag"}, {"id": "a", "action": "flag"}]}`, expected: "rule a is defined twice"},
+		{name: "unknown action", file: `{"rules": [{"id": "a", "action": "block"}]}`, expected: "rule a: action must be"},
+		{name: "invalid amount", file: `{"rules": [{"id": "a", "action": "reject", "amountOver": "lots"}]}`, expected: "rule a: amountOver"},
+		{name: "negative amount", file: `{"rules": [{"id": "a", "action": "reject", "amountUnder": "-1"}]}`, expected: "rule a: amountUnder"},
+		{name: "invalid account age", file: `{"rules": [{"id": "a", "action": "flag", "accountAgeUnder": "3 days"}]}`, expected: "rule a: accountAgeUnder"},
+		{name: "invalid window time", file: `{"rules": [{"id": "a", "action": "flag", "window": {"from": "22", "to": "06:00"}}]}`, expected: "rule a: window: from"},
+		{name: "empty window", file: `{"rules": [{"id": "a", "action": "flag", "window": {"from": "06:00", "to": "06:00"}}]}`, expected: "rule a: window: from and to must differ"},
+		{name: "unknown field", file: `{"rules": [{"id": "a", "action": "reject", "soruces": ["game"]}]}`, expected: "invalid rule file"},
+		{name: "unknown top-level field", file: `{"zone": "Europe/Riga", "rules": []}`, expected: "invalid rule file"},
+		{name: "unknown source", file: `{"rules": [{"id": "a", "action": "reject", "sources": ["games"]}]}`, expected: "rule a: sources: unknown value"},
+		{name: "unknown state", file: `{"rules": [{"id": "a", "action": "reject", "states": ["won"]}]}`, expected: "rule a: states: unknown value"},
+		{name: "unknown status", file: `{"rules": [{"id": "a", "action": "flag", "statuses": ["banned"]}]}`, expected: "rule a: statuses: unknown value"},
+		{name: "unknown day", file: `{"rules": [{"id": "a", "action": "flag", "window": {"days": ["funday"], "from": "00:00", "to": "24:00"}}]}`, expected: "rule a: window: unknown day"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.file))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	set, err := Parse([]byte(`{
+		"timeZone": "Europe/Riga",
+		"rules": [
+			{"id": "payment-win-only", "action": "reject", "sources": ["payment"], "states": ["lose"]},
+			{"id": "max-game-win", "action": "reject", "sources": ["game"], "states": ["win"], "amountOver": "10000.00"},
+			{"id": "large-server", "action": "review", "sources": ["server"], "amountOver": "500"},
+			{"id": "tiny-bet", "action": "flag", "states": ["lose"], "amountUnder": "0.10"},
+			{"id": "new-account", "action": "flag", "accountAgeUnder": "72h", "amountOver": "100"},
+			{"id": "watched-user", "action": "flag", "userIds": [7]},
+			{"id": "suspended-win", "action": "flag", "statuses": ["suspended"], "states": ["win"]},
+			{"id": "weekend-night", "action": "flag", "sources": ["server"], "window": {"days": ["sat", "sun"], "from": "22:00", "to": "06:00"}}
+		]
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, 8, set.Len())
+
+	// Wednesday 2025-06-04 12:00 in Riga.
+	noon := time.Date(2025, 6, 4, 9, 0, 0, 0, time.UTC)
+	oldAccount := noon.AddDate(-1, 0, 0)
+	base := Input{UserID: 1, SourceType: "game", State: "win", Amount: 10, Status: "active", AccountCreatedAt: oldAccount, At: noon}
+
+	with := func(change func(in *Input)) Input {
+		in := base
+		change(&in)
+		return in
+	}
+
+	tests := []struct {
+		name     string
+		input    Input
+		expected []string
+	}{
+		{name: "nothing matches", input: base},
+		{name: "payment bet", input: with(func(in *Input) { in.SourceType = "payment"; in.State = "lose" }), expected: []string{"payment-win-only"}},
+		{name: "game win at the maximum", input: with(func(in *Input) { in.Amount = 10000 })},
+		{name: "game win over the maximum", input: with(func(in *Input) { in.Amount = 10000.01 }), expected: []string{"max-game-win"}},
+		{name: "large server credit", input: with(func(in *Input) { in.SourceType = "server"; in.Amount = 750 }), expected: []string{"large-server"}},
+		{name: "tiny bet", input: with(func(in *Input) { in.State = "lose"; in.Amount = 0.05 }), expected: []string{"tiny-bet"}},
+		{name: "new account with a large win", input: with(func(in *Input) { in.Amount = 150; in.AccountCreatedAt = noon.Add(-24 * time.Hour) }), expected: []string{"new-account"}},
+		{name: "new account with a small win", input: with(func(in *Input) { in.AccountCreatedAt = noon.Add(-24 * time.Hour) })},
+		{name: "watched user", input: with(func(in *Input) { in.UserID = 7 }), expected: []string{"watched-user"}},
+		{name: "suspended account", input: with(func(in *Input) { in.Status = "suspended" }), expected: []string{"suspended-win"}},
+		{
+			name:     "several rules match in file order",
+			input:    with(func(in *Input) { in.UserID = 7; in.Amount = 20000 }),
+			expected: []string{"max-game-win", "watched-user"},
+		},
+		{
+			name:     "saturday night",
+			input:    with(func(in *Input) { in.SourceType = "server"; in.At = time.Date(2025, 6, 7, 20, 30, 0, 0, time.UTC) }),
+			expected: []string{"weekend-night"},
+		},
+		{
+			name:     "early sunday belongs to the saturday window",
+			input:    with(func(in *Input) { in.SourceType = "server"; in.At = time.Date(2025, 6, 8, 1, 0, 0, 0, time.UTC) }),
+			expected: []string{"weekend-night"},
+		},
+		{
+			name:  "early saturday belongs to the friday window",
+			input: with(func(in *Input) { in.SourceType = "server"; in.At = time.Date(2025, 6, 7, 1, 0, 0, 0, time.UTC) }),
+		},
+		{
+			name:     "sunday night",
+			input:    with(func(in *Input) { in.SourceType = "server"; in.At = time.Date(2025, 6, 8, 19, 0, 0, 0, time.UTC) }),
+			expected: []string{"weekend-night"},
+		},
+		{
+			name:  "window end is exclusive",
+			input: with(func(in *Input) { in.SourceType = "server"; in.At = time.Date(2025, 6, 8, 3, 0, 0, 0, time.UTC) }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, rule := range set.Evaluate(tt.input) {
+				ids = append(ids, rule.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestWindowWithinADay(t *testing.T) {
+	window := &Window{From: "09:00", To: "17:30"}
+	require.NoError(t, window.compile())
+
+	assert.False(t, window.contains(time.Date(2025, 6, 4, 8, 59, 0, 0, time.UTC)))
+	assert.True(t, window.contains(time.Date(2025, 6, 4, 9, 0, 0, 0, time.UTC)))
+	assert.True(t, window.contains(time.Date(2025, 6, 4, 17, 29, 0, 0, time.UTC)))
+	assert.False(t, window.contains(time.Date(2025, 6, 4, 17, 30, 0, 0, time.UTC)))
+}
+
+func TestEngineReloadsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	writeRules := func(content string, modTime time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	input := Input{SourceType: "game", State: "win", Amount: 50, At: time.Now()}
+	start := time.Now().Add(-time.Hour)
+
+	writeRules(`{"rules": [{"id": "first", "action": "flag"}]}`, start)
+	engine := NewEngine(path)
+	require.NoError(t, engine.Load())
+	require.Len(t, engine.Evaluate(input), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go engine.Watch(ctx, 10*time.Millisecond)
+
+	writeRules(`{"rules": [{"id": "first", "action": "flag"}, {"id": "second", "action": "reject"}]}`, start.Add(time.Minute))
+	assert.Eventually(t, func() bool { return len(engine.Evaluate(input)) == 2 }, time.Second, 10*time.Millisecond)
+
+	writeRules(`{"rules": [`, start.Add(2*time.Minute))
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, engine.Evaluate(input), 2, "an invalid file keeps the previous rules")
+}
+
+func TestEngineWithoutFileHasNoRules(t *testing.T) {
+	engine := NewEngine("")
+	require.NoError(t, engine.Load())
+	assert.Empty(t, engine.Evaluate(Input{SourceType: "game", State: "win", Amount: 1}))
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var statusTransitions = map[string][]string{
+	model.StatusActive:       {model.StatusSuspended, model.StatusSelfExcluded, model.StatusClosed},
+	model.StatusSuspended:    {model.StatusActive, model.StatusSelfExcluded, model.StatusClosed},
+	model.StatusSelfExcluded: {model.StatusActive, model.StatusClosed},
+	model.StatusClosed:       {},
+}
+
+// statusPolicies lists, per account status and source type, the transaction
+// states that are still accepted. Active accounts accept everything.
+var statusPolicies = map[string]map[string][]string{
+	model.StatusSuspended: {
+		"payment":    {"win"},
+		"server":     {"win"},
+		"adjustment": {"win", "lose"},
+	},
+	model.StatusSelfExcluded: {
+		"payment":    {"win", "lose"},
+		"adjustment": {"win", "lose"},
+	},
+	model.StatusClosed: {},
+}
+
+type AccountService struct {
+	userRepo    database.UserRepository
+	accountRepo database.AccountRepository
+	auditRepo   database.AuditRepository
+}
+
+func NewAccountService() *AccountService {
+	return &AccountService{
+		userRepo:    database.NewUserRepository(),
+		accountRepo: database.NewAccountRepository(),
+		auditRepo:   database.NewAuditRepository(),
+	}
+}
+
+func (s *AccountService) ChangeStatus(userID uint64, req dto.ChangeStatusRequest, actor Actor) (*dto.AccountStatusResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"userID":    userID,
+		"status":    req.Status,
+		"actor":     actor.ID,
+		"requestID": actor.RequestID,
+	}).Info("Changing account status")
+
+	var response dto.AccountStatusResponse
+	err := s.userRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		user, err := s.userRepo.GetUserForUpdate(tx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		now := time.Now().UTC()
+		if err := validateStatusTransition(user, req.Status, req.ExcludedUntil, now); err != nil {
+			return err
+		}
+
+		var excludedUntil *time.Time
+		if req.Status == model.StatusSelfExcluded {
+			until := req.ExcludedUntil.UTC()
+			excludedUntil = &until
+		}
+
+		if err := s.accountRepo.UpdateUserStatus(tx, userID, req.Status, excludedUntil); err != nil {
+			return fmt.Errorf("failed to update status: %w", err)
+		}
+
+		change := &model.AccountStatusChange{
+			UserID:        userID,
+			FromStatus:    user.Status,
+			ToStatus:      req.Status,
+			Reason:        req.Reason,
+			ExcludedUntil: excludedUntil,
+			Actor:         actor.ID,
+		}
+		if err := s.accountRepo.CreateStatusChange(tx, change); err != nil {
+			return fmt.Errorf("failed to record status change: %w", err)
+		}
+
+		response = dto.AccountStatusResponse{
+			UserID:        userID,
+			Status:        req.Status,
+			ExcludedUntil: excludedUntil,
+		}
+
+		err = recordAudit(tx, s.auditRepo, actor, AuditChange{
+			Action:       model.AuditAccountStatusChange,
+			TargetUserID: &userID,
+			Resource:     fmt.Sprintf("account_status_changes/%d", change.ID),
+			Before: dto.AccountStatusResponse{
+				UserID:        userID,
+				Status:        user.Status,
+				ExcludedUntil: user.ExcludedUntil,
+			},
+			After: response,
+		})
+		if err != nil {
+			return err
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"userID":     userID,
+			"fromStatus": user.Status,
+			"toStatus":   req.Status,
+			"actor":      actor.ID,
+		}).Info("Account status changed")
+		return nil
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userID": userID, "status": req.Status, "error": err}).Warn("Failed to change account status")
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (s *AccountService) GetStatusHistory(userID uint64) (*dto.StatusHistoryResponse, error) {
+	logrus.WithField("userID", userID).Info("Getting account status history")
+
+	if _, err := s.userRepo.GetUser(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.WithField("userID", userID).Warn("User not found")
+			return nil, errors.New("user not found")
+		}
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to get user")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	changes, err := s.accountRepo.GetStatusChanges(userID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to get status changes")
+		return nil, fmt.Errorf("failed to get status changes: %w", err)
+	}
+
+	response := &dto.StatusHistoryResponse{
+		UserID:  userID,
+		Changes: make([]dto.StatusChangeResponse, 0, len(changes)),
+	}
+	for _, change := range changes {
+		response.Changes = append(response.Changes, dto.StatusChangeResponse{
+			ID:            change.ID,
+			FromStatus:    change.FromStatus,
+			ToStatus:      change.ToStatus,
+			Reason:        change.Reason,
+			ExcludedUntil: change.ExcludedUntil,
+			Actor:         change.Actor,
+			CreatedAt:     change.CreatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+func validateStatusTransition(user *model.User, to string, excludedUntil *time.Time, now time.Time) error {
+	allowed := false
+	for _, status := range statusTransitions[user.Status] {
+		if status == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errors.New("invalid status transition")
+	}
+
+	if to == model.StatusSelfExcluded && (excludedUntil == nil || !excludedUntil.After(now)) {
+		return errors.New("excluded until must be in the future")
+	}
+
+	if user.Status == model.StatusSelfExcluded && to == model.StatusActive &&
+		user.ExcludedUntil != nil && user.ExcludedUntil.After(now) {
+		return errors.New("self-exclusion still active")
+	}
+
+	return nil
+}
+
+// checkAccountPolicy rejects transactions the account status does not allow
+// for the given source type.
+func checkAccountPolicy(user *model.User, sourceType, state string) error {
+	if user.Status == "" || user.Status == model.StatusActive {
+		return nil
+	}
+
+	for _, allowed := range statusPolicies[user.Status][sourceType] {
+		if allowed == state {
+			return nil
+		}
+	}
+
+	switch user.Status {
+	case model.StatusSuspended:
+		return errors.New("account suspended")
+	case model.StatusSelfExcluded:
+		return errors.New("account self-excluded")
+	default:
+		return errors.New("account closed")
+	}
+}
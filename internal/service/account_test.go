@@ -0,0 +1,126 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStatusTransition(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name          string
+		user          *model.User
+		to            string
+		excludedUntil *time.Time
+		expectedError string
+	}{
+		{
+			name: "active to suspended",
+			user: &model.User{Status: model.StatusActive},
+			to:   model.StatusSuspended,
+		},
+		{
+			name: "suspended to active",
+			user: &model.User{Status: model.StatusSuspended},
+			to:   model.StatusActive,
+		},
+		{
+			name:          "active to self-excluded with future date",
+			user:          &model.User{Status: model.StatusActive},
+			to:            model.StatusSelfExcluded,
+			excludedUntil: &future,
+		},
+		{
+			name:          "self-exclusion without date",
+			user:          &model.User{Status: model.StatusActive},
+			to:            model.StatusSelfExcluded,
+			expectedError: "excluded until must be in the future",
+		},
+		{
+			name:          "self-exclusion in the past",
+			user:          &model.User{Status: model.StatusActive},
+			to:            model.StatusSelfExcluded,
+			excludedUntil: &past,
+			expectedError: "excluded until must be in the future",
+		},
+		{
+			name:          "reactivation during self-exclusion",
+			user:          &model.User{Status: model.StatusSelfExcluded, ExcludedUntil: &future},
+			to:            model.StatusActive,
+			expectedError: "self-exclusion still active",
+		},
+		{
+			name: "reactivation after self-exclusion",
+			user: &model.User{Status: model.StatusSelfExcluded, ExcludedUntil: &past},
+			to:   model.StatusActive,
+		},
+		{
+			name:          "self-excluded to suspended",
+			user:          &model.User{Status: model.StatusSelfExcluded, ExcludedUntil: &future},
+			to:            model.StatusSuspended,
+			expectedError: "invalid status transition",
+		},
+		{
+			name:          "closed is final",
+			user:          &model.User{Status: model.StatusClosed},
+			to:            model.StatusActive,
+			expectedError: "invalid status transition",
+		},
+		{
+			name:          "same status",
+			user:          &model.User{Status: model.StatusActive},
+			to:            model.StatusActive,
+			expectedError: "invalid status transition",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStatusTransition(tt.user, tt.to, tt.excludedUntil, now)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckAccountPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		sourceType    string
+		state         string
+		expectedError string
+	}{
+		{name: "active game bet", status: model.StatusActive, sourceType: "game", state: "lose"},
+		{name: "legacy user without status", status: "", sourceType: "game", state: "win"},
+		{name: "suspended payment refund", status: model.StatusSuspended, sourceType: "payment", state: "win"},
+		{name: "suspended game bet", status: model.StatusSuspended, sourceType: "game", state: "lose", expectedError: "account suspended"},
+		{name: "suspended withdrawal", status: model.StatusSuspended, sourceType: "payment", state: "lose", expectedError: "account suspended"},
+		{name: "self-excluded withdrawal", status: model.StatusSelfExcluded, sourceType: "payment", state: "lose"},
+		{name: "self-excluded game win", status: model.StatusSelfExcluded, sourceType: "game", state: "win", expectedError: "account self-excluded"},
+		{name: "closed payment refund", status: model.StatusClosed, sourceType: "payment", state: "win", expectedError: "account closed"},
+		{name: "suspended adjustment debit", status: model.StatusSuspended, sourceType: "adjustment", state: "lose"},
+		{name: "self-excluded adjustment credit", status: model.StatusSelfExcluded, sourceType: "adjustment", state: "win"},
+		{name: "closed adjustment credit", status: model.StatusClosed, sourceType: "adjustment", state: "win", expectedError: "account closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAccountPolicy(&model.User{Status: tt.status}, tt.sourceType, tt.state)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/stream"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type AdjustmentService struct {
+	adjustmentRepo database.AdjustmentRepository
+	userRepo       database.UserRepository
+	auditRepo      database.AuditRepository
+	userService    *UserService
+}
+
+func NewAdjustmentService() *AdjustmentService {
+	return &AdjustmentService{
+		adjustmentRepo: database.NewAdjustmentRepository(),
+		userRepo:       database.NewUserRepository(),
+		auditRepo:      database.NewAuditRepository(),
+		userService:    NewUserService(),
+	}
+}
+
+// RequestAdjustment records a proposed credit or debit. Nothing is booked
+// until another operator approves it.
+func (s *AdjustmentService) RequestAdjustment(userID uint64, req dto.CreateAdjustmentRequest, actor Actor) (*dto.AdjustmentResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"userID":     userID,
+		"direction":  req.Direction,
+		"amount":     req.Amount,
+		"reasonCode": req.ReasonCode,
+		"actor":      actor.ID,
+	}).Info("Requesting balance adjustment")
+
+	adjustment := &model.BalanceAdjustment{
+		UserID:      userID,
+		Direction:   req.Direction,
+		Amount:      req.Amount,
+		ReasonCode:  req.ReasonCode,
+		Note:        req.Note,
+		Status:      model.AdjustmentPending,
+		RequestedBy: actor.ID,
+	}
+	err := s.adjustmentRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if _, err := s.userRepo.GetUserForUpdate(tx, userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		if err := s.adjustmentRepo.CreateAdjustment(tx, adjustment); err != nil {
+			return fmt.Errorf("failed to create adjustment: %w", err)
+		}
+
+		return recordAudit(tx, s.auditRepo, actor, AuditChange{
+			Action:       model.AuditAdjustmentRequest,
+			TargetUserID: &userID,
+			Resource:     adjustmentResource(adjustment.ID),
+			After:        adjustmentResponse(*adjustment),
+		})
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Warn("Failed to request balance adjustment")
+		return nil, err
+	}
+
+	response := adjustmentResponse(*adjustment)
+	return &response, nil
+}
+
+func (s *AdjustmentService) GetAdjustment(adjustmentID uint64) (*dto.AdjustmentResponse, error) {
+	adjustment, err := s.adjustmentRepo.GetAdjustment(adjustmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("adjustment not found")
+		}
+		logrus.WithFields(logrus.Fields{"adjustmentID": adjustmentID, "error": err}).Error("Failed to get adjustment")
+		return nil, fmt.Errorf("failed to get adjustment: %w", err)
+	}
+
+	response := adjustmentResponse(*adjustment)
+	return &response, nil
+}
+
+func (s *AdjustmentService) ListAdjustments(status string, userID uint64, page, pageSize int) (*dto.AdjustmentListResponse, error) {
+	logrus.WithFields(logrus.Fields{"status": status, "userID": userID, "page": page}).Info("Listing adjustments")
+
+	adjustments, total, err := s.adjustmentRepo.ListAdjustments(status, userID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to list adjustments")
+		return nil, fmt.Errorf("failed to list adjustments: %w", err)
+	}
+
+	response := &dto.AdjustmentListResponse{
+		Adjustments: make([]dto.AdjustmentResponse, 0, len(adjustments)),
+		Page:        page,
+		PageSize:    pageSize,
+		Total:       total,
+	}
+	for _, adjustment := range adjustments {
+		response.Adjustments = append(response.Adjustments, adjustmentResponse(adjustment))
+	}
+	return response, nil
+}
+
+// ApproveAdjustment books the adjustment through the regular transaction path
+// with source type "adjustment". The booking, the status change and the audit
+// entry commit together; if the booking is rejected, for example for
+// insufficient balance, the adjustment stays pending.
+func (s *AdjustmentService) ApproveAdjustment(adjustmentID uint64, req dto.ReviewAdjustmentRequest, actor Actor) (*dto.AdjustmentResponse, error) {
+	logrus.WithFields(logrus.Fields{"adjustmentID": adjustmentID, "actor": actor.ID}).Info("Approving balance adjustment")
+
+	var (
+		adjustment  *model.BalanceAdjustment
+		transaction dto.TransactionRequest
+		update      stream.Message
+	)
+	err := s.userService.withRetries(func() error {
+		return s.adjustmentRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+			var before dto.AdjustmentResponse
+			var err error
+			adjustment, before, err = s.pendingAdjustment(tx, adjustmentID, actor)
+			if err != nil {
+				return err
+			}
+
+			transaction = adjustmentTransaction(adjustment)
+			result, err := s.userService.processTransaction(tx, adjustment.UserID, transaction, "adjustment", nil)
+			if err != nil {
+				return err
+			}
+			if result.review != nil {
+				// Nothing was booked; keep the adjustment pending.
+				return errors.New("adjustment held for review")
+			}
+			update = result.update
+
+			review(adjustment, model.AdjustmentApproved, req.Note, actor, time.Now().UTC())
+			adjustment.TransactionID = &transaction.TransactionID
+			return s.saveReview(tx, adjustment, before, model.AuditAdjustmentApprove, actor)
+		})
+	})
+	if err != nil {
+		if adjustment != nil && transaction.TransactionID != "" {
+			s.userService.recordTransactionFailure(adjustment.UserID, transaction, "adjustment", err)
+		}
+		logrus.WithFields(logrus.Fields{"adjustmentID": adjustmentID, "error": err}).Warn("Failed to approve balance adjustment")
+		return nil, err
+	}
+
+	s.userService.hub.Publish(adjustment.UserID, update)
+
+	response := adjustmentResponse(*adjustment)
+	return &response, nil
+}
+
+func (s *AdjustmentService) RejectAdjustment(adjustmentID uint64, req dto.ReviewAdjustmentRequest, actor Actor) (*dto.AdjustmentResponse, error) {
+	logrus.WithFields(logrus.Fields{"adjustmentID": adjustmentID, "actor": actor.ID}).Info("Rejecting balance adjustment")
+
+	var adjustment *model.BalanceAdjustment
+	err := s.adjustmentRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		var before dto.AdjustmentResponse
+		var err error
+		adjustment, before, err = s.pendingAdjustment(tx, adjustmentID, actor)
+		if err != nil {
+			return err
+		}
+
+		review(adjustment, model.AdjustmentRejected, req.Note, actor, time.Now().UTC())
+		return s.saveReview(tx, adjustment, before, model.AuditAdjustmentReject, actor)
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"adjustmentID": adjustmentID, "error": err}).Warn("Failed to reject balance adjustment")
+		return nil, err
+	}
+
+	response := adjustmentResponse(*adjustment)
+	return &response, nil
+}
+
+// pendingAdjustment locks the adjustment and checks that actor may review it.
+func (s *AdjustmentService) pendingAdjustment(tx *gorm.DB, adjustmentID uint64, actor Actor) (*model.BalanceAdjustment, dto.AdjustmentResponse, error) {
+	adjustment, err := s.adjustmentRepo.GetAdjustmentForUpdate(tx, adjustmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.AdjustmentResponse{}, errors.New("adjustment not found")
+		}
+		return nil, dto.AdjustmentResponse{}, fmt.Errorf("failed to get adjustment: %w", err)
+	}
+
+	if err := checkReviewer(adjustment, actor); err != nil {
+		return nil, dto.AdjustmentResponse{}, err
+	}
+	return adjustment, adjustmentResponse(*adjustment), nil
+}
+
+func (s *AdjustmentService) saveReview(tx *gorm.DB, adjustment *model.BalanceAdjustment, before dto.AdjustmentResponse, action string, actor Actor) error {
+	if err := s.adjustmentRepo.SaveAdjustment(tx, adjustment); err != nil {
+		return fmt.Errorf("failed to save adjustment: %w", err)
+	}
+
+	return recordAudit(tx, s.auditRepo, actor, AuditChange{
+		Action:       action,
+		TargetUserID: &adjustment.UserID,
+		Resource:     adjustmentResource(adjustment.ID),
+		Before:       before,
+		After:        adjustmentResponse(*adjustment),
+	})
+}
+
+// checkReviewer enforces maker-checker: only pending adjustments can be
+// reviewed, and never by the operator who requested them.
+func checkReviewer(adjustment *model.BalanceAdjustment, actor Actor) error {
+	if adjustment.Status != model.AdjustmentPending {
+		return errors.New("adjustment already reviewed")
+	}
+	if adjustment.RequestedBy == actor.ID {
+		return errors.New("reviewer is the requester")
+	}
+	return nil
+}
+
+func review(adjustment *model.BalanceAdjustment, status, note string, actor Actor, now time.Time) {
+	adjustment.Status = status
+	adjustment.ReviewedBy = &actor.ID
+	adjustment.ReviewedAt = &now
+	if note != "" {
+		adjustment.ReviewNote = &note
+	}
+}
+
+// adjustmentTransaction is the ledger transaction an approved adjustment
+// books. Its ID links the transaction back to the adjustment and keeps a
+// repeated approval from booking twice.
+func adjustmentTransaction(adjustment *model.BalanceAdjustment) dto.TransactionRequest {
+	state := "win"
+	if adjustment.Direction == model.AdjustmentDebit {
+		state = "lose"
+	}
+	return dto.TransactionRequest{
+		State:         state,
+		Amount:        adjustment.Amount,
+		TransactionID: fmt.Sprintf("adjustment-%d", adjustment.ID),
+	}
+}
+
+func adjustmentResource(adjustmentID uint64) string {
+	return fmt.Sprintf("balance_adjustments/%d", adjustmentID)
+}
+
+func adjustmentResponse(adjustment model.BalanceAdjustment) dto.AdjustmentResponse {
+	return dto.AdjustmentResponse{
+		ID:            adjustment.ID,
+		UserID:        adjustment.UserID,
+		Direction:     adjustment.Direction,
+		Amount:        adjustment.Amount,
+		ReasonCode:    adjustment.ReasonCode,
+		Note:          adjustment.Note,
+		Status:        adjustment.Status,
+		RequestedBy:   adjustment.RequestedBy,
+		ReviewedBy:    adjustment.ReviewedBy,
+		ReviewNote:    adjustment.ReviewNote,
+		ReviewedAt:    adjustment.ReviewedAt,
+		TransactionID: adjustment.TransactionID,
+		CreatedAt:     adjustment.CreatedAt,
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckReviewer(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		reviewer      string
+		expectedError string
+	}{
+		{name: "different operator", status: model.AdjustmentPending, reviewer: "bob"},
+		{name: "requester reviews own request", status: model.AdjustmentPending, reviewer: "alice", expectedError: "reviewer is the requester"},
+		{name: "already approved", status: model.AdjustmentApproved, reviewer: "bob", expectedError: "adjustment already reviewed"},
+		{name: "already rejected", status: model.AdjustmentRejected, reviewer: "alice", expectedError: "adjustment already reviewed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adjustment := &model.BalanceAdjustment{Status: tt.status, RequestedBy: "alice"}
+			err := checkReviewer(adjustment, Actor{ID: tt.reviewer})
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAdjustmentTransaction(t *testing.T) {
+	tests := []struct {
+		direction string
+		expected  dto.TransactionRequest
+	}{
+		{model.AdjustmentCredit, dto.TransactionRequest{State: "win", Amount: "12.50", TransactionID: "adjustment-7"}},
+		{model.AdjustmentDebit, dto.TransactionRequest{State: "lose", Amount: "12.50", TransactionID: "adjustment-7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			adjustment := &model.BalanceAdjustment{ID: 7, Direction: tt.direction, Amount: "12.50"}
+			assert.Equal(t, tt.expected, adjustmentTransaction(adjustment))
+		})
+	}
+}
+
+func TestReview(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	adjustment := &model.BalanceAdjustment{Status: model.AdjustmentPending, RequestedBy: "alice"}
+	review(adjustment, model.AdjustmentRejected, "", Actor{ID: "bob"}, now)
+	assert.Equal(t, model.AdjustmentRejected, adjustment.Status)
+	assert.Equal(t, "bob", *adjustment.ReviewedBy)
+	assert.Equal(t, now, *adjustment.ReviewedAt)
+	assert.Nil(t, adjustment.ReviewNote)
+
+	adjustment = &model.BalanceAdjustment{Status: model.AdjustmentPending, RequestedBy: "alice"}
+	review(adjustment, model.AdjustmentApproved, "ticket 4411", Actor{ID: "bob"}, now)
+	assert.Equal(t, "ticket 4411", *adjustment.ReviewNote)
+}
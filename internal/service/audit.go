@@ -0,0 +1,133 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Actor identifies who performs a privileged operation and the request it
+// arrived with, so the audit log can be correlated with access logs.
+type Actor struct {
+	ID        string
+	RequestID string
+}
+
+// AuditChange describes one privileged change. Before and After are stored as
+// JSON; a nil value means the state did not exist on that side.
+type AuditChange struct {
+	Action       string
+	TargetUserID *uint64
+	Resource     string
+	Before       interface{}
+	After        interface{}
+}
+
+type AuditService struct {
+	auditRepo database.AuditRepository
+}
+
+func NewAuditService() *AuditService {
+	return &AuditService{
+		auditRepo: database.NewAuditRepository(),
+	}
+}
+
+func (s *AuditService) ListEntries(filter database.AuditFilter, page, pageSize int) (*dto.AuditListResponse, error) {
+	logrus.WithFields(logrus.Fields{
+		"actor":        filter.Actor,
+		"targetUserID": filter.TargetUserID,
+		"from":         filter.From,
+		"to":           filter.To,
+		"page":         page,
+	}).Info("Listing audit entries")
+
+	entries, total, err := s.auditRepo.ListEntries(filter, (page-1)*pageSize, pageSize)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to list audit entries")
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	response := &dto.AuditListResponse{
+		Entries:  make([]dto.AuditEntryResponse, 0, len(entries)),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, auditEntryResponse(entry))
+	}
+	return response, nil
+}
+
+// recordAudit writes the audit entry for change inside tx, so the entry is
+// committed or rolled back together with the change itself.
+func recordAudit(tx *gorm.DB, repo database.AuditRepository, actor Actor, change AuditChange) error {
+	entry, err := auditEntry(actor, change, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if err := repo.CreateEntry(tx, entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+func auditEntry(actor Actor, change AuditChange, now time.Time) (*model.AuditEntry, error) {
+	before, err := auditState(change.Before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit state: %w", err)
+	}
+	after, err := auditState(change.After)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit state: %w", err)
+	}
+
+	return &model.AuditEntry{
+		Actor:        actor.ID,
+		Action:       change.Action,
+		TargetUserID: change.TargetUserID,
+		Resource:     change.Resource,
+		Before:       before,
+		After:        after,
+		RequestID:    actor.RequestID,
+		CreatedAt:    now,
+	}, nil
+}
+
+func auditState(state interface{}) (*string, error) {
+	if state == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	value := string(encoded)
+	return &value, nil
+}
+
+func auditEntryResponse(entry model.AuditEntry) dto.AuditEntryResponse {
+	response := dto.AuditEntryResponse{
+		ID:           entry.ID,
+		Actor:        entry.Actor,
+		Action:       entry.Action,
+		TargetUserID: entry.TargetUserID,
+		Resource:     entry.Resource,
+		RequestID:    entry.RequestID,
+		CreatedAt:    entry.CreatedAt,
+	}
+	if entry.Before != nil {
+		response.Before = json.RawMessage(*entry.Before)
+	}
+	if entry.After != nil {
+		response.After = json.RawMessage(*entry.After)
+	}
+	return response
+}
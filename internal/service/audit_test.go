@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditEntry(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	userID := uint64(7)
+
+	entry, err := auditEntry(Actor{ID: "alice", RequestID: "req-1"}, AuditChange{
+		Action:       model.AuditAccountStatusChange,
+		TargetUserID: &userID,
+		Resource:     "account_status_changes/3",
+		Before:       dto.AccountStatusResponse{UserID: userID, Status: model.StatusActive},
+		After:        dto.AccountStatusResponse{UserID: userID, Status: model.StatusSuspended},
+	}, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", entry.Actor)
+	assert.Equal(t, "req-1", entry.RequestID)
+	assert.Equal(t, model.AuditAccountStatusChange, entry.Action)
+	assert.Equal(t, &userID, entry.TargetUserID)
+	assert.Equal(t, now, entry.CreatedAt)
+	assert.JSONEq(t, `{"userId":7,"status":"active"}`, *entry.Before)
+	assert.JSONEq(t, `{"userId":7,"status":"suspended"}`, *entry.After)
+
+	response := auditEntryResponse(*entry)
+	assert.JSONEq(t, `{"userId":7,"status":"suspended"}`, string(response.After))
+}
+
+func TestAuditEntryWithoutBefore(t *testing.T) {
+	entry, err := auditEntry(Actor{ID: "alice"}, AuditChange{
+		Action:   model.AuditWebhookCreate,
+		Resource: "webhooks/1",
+		After:    dto.WebhookResponse{ID: 1, URL: "https://example.com/hook"},
+	}, time.Now())
+
+	assert.NoError(t, err)
+	assert.Nil(t, entry.Before)
+	assert.Nil(t, entry.TargetUserID)
+	assert.NotContains(t, *entry.After, "secret")
+
+	response := auditEntryResponse(*entry)
+	assert.Nil(t, response.Before)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/hashchain"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type BackfillService struct {
+	backfillRepo database.BackfillRepository
+}
+
+func NewBackfillService() *BackfillService {
+	return &BackfillService{
+		backfillRepo: database.NewBackfillRepository(),
+	}
+}
+
+// transactionBalance is the balance before and after one transaction.
+type transactionBalance struct {
+	ID     uint64
+	Before string
+	After  string
+}
+
+// BackfillUser stores the balance before and after every transaction of the
+// user that was booked before balances were recorded, then re-links the
+// user's chain from the first row linked at an older chain version, so the
+// balances and rounds are covered by the hash. The chain is verified first; a
+// broken chain is not re-linked, as that would hide the tampering. The user
+// row is locked so no transaction is booked while the history is walked.
+func (s *BackfillService) BackfillUser(userID uint64) (*dto.BalanceBackfillResponse, error) {
+	logrus.WithField("userID", userID).Info("Backfilling transaction balances")
+
+	var backfilled, relinked int
+	err := s.backfillRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		user, err := s.backfillRepo.GetUserForUpdate(tx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		transactions, err := s.backfillRepo.GetTransactionsNewestFirst(tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+
+		balances, err := backfillBalances(user.Balance, transactions)
+		if err != nil {
+			return err
+		}
+		filled := make(map[uint64]transactionBalance, len(balances))
+		for _, balance := range balances {
+			filled[balance.ID] = balance
+		}
+
+		oldestFirst := make([]model.Transaction, len(transactions))
+		for i, transaction := range transactions {
+			if balance, ok := filled[transaction.ID]; ok {
+				transaction.BalanceBefore = &balance.Before
+				transaction.BalanceAfter = &balance.After
+			}
+			oldestFirst[len(transactions)-1-i] = transaction
+		}
+
+		changed, broken, err := relinkChain(oldestFirst)
+		if err != nil {
+			return err
+		}
+		if broken != nil {
+			logrus.WithFields(logrus.Fields{
+				"userID":        userID,
+				"transactionID": broken.Transaction.ID,
+				"reason":        broken.Reason,
+			}).Error("Refusing to backfill a broken hash chain")
+			return fmt.Errorf("hash chain broken at transaction %d: %s", broken.Transaction.ID, broken.Reason)
+		}
+
+		for _, transaction := range changed {
+			if err := s.backfillRepo.UpdateTransactionChain(tx, transaction); err != nil {
+				return fmt.Errorf("failed to store transaction %d: %w", transaction.ID, err)
+			}
+			delete(filled, transaction.ID)
+		}
+		// Rows already at the current version have their balances stored.
+		for _, transaction := range oldestFirst {
+			if _, ok := filled[transaction.ID]; ok {
+				if err := s.backfillRepo.UpdateTransactionChain(tx, transaction); err != nil {
+					return fmt.Errorf("failed to store transaction %d: %w", transaction.ID, err)
+				}
+			}
+		}
+		backfilled, relinked = len(balances), len(changed)
+		return nil
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to backfill transaction balances")
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{"userID": userID, "backfilled": backfilled, "relinked": relinked}).Info("Transaction balances backfilled")
+	return &dto.BalanceBackfillResponse{UserID: userID, Backfilled: backfilled, Relinked: relinked}, nil
+}
+
+// BackfillUserIDs returns every user with transactions that have no balances
+// stored or are linked at an older chain version.
+func (s *BackfillService) BackfillUserIDs() ([]uint64, error) {
+	return s.backfillRepo.GetUserIDsToBackfill()
+}
+
+// relinkChain verifies a user's chain, given oldest first, and re-links it at
+// the current version from the first row linked at an older one. Every later
+// row is re-linked too, as its previous hash changes. It returns the rows that
+// changed, or the first break when the chain does not verify.
+func relinkChain(oldestFirst []model.Transaction) ([]model.Transaction, *hashchain.Break, error) {
+	var verifier hashchain.Verifier
+	for _, transaction := range oldestFirst {
+		broken, err := verifier.Check(transaction)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to verify transaction %d: %w", transaction.ID, err)
+		}
+		if broken != nil {
+			return nil, broken, nil
+		}
+	}
+
+	var changed []model.Transaction
+	previous := ""
+	for _, transaction := range oldestFirst {
+		if len(changed) > 0 || transaction.ChainVersion != hashchain.Version {
+			if err := hashchain.Link(previous, &transaction); err != nil {
+				return nil, nil, fmt.Errorf("failed to link transaction %d: %w", transaction.ID, err)
+			}
+			changed = append(changed, transaction)
+		}
+		previous = transaction.Hash
+	}
+	return changed, nil, nil
+}
+
+// backfillBalances walks a user's transactions from the newest back, starting
+// from the user's current balance, and returns the balances of the ones that
+// have none stored. A stored balance re-anchors the walk, so rows that were
+// already recorded are trusted over the sum of what came after them.
+func backfillBalances(current string, newestFirst []model.Transaction) ([]transactionBalance, error) {
+	balance, err := parseAmount(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid balance: %w", err)
+	}
+
+	var balances []transactionBalance
+	for _, transaction := range newestFirst {
+		if transaction.BalanceBefore != nil && transaction.BalanceAfter != nil {
+			if balance, err = parseAmount(*transaction.BalanceBefore); err != nil {
+				return nil, fmt.Errorf("invalid balance of transaction %d: %w", transaction.ID, err)
+			}
+			continue
+		}
+
+		amount, err := parseAmount(transaction.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount of transaction %d: %w", transaction.ID, err)
+		}
+		before := roundAmount(balance - signedAmount(transaction.State, amount))
+		balances = append(balances, transactionBalance{
+			ID:     transaction.ID,
+			Before: formatAmount(before),
+			After:  formatAmount(balance),
+		})
+		balance = before
+	}
+	return balances, nil
+}
+
+// signedAmount is the change a transaction makes to the balance.
+func signedAmount(state string, amount float64) float64 {
+	if state == "lose" {
+		return -amount
+	}
+	return amount
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/hashchain"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillBalances(t *testing.T) {
+	stored := func(amount string) *string { return &amount }
+
+	tests := []struct {
+		name         string
+		current      string
+		transactions []model.Transaction
+		want         []transactionBalance
+		wantErr      bool
+	}{
+		{
+			name:    "walks back from the current balance",
+			current: "25.00",
+			transactions: []model.Transaction{
+				{ID: 3, State: "lose", Amount: "5.00"},
+				{ID: 2, State: "win", Amount: "20.00"},
+				{ID: 1, State: "win", Amount: "10.00"},
+			},
+			want: []transactionBalance{
+				{ID: 3, Before: "30.00", After: "25.00"},
+				{ID: 2, Before: "10.00", After: "30.00"},
+				{ID: 1, Before: "0.00", After: "10.00"},
+			},
+		},
+		{
+			name:    "stored balances re-anchor the walk",
+			current: "40.00",
+			transactions: []model.Transaction{
+				{ID: 3, State: "win", Amount: "15.00", BalanceBefore: stored("25.00"), BalanceAfter: stored("40.00")},
+				{ID: 2, State: "lose", Amount: "0.10"},
+				{ID: 1, State: "win", Amount: "25.10"},
+			},
+			want: []transactionBalance{
+				{ID: 2, Before: "25.10", After: "25.00"},
+				{ID: 1, Before: "0.00", After: "25.10"},
+			},
+		},
+		{
+			name:    "nothing to backfill",
+			current: "10.00",
+			transactions: []model.Transaction{
+				{ID: 1, State: "win", Amount: "10.00", BalanceBefore: stored("0.00"), BalanceAfter: stored("10.00")},
+			},
+			want: nil,
+		},
+		{
+			name:    "invalid amount",
+			current: "10.00",
+			transactions: []model.Transaction{
+				{ID: 1, State: "win", Amount: "abc"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := backfillBalances(tt.current, tt.transactions)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// linkedChain links transactions in order, each at the chain version it
+// carries, as they would have been stored.
+func linkedChain(t *testing.T, transactions ...model.Transaction) []model.Transaction {
+	previous := ""
+	for i := range transactions {
+		transactions[i].ID = uint64(i + 1)
+		hash, err := hashchain.Hash(previous, transactions[i])
+		require.NoError(t, err)
+		transactions[i].PreviousHash = nil
+		if previous != "" {
+			link := previous
+			transactions[i].PreviousHash = &link
+		}
+		transactions[i].Hash = hash
+		previous = hash
+	}
+	return transactions
+}
+
+func TestRelinkChain(t *testing.T) {
+	created := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	stored := func(amount string) *string { return &amount }
+	legacy := func(id string, state, amount string) model.Transaction {
+		return model.Transaction{UserID: 1, TransactionID: id, State: state, Amount: amount, SourceType: "game", ChainVersion: 1, CreatedAt: created}
+	}
+	current := func(id string, state, amount, before, after string) model.Transaction {
+		return model.Transaction{UserID: 1, TransactionID: id, State: state, Amount: amount, SourceType: "game", ChainVersion: hashchain.Version,
+			BalanceBefore: stored(before), BalanceAfter: stored(after), CreatedAt: created}
+	}
+
+	tests := []struct {
+		name        string
+		chain       func(t *testing.T) []model.Transaction
+		wantChanged []uint64
+		wantBreak   string
+	}{
+		{
+			name: "re-links from the first old row to the end",
+			chain: func(t *testing.T) []model.Transaction {
+				return linkedChain(t,
+					legacy("tx-1", "win", "10.00"),
+					legacy("tx-2", "lose", "5.00"),
+					current("tx-3", "win", "1.00", "5.00", "6.00"),
+				)
+			},
+			wantChanged: []uint64{1, 2, 3},
+		},
+		{
+			name: "keeps current rows before the first old one",
+			chain: func(t *testing.T) []model.Transaction {
+				return linkedChain(t,
+					current("tx-1", "win", "10.00", "0.00", "10.00"),
+					legacy("tx-2", "lose", "5.00"),
+				)
+			},
+			wantChanged: []uint64{2},
+		},
+		{
+			name: "nothing to re-link",
+			chain: func(t *testing.T) []model.Transaction {
+				return linkedChain(t, current("tx-1", "win", "10.00", "0.00", "10.00"))
+			},
+		},
+		{
+			name: "refuses a broken chain",
+			chain: func(t *testing.T) []model.Transaction {
+				chain := linkedChain(t,
+					legacy("tx-1", "win", "10.00"),
+					legacy("tx-2", "lose", "5.00"),
+				)
+				chain[1].Amount = "0.50"
+				return chain
+			},
+			wantBreak: hashchain.ReasonHashMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := tt.chain(t)
+			changed, broken, err := relinkChain(chain)
+			require.NoError(t, err)
+			if tt.wantBreak != "" {
+				require.NotNil(t, broken)
+				assert.Equal(t, tt.wantBreak, broken.Reason)
+				assert.Empty(t, changed)
+				return
+			}
+			require.Nil(t, broken)
+
+			var ids []uint64
+			for _, transaction := range changed {
+				ids = append(ids, transaction.ID)
+				assert.Equal(t, hashchain.Version, transaction.ChainVersion)
+			}
+			assert.Equal(t, tt.wantChanged, ids)
+
+			// The chain with the re-linked rows in place verifies.
+			byID := map[uint64]model.Transaction{}
+			for _, transaction := range changed {
+				byID[transaction.ID] = transaction
+			}
+			var verifier hashchain.Verifier
+			for _, transaction := range chain {
+				if relinked, ok := byID[transaction.ID]; ok {
+					transaction = relinked
+				}
+				broken, err := verifier.Check(transaction)
+				require.NoError(t, err)
+				assert.Nil(t, broken)
+			}
+		})
+	}
+}
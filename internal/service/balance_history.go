@@ -0,0 +1,128 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// GetBalanceAt returns the user's balance right after the last transaction
+// created at or before at was applied, together with that transaction.
+// Transactions are created under the user's row lock, so they are created in
+// the order they were applied.
+//
+// The balance starts from the nearest snapshot: the last one taken at or
+// before at is rolled forward, otherwise the first one after it (or the
+// current balance) is rolled back. Only the transactions between the snapshot
+// and at are summed.
+func (s *UserService) GetBalanceAt(userID uint64, at time.Time) (*dto.HistoricalBalanceResponse, error) {
+	logrus.WithFields(logrus.Fields{"userID": userID, "at": at}).Info("Getting historical balance")
+
+	var response *dto.HistoricalBalanceResponse
+	err := s.snapshotRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		user, err := s.snapshotRepo.GetUser(tx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		last, err := s.snapshotRepo.GetLastTransaction(tx, userID, at)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get last transaction: %w", err)
+		}
+		var through uint64
+		if err == nil {
+			through = last.ID
+		}
+
+		balance, err := s.balanceAt(tx, user, through)
+		if err != nil {
+			return err
+		}
+
+		response = &dto.HistoricalBalanceResponse{
+			UserID:  userID,
+			Balance: formatAmount(balance),
+			At:      at,
+		}
+		if through != 0 {
+			response.LastTransaction = historicalTransaction(last)
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		if err.Error() == "user not found" {
+			logrus.WithField("userID", userID).Warn("User not found")
+		} else {
+			logrus.WithFields(logrus.Fields{"userID": userID, "at": at, "error": err}).Error("Failed to get historical balance")
+		}
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{"userID": userID, "at": at, "balance": response.Balance}).Info("Historical balance retrieved successfully")
+	return response, nil
+}
+
+// balanceAt returns the user's balance right after the transaction with
+// transactionID was applied; 0 is before the user's first transaction.
+func (s *UserService) balanceAt(tx *gorm.DB, user *model.User, transactionID uint64) (float64, error) {
+	before, err := s.snapshotRepo.GetSnapshotAtOrBefore(tx, user.ID, transactionID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	if err == nil {
+		return s.rollBalance(tx, user.ID, before.Balance, &before.TransactionID, &transactionID, 1)
+	}
+
+	base := user.Balance
+	var through *uint64
+	after, err := s.snapshotRepo.GetSnapshotAfter(tx, user.ID, transactionID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+	if err == nil {
+		base = after.Balance
+		through = &after.TransactionID
+	}
+	return s.rollBalance(tx, user.ID, base, &transactionID, through, -1)
+}
+
+// rollBalance adds (direction 1) or removes (direction -1) the net effect of
+// the transactions between after and through to a base balance.
+func (s *UserService) rollBalance(tx *gorm.DB, userID uint64, base string, after, through *uint64, direction float64) (float64, error) {
+	balance, err := parseAmount(base)
+	if err != nil {
+		return 0, fmt.Errorf("invalid base balance: %w", err)
+	}
+
+	sum, err := s.snapshotRepo.NetTransactions(tx, userID, after, through)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+	net, err := parseAmount(sum)
+	if err != nil {
+		return 0, fmt.Errorf("invalid transaction sum: %w", err)
+	}
+
+	return roundAmount(balance + direction*net), nil
+}
+
+func historicalTransaction(transaction *model.Transaction) *dto.HistoricalTransaction {
+	return &dto.HistoricalTransaction{
+		TransactionID: transaction.TransactionID,
+		State:         transaction.State,
+		Amount:        transaction.Amount,
+		SourceType:    transaction.SourceType,
+		BalanceBefore: transaction.BalanceBefore,
+		BalanceAfter:  transaction.BalanceAfter,
+		CreatedAt:     transaction.CreatedAt,
+	}
+}
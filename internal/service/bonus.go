@@ -0,0 +1,600 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/config"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/stream"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func (s *UserService) GetBonusProgress(userID uint64) (*dto.BonusProgressResponse, error) {
+	logrus.WithField("userID", userID).Info("Getting bonus progress")
+
+	if _, err := s.userRepo.GetUser(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.WithField("userID", userID).Warn("User not found")
+			return nil, errors.New("user not found")
+		}
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to get user")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	bonuses, err := s.bonusRepo.GetBonuses(userID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to get bonuses")
+		return nil, fmt.Errorf("failed to get bonuses: %w", err)
+	}
+
+	response := &dto.BonusProgressResponse{
+		UserID:  userID,
+		Bonuses: make([]dto.BonusProgress, 0, len(bonuses)),
+	}
+	for _, bonus := range bonuses {
+		progress, err := bonusProgress(bonus)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{"userID": userID, "bonusID": bonus.ID, "error": err}).Error("Invalid bonus amounts")
+			return nil, fmt.Errorf("invalid bonus amounts: %w", err)
+		}
+		response.Bonuses = append(response.Bonuses, progress)
+	}
+
+	return response, nil
+}
+
+// expireBonuses forfeits every active bonus whose expiry has passed and
+// returns the bonuses that are still active, along with the last forfeit
+// transaction it booked, if any. Forfeited funds are booked as a server
+// "lose" transaction so the ledger keeps explaining the total balance.
+func (s *UserService) expireBonuses(tx *gorm.DB, user *model.User, balances walletBalances, bonuses []model.Bonus, now time.Time) ([]model.Bonus, *model.Transaction, error) {
+	var active, expired []model.Bonus
+	var forfeit *model.Transaction
+	for _, bonus := range bonuses {
+		if bonus.ExpiresAt.After(now) {
+			active = append(active, bonus)
+		} else {
+			expired = append(expired, bonus)
+		}
+	}
+
+	for i := range expired {
+		bonus := &expired[i]
+		held, err := parseAmount(bonus.Balance)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid bonus balance: %w", err)
+		}
+
+		forfeitLocked := len(active) == 0 && i == len(expired)-1
+		deltas := forfeitDeltas(balances, held, forfeitLocked)
+
+		bonus.Status = model.BonusForfeited
+		bonus.Balance = formatAmount(0)
+		if err := s.bonusRepo.UpdateBonus(tx, bonus); err != nil {
+			return nil, nil, fmt.Errorf("failed to forfeit bonus: %w", err)
+		}
+
+		forfeited := 0.0
+		for _, delta := range deltas {
+			forfeited -= delta
+		}
+		forfeited = roundAmount(forfeited)
+
+		logrus.WithFields(logrus.Fields{
+			"userID":    user.ID,
+			"bonusID":   bonus.ID,
+			"forfeited": forfeited,
+		}).Info("Bonus expired and forfeited")
+
+		if forfeited == 0 {
+			continue
+		}
+
+		total, err := parseAmount(user.Balance)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid current balance: %w", err)
+		}
+		remaining := roundAmount(total - forfeited)
+		user.Balance = formatAmount(remaining)
+		if err := s.updateBalance(tx, user, user.Balance); err != nil {
+			if errors.Is(err, errVersionConflict) {
+				return nil, nil, err
+			}
+			return nil, nil, fmt.Errorf("failed to update balance: %w", err)
+		}
+
+		transaction := &model.Transaction{
+			UserID:        user.ID,
+			TransactionID: fmt.Sprintf("bonus-forfeit-%d", bonus.ID),
+			Amount:        formatAmount(forfeited),
+			State:         "lose",
+			SourceType:    "server",
+		}
+		setBalances(transaction, total, remaining)
+		if err := s.createTransaction(tx, transaction); err != nil {
+			return nil, nil, fmt.Errorf("failed to create forfeit transaction: %w", err)
+		}
+
+		entry := model.WalletEntry{UserID: user.ID, TransactionID: &transaction.ID, BonusID: &bonus.ID}
+		if err := s.applyWalletDeltas(tx, entry, balances, deltas, nil); err != nil {
+			return nil, nil, err
+		}
+
+		if err := s.recordBalanceChange(tx, transaction, total, remaining, balances, now); err != nil {
+			return nil, nil, err
+		}
+		forfeit = transaction
+	}
+
+	return active, forfeit, nil
+}
+
+// ExpireBonuses forfeits the expired bonuses of users who have not transacted
+// since they expired, and returns how many users it forfeited bonuses of.
+// Each user is handled in a database transaction of its own, under the same
+// locks as a transaction.
+func (s *UserService) ExpireBonuses(now time.Time) (int, error) {
+	userIDs, err := s.bonusRepo.ListUsersWithExpiredBonuses(now, expiredBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list users with expired bonuses: %w", err)
+	}
+
+	expired := 0
+	for _, userID := range userIDs {
+		if err := s.expireUserBonuses(userID); err != nil {
+			return expired, fmt.Errorf("failed to forfeit bonuses of user %d: %w", userID, err)
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func (s *UserService) expireUserBonuses(userID uint64) error {
+	var update *stream.Message
+	err := s.userRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		open, err := s.closingRepo.LockOpenPeriod(tx, now)
+		if err != nil {
+			return fmt.Errorf("failed to check business day: %w", err)
+		}
+		if !open {
+			return errors.New("business day closed")
+		}
+
+		user, err := s.userRepo.GetUserForUpdate(tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		wallets, err := s.walletRepo.GetWalletsForUpdate(tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallets: %w", err)
+		}
+		balances, err := walletBalancesFor(user, wallets)
+		if err != nil {
+			return fmt.Errorf("invalid wallet balance: %w", err)
+		}
+		bonuses, err := s.bonusRepo.GetActiveBonusesForUpdate(tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get active bonuses: %w", err)
+		}
+
+		_, forfeit, err := s.expireBonuses(tx, user, balances, bonuses, now)
+		if err != nil || forfeit == nil {
+			return err
+		}
+
+		balance, err := parseAmount(user.Balance)
+		if err != nil {
+			return fmt.Errorf("invalid current balance: %w", err)
+		}
+		message, err := balanceUpdate(forfeit, balance, balances, now)
+		if err != nil {
+			return err
+		}
+		update = &message
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if update != nil {
+		s.hub.Publish(userID, *update)
+	}
+	return nil
+}
+
+func (s *UserService) grantBonus(tx *gorm.DB, userID, transactionID uint64, amount float64, now time.Time) error {
+	cfg := config.Get()
+	required := formatAmount(wageringRequirement(amount, cfg.BonusWageringMultiplier))
+
+	bonus := &model.Bonus{
+		UserID:            userID,
+		TransactionID:     transactionID,
+		Amount:            formatAmount(amount),
+		Balance:           formatAmount(amount),
+		WageringRequired:  required,
+		WageringRemaining: required,
+		Status:            model.BonusActive,
+		ExpiresAt:         now.Add(cfg.BonusExpiry),
+	}
+	if err := s.bonusRepo.CreateBonus(tx, bonus); err != nil {
+		return fmt.Errorf("failed to create bonus: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"userID":           userID,
+		"bonusID":          bonus.ID,
+		"amount":           bonus.Amount,
+		"wageringRequired": required,
+		"expiresAt":        bonus.ExpiresAt,
+	}).Info("Bonus granted")
+	return nil
+}
+
+// wagerBonuses counts a game bet towards the wagering requirements of the
+// active bonuses, oldest first, and converts every bonus that completes. What
+// the bet counted towards each bonus is stored, so a rollback can restore it.
+func (s *UserService) wagerBonuses(tx *gorm.DB, userID, transactionID uint64, balances walletBalances, bonuses []model.Bonus, amount float64) error {
+	remaining := make([]float64, len(bonuses))
+	for i, bonus := range bonuses {
+		value, err := parseAmount(bonus.WageringRemaining)
+		if err != nil {
+			return fmt.Errorf("invalid wagering remaining: %w", err)
+		}
+		remaining[i] = value
+	}
+
+	updated := allocateWagering(remaining, amount)
+
+	var completed []*model.Bonus
+	var held []float64
+	stillActive := 0
+	for i := range bonuses {
+		bonus := &bonuses[i]
+		if updated[i] == remaining[i] {
+			stillActive++
+			continue
+		}
+
+		wager := &model.BonusWager{
+			TransactionID: transactionID,
+			BonusID:       bonus.ID,
+			Amount:        formatAmount(roundAmount(remaining[i] - updated[i])),
+		}
+		if err := s.bonusRepo.CreateBonusWager(tx, wager); err != nil {
+			return fmt.Errorf("failed to record bonus wagering: %w", err)
+		}
+
+		bonus.WageringRemaining = formatAmount(updated[i])
+		if updated[i] == 0 {
+			balance, err := parseAmount(bonus.Balance)
+			if err != nil {
+				return fmt.Errorf("invalid bonus balance: %w", err)
+			}
+			bonus.Status = model.BonusCompleted
+			bonus.Balance = formatAmount(0)
+			completed = append(completed, bonus)
+			held = append(held, balance)
+		} else {
+			stillActive++
+		}
+
+		if err := s.bonusRepo.UpdateBonus(tx, bonus); err != nil {
+			return fmt.Errorf("failed to update bonus: %w", err)
+		}
+	}
+
+	for i, bonus := range completed {
+		releaseLocked := stillActive == 0 && i == len(completed)-1
+		deltas := conversionDeltas(balances, held[i], releaseLocked)
+
+		entry := model.WalletEntry{UserID: userID, BonusID: &bonus.ID}
+		if err := s.applyWalletDeltas(tx, entry, balances, deltas, nil); err != nil {
+			return err
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"userID":    userID,
+			"bonusID":   bonus.ID,
+			"converted": deltas[model.WalletCash],
+		}).Info("Bonus wagering completed and converted to cash")
+	}
+
+	return nil
+}
+
+// restoreWagering gives a rolled back bet's wagering back to a bonus. The
+// bet cannot be rolled back once the bonus converted to cash; a forfeited
+// bonus has nothing left to wager, so it is left as it is. Active bonuses are
+// updated in place in bonuses.
+func (s *UserService) restoreWagering(tx *gorm.DB, transactionID uint64, bonuses []model.Bonus) error {
+	wagers, err := s.bonusRepo.GetTransactionBonusWagers(tx, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to get bonus wagering: %w", err)
+	}
+
+	for _, wager := range wagers {
+		bonus := findBonus(bonuses, wager.BonusID)
+		if bonus == nil {
+			if bonus, err = s.bonusRepo.GetBonusForUpdate(tx, wager.BonusID); err != nil {
+				return fmt.Errorf("failed to get bonus: %w", err)
+			}
+		}
+		remaining, err := restoredWagering(*bonus, wager.Amount)
+		if err != nil {
+			return err
+		}
+		if remaining == bonus.WageringRemaining {
+			continue
+		}
+
+		bonus.WageringRemaining = remaining
+		if err := s.bonusRepo.UpdateBonus(tx, bonus); err != nil {
+			return fmt.Errorf("failed to update bonus: %w", err)
+		}
+	}
+	return nil
+}
+
+// findBonus returns the bonus with the given ID from bonuses, or nil.
+func findBonus(bonuses []model.Bonus, bonusID uint64) *model.Bonus {
+	for i := range bonuses {
+		if bonuses[i].ID == bonusID {
+			return &bonuses[i]
+		}
+	}
+	return nil
+}
+
+// bonusForfeit is bonus money a rollback refunded that has to be forfeited
+// again.
+type bonusForfeit struct {
+	BonusID *uint64
+	Amount  float64
+}
+
+// refundBonuses returns what a rolled back bet took from the bonus wallet to
+// the bonuses it was taken from. An active bonus gets its share back. A
+// completed bonus was converted to cash, so its share is refunded to cash. A
+// forfeited bonus's share is refunded to the bonus wallet and returned, to be
+// forfeited again once the reversal is booked, so the bonus wallet never
+// holds money no bonus owns. Bonus money taken before bonuses tracked their
+// share goes to the oldest active bonus, or is forfeited when there is none.
+func (s *UserService) refundBonuses(tx *gorm.DB, reversal *model.Transaction, entries []model.WalletEntry, balances walletBalances, bonuses []model.Bonus) ([]bonusForfeit, error) {
+	var forfeits []bonusForfeit
+	for _, taken := range entries {
+		if taken.WalletType != model.WalletBonus {
+			continue
+		}
+		amount, err := parseAmount(taken.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wallet entry amount: %w", err)
+		}
+		amount = -amount
+		if amount <= 0 {
+			continue
+		}
+
+		entry := model.WalletEntry{UserID: reversal.UserID, TransactionID: &reversal.ID}
+		var bonus *model.Bonus
+		if taken.BonusID != nil {
+			bonus = findBonus(bonuses, *taken.BonusID)
+		} else if len(bonuses) > 0 {
+			bonus = &bonuses[0]
+		}
+		if bonus != nil {
+			held, err := parseAmount(bonus.Balance)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bonus balance: %w", err)
+			}
+			bonus.Balance = formatAmount(roundAmount(held + amount))
+			if err := s.bonusRepo.UpdateBonus(tx, bonus); err != nil {
+				return nil, fmt.Errorf("failed to update bonus: %w", err)
+			}
+			entry.BonusID = &bonus.ID
+			if err := s.applyWalletDeltas(tx, entry, balances, map[string]float64{model.WalletBonus: amount}, nil); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		entry.BonusID = taken.BonusID
+		if taken.BonusID != nil {
+			owner, err := s.bonusRepo.GetBonusForUpdate(tx, *taken.BonusID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get bonus: %w", err)
+			}
+			if owner.Status == model.BonusCompleted {
+				if err := s.applyWalletDeltas(tx, entry, balances, map[string]float64{model.WalletCash: amount}, nil); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+
+		if err := s.applyWalletDeltas(tx, entry, balances, map[string]float64{model.WalletBonus: amount}, nil); err != nil {
+			return nil, err
+		}
+		forfeits = append(forfeits, bonusForfeit{BonusID: taken.BonusID, Amount: amount})
+	}
+	return forfeits, nil
+}
+
+// forfeitRefund forfeits bonus money a rollback refunded to a bonus that is
+// no longer active, booked as a server "lose" like the original forfeit, and
+// returns the user's total balance after it.
+func (s *UserService) forfeitRefund(tx *gorm.DB, reversal *model.Transaction, forfeit bonusForfeit, balance float64, balances walletBalances, now time.Time) (float64, error) {
+	bonusID, amount := forfeit.BonusID, forfeit.Amount
+	transactionID := reversal.TransactionID + ":bonus-forfeit"
+	if bonusID != nil {
+		transactionID = fmt.Sprintf("%s-%d", transactionID, *bonusID)
+	}
+
+	remaining := roundAmount(balance - amount)
+	transaction := &model.Transaction{
+		UserID:        reversal.UserID,
+		TransactionID: transactionID,
+		Amount:        formatAmount(amount),
+		State:         "lose",
+		SourceType:    "server",
+	}
+	setBalances(transaction, balance, remaining)
+	if err := s.createTransaction(tx, transaction); err != nil {
+		return 0, fmt.Errorf("failed to create forfeit transaction: %w", err)
+	}
+
+	entry := model.WalletEntry{UserID: reversal.UserID, TransactionID: &transaction.ID, BonusID: bonusID}
+	if err := s.applyWalletDeltas(tx, entry, balances, map[string]float64{model.WalletBonus: -amount}, nil); err != nil {
+		return 0, err
+	}
+	if err := s.recordBalanceChange(tx, transaction, balance, remaining, balances, now); err != nil {
+		return 0, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"userID":        reversal.UserID,
+		"transactionID": transactionID,
+		"forfeited":     amount,
+	}).Info("Refunded bonus money forfeited")
+	return remaining, nil
+}
+
+// restoredWagering returns the bonus's wagering remaining with a bet's
+// wagering added back, capped at the requirement.
+func restoredWagering(bonus model.Bonus, wagered string) (string, error) {
+	switch bonus.Status {
+	case model.BonusCompleted:
+		return "", errors.New("bonus already converted")
+	case model.BonusForfeited:
+		return bonus.WageringRemaining, nil
+	}
+
+	remaining, err := parseAmount(bonus.WageringRemaining)
+	if err != nil {
+		return "", fmt.Errorf("invalid wagering remaining: %w", err)
+	}
+	required, err := parseAmount(bonus.WageringRequired)
+	if err != nil {
+		return "", fmt.Errorf("invalid wagering required: %w", err)
+	}
+	amount, err := parseAmount(wagered)
+	if err != nil {
+		return "", fmt.Errorf("invalid bonus wagering: %w", err)
+	}
+	return formatAmount(math.Min(roundAmount(remaining+amount), required)), nil
+}
+
+func wageringRequirement(amount float64, multiplier int) float64 {
+	return roundAmount(amount * float64(multiplier))
+}
+
+// allocateWagering applies a bet to the outstanding wagering requirements in
+// order. Turnover left over after a requirement is met carries into the next.
+func allocateWagering(remaining []float64, amount float64) []float64 {
+	updated := make([]float64, len(remaining))
+	copy(updated, remaining)
+
+	left := amount
+	for i := range updated {
+		if left <= 0 {
+			break
+		}
+		applied := math.Min(updated[i], left)
+		updated[i] = roundAmount(updated[i] - applied)
+		left = roundAmount(left - applied)
+	}
+
+	return updated
+}
+
+// splitBonusDebit takes a debit of the bonus wallet from the bonuses holding
+// it, oldest first, and returns how much it takes from each. Whatever no bonus
+// holds, such as bonus money from before bonuses tracked their share, is
+// returned as unowned.
+func splitBonusDebit(bonuses []model.Bonus, amount float64) ([]float64, float64, error) {
+	taken := make([]float64, len(bonuses))
+	left := amount
+	for i, bonus := range bonuses {
+		if left <= 0 {
+			break
+		}
+		held, err := parseAmount(bonus.Balance)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid bonus balance: %w", err)
+		}
+		taken[i] = math.Min(held, left)
+		left = roundAmount(left - taken[i])
+	}
+	return taken, left, nil
+}
+
+// conversionDeltas moves what a completed bonus still holds from the bonus
+// wallet into cash.
+// Locked winnings are released along with the last active bonus.
+func conversionDeltas(balances walletBalances, held float64, releaseLocked bool) map[string]float64 {
+	deltas := map[string]float64{}
+
+	converted := math.Min(held, balances[model.WalletBonus])
+	if converted > 0 {
+		deltas[model.WalletBonus] = -converted
+	}
+	if releaseLocked && balances[model.WalletLocked] > 0 {
+		deltas[model.WalletLocked] = -balances[model.WalletLocked]
+		converted += balances[model.WalletLocked]
+	}
+	if converted > 0 {
+		deltas[model.WalletCash] = roundAmount(converted)
+	}
+
+	return deltas
+}
+
+// forfeitDeltas removes what an expired bonus still holds from the bonus
+// wallet. Locked winnings are forfeited along with the last active bonus.
+func forfeitDeltas(balances walletBalances, held float64, forfeitLocked bool) map[string]float64 {
+	deltas := map[string]float64{}
+
+	if forfeited := math.Min(held, balances[model.WalletBonus]); forfeited > 0 {
+		deltas[model.WalletBonus] = -forfeited
+	}
+	if forfeitLocked && balances[model.WalletLocked] > 0 {
+		deltas[model.WalletLocked] = -balances[model.WalletLocked]
+	}
+
+	return deltas
+}
+
+func bonusProgress(bonus model.Bonus) (dto.BonusProgress, error) {
+	required, err := parseAmount(bonus.WageringRequired)
+	if err != nil {
+		return dto.BonusProgress{}, err
+	}
+	remaining, err := parseAmount(bonus.WageringRemaining)
+	if err != nil {
+		return dto.BonusProgress{}, err
+	}
+
+	wagered := roundAmount(required - remaining)
+	progress := 100.0
+	if required > 0 {
+		progress = roundAmount(wagered / required * 100)
+	}
+
+	return dto.BonusProgress{
+		ID:                bonus.ID,
+		Amount:            bonus.Amount,
+		WageringRequired:  bonus.WageringRequired,
+		Wagered:           formatAmount(wagered),
+		WageringRemaining: bonus.WageringRemaining,
+		Progress:          progress,
+		Status:            bonus.Status,
+		ExpiresAt:         bonus.ExpiresAt,
+		CreatedAt:         bonus.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWageringRequirement(t *testing.T) {
+	assert.Equal(t, 300.0, wageringRequirement(10, 30))
+	assert.Equal(t, 31.5, wageringRequirement(1.05, 30))
+	assert.Equal(t, 0.0, wageringRequirement(10, 0))
+}
+
+func TestAllocateWagering(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining []float64
+		amount    float64
+		want      []float64
+	}{
+		{
+			name:      "bet reduces oldest requirement",
+			remaining: []float64{300, 150},
+			amount:    25.5,
+			want:      []float64{274.5, 150},
+		},
+		{
+			name:      "bet completes requirement and carries over",
+			remaining: []float64{20, 150},
+			amount:    50,
+			want:      []float64{0, 120},
+		},
+		{
+			name:      "bet larger than all requirements",
+			remaining: []float64{20, 10},
+			amount:    100,
+			want:      []float64{0, 0},
+		},
+		{
+			name:      "no active bonuses",
+			remaining: []float64{},
+			amount:    10,
+			want:      []float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allocateWagering(tt.remaining, tt.amount)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConversionDeltas(t *testing.T) {
+	tests := []struct {
+		name          string
+		balances      walletBalances
+		held          float64
+		releaseLocked bool
+		want          map[string]float64
+	}{
+		{
+			name:     "converts what the bonus holds to cash",
+			balances: walletBalances{model.WalletBonus: 50, model.WalletLocked: 20},
+			held:     30,
+			want:     map[string]float64{model.WalletBonus: -30, model.WalletCash: 30},
+		},
+		{
+			name:     "converts only what is left in the bonus wallet",
+			balances: walletBalances{model.WalletBonus: 12.5},
+			held:     30,
+			want:     map[string]float64{model.WalletBonus: -12.5, model.WalletCash: 12.5},
+		},
+		{
+			name:          "releases locked winnings with the last bonus",
+			balances:      walletBalances{model.WalletBonus: 10, model.WalletLocked: 40},
+			held:          10,
+			releaseLocked: true,
+			want:          map[string]float64{model.WalletBonus: -10, model.WalletLocked: -40, model.WalletCash: 50},
+		},
+		{
+			name:          "nothing to convert",
+			balances:      walletBalances{},
+			held:          10,
+			releaseLocked: true,
+			want:          map[string]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, conversionDeltas(tt.balances, tt.held, tt.releaseLocked))
+		})
+	}
+}
+
+func TestForfeitDeltas(t *testing.T) {
+	tests := []struct {
+		name          string
+		balances      walletBalances
+		held          float64
+		forfeitLocked bool
+		want          map[string]float64
+	}{
+		{
+			name:     "forfeits only what the bonus holds",
+			balances: walletBalances{model.WalletBonus: 50, model.WalletLocked: 20},
+			held:     30,
+			want:     map[string]float64{model.WalletBonus: -30},
+		},
+		{
+			name:          "forfeits locked winnings with the last bonus",
+			balances:      walletBalances{model.WalletBonus: 5, model.WalletLocked: 20},
+			held:          30,
+			forfeitLocked: true,
+			want:          map[string]float64{model.WalletBonus: -5, model.WalletLocked: -20},
+		},
+		{
+			name:          "nothing to forfeit",
+			balances:      walletBalances{model.WalletCash: 100},
+			held:          30,
+			forfeitLocked: true,
+			want:          map[string]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, forfeitDeltas(tt.balances, tt.held, tt.forfeitLocked))
+		})
+	}
+}
+
+func TestSplitBonusDebit(t *testing.T) {
+	tests := []struct {
+		name        string
+		balances    []string
+		amount      float64
+		wantTaken   []float64
+		wantUnowned float64
+	}{
+		{
+			name:      "takes from the oldest bonus first",
+			balances:  []string{"20.00", "30.00"},
+			amount:    5,
+			wantTaken: []float64{5, 0},
+		},
+		{
+			name:      "carries into the next bonus",
+			balances:  []string{"20.00", "30.00"},
+			amount:    25.5,
+			wantTaken: []float64{20, 5.5},
+		},
+		{
+			name:      "skips spent bonuses",
+			balances:  []string{"0.00", "30.00"},
+			amount:    10,
+			wantTaken: []float64{0, 10},
+		},
+		{
+			name:        "money no bonus holds",
+			balances:    []string{"5.00"},
+			amount:      8,
+			wantTaken:   []float64{5},
+			wantUnowned: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bonuses := make([]model.Bonus, len(tt.balances))
+			for i, balance := range tt.balances {
+				bonuses[i] = model.Bonus{ID: uint64(i + 1), Balance: balance}
+			}
+
+			taken, unowned, err := splitBonusDebit(bonuses, tt.amount)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantTaken, taken)
+			assert.Equal(t, tt.wantUnowned, unowned)
+		})
+	}
+}
+
+func TestBonusProgress(t *testing.T) {
+	got, err := bonusProgress(model.Bonus{
+		ID:                7,
+		Amount:            "10.00",
+		WageringRequired:  "300.00",
+		WageringRemaining: "225.00",
+		Status:            model.BonusActive,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), got.ID)
+	assert.Equal(t, "75.00", got.Wagered)
+	assert.Equal(t, 25.0, got.Progress)
+
+	got, err = bonusProgress(model.Bonus{WageringRequired: "0.00", WageringRemaining: "0.00"})
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, got.Progress)
+
+	_, err = bonusProgress(model.Bonus{WageringRequired: "abc", WageringRemaining: "0.00"})
+	assert.Error(t, err)
+}
+
+func TestRestoredWagering(t *testing.T) {
+	tests := []struct {
+		name    string
+		bonus   model.Bonus
+		wagered string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "gives the wagering back",
+			bonus:   model.Bonus{Status: model.BonusActive, WageringRequired: "300.00", WageringRemaining: "250.00"},
+			wagered: "25.50",
+			want:    "275.50",
+		},
+		{
+			name:    "capped at the requirement",
+			bonus:   model.Bonus{Status: model.BonusActive, WageringRequired: "300.00", WageringRemaining: "290.00"},
+			wagered: "25.00",
+			want:    "300.00",
+		},
+		{
+			name:    "forfeited bonus is left as it is",
+			bonus:   model.Bonus{Status: model.BonusForfeited, WageringRequired: "300.00", WageringRemaining: "120.00"},
+			wagered: "25.00",
+			want:    "120.00",
+		},
+		{
+			name:    "converted bonus blocks the rollback",
+			bonus:   model.Bonus{Status: model.BonusCompleted, WageringRequired: "300.00", WageringRemaining: "0.00"},
+			wagered: "25.00",
+			wantErr: "bonus already converted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := restoredWagering(tt.bonus, tt.wagered)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
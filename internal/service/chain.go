@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/hashchain"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+// errChainBroken stops the walk over a chain at its first broken link.
+var errChainBroken = errors.New("chain broken")
+
+type ChainService struct {
+	chainRepo database.ChainRepository
+}
+
+func NewChainService() *ChainService {
+	return &ChainService{
+		chainRepo: database.NewChainRepository(),
+	}
+}
+
+// VerifyUser walks the user's hash chain from the first transaction and
+// reports the first link that does not verify.
+func (s *ChainService) VerifyUser(userID uint64) (*dto.ChainVerificationResponse, error) {
+	logrus.WithField("userID", userID).Info("Verifying transaction chain")
+
+	exists, err := s.chainRepo.UserExists(userID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to get user")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !exists {
+		return nil, errors.New("user not found")
+	}
+
+	verifier := &hashchain.Verifier{}
+	var broken *hashchain.Break
+	err = s.chainRepo.EachChainTransaction(userID, func(transaction model.Transaction) error {
+		linkBreak, err := verifier.Check(transaction)
+		if err != nil {
+			return err
+		}
+		if linkBreak != nil {
+			broken = linkBreak
+			return errChainBroken
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errChainBroken) {
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to verify transaction chain")
+		return nil, fmt.Errorf("failed to verify chain: %w", err)
+	}
+
+	response := &dto.ChainVerificationResponse{
+		UserID:   userID,
+		Intact:   broken == nil,
+		Verified: verifier.Checked(),
+	}
+	if broken != nil {
+		response.BrokenLink = &dto.ChainBreak{
+			ID:            broken.Transaction.ID,
+			TransactionID: broken.Transaction.TransactionID,
+			Reason:        broken.Reason,
+			ExpectedHash:  broken.Expected,
+			ActualHash:    broken.Actual,
+		}
+		logrus.WithFields(logrus.Fields{
+			"userID":        userID,
+			"transactionID": broken.Transaction.TransactionID,
+			"reason":        broken.Reason,
+		}).Warn("Transaction chain broken")
+	}
+
+	return response, nil
+}
+
+// ChainUserIDs returns every user that has a chain to verify.
+func (s *ChainService) ChainUserIDs() ([]uint64, error) {
+	return s.chainRepo.GetChainUserIDs()
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ClosingService struct {
+	closingRepo database.ClosingRepository
+}
+
+func NewClosingService() *ClosingService {
+	return &ClosingService{
+		closingRepo: database.NewClosingRepository(),
+	}
+}
+
+// GetClosingReport returns the closing of a business day: the totals per
+// source type and state, the sums of all user balances and one page of the
+// per-user balances.
+func (s *ClosingService) GetClosingReport(businessDate time.Time, page, pageSize int) (*dto.ClosingReportResponse, error) {
+	date := businessDate.Format("2006-01-02")
+	logrus.WithFields(logrus.Fields{"businessDate": date, "page": page, "pageSize": pageSize}).Info("Getting closing report")
+
+	closing, err := s.closingRepo.GetClosing(businessDate)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("closing not found")
+		}
+		logrus.WithFields(logrus.Fields{"businessDate": date, "error": err}).Error("Failed to get closing")
+		return nil, fmt.Errorf("failed to get closing: %w", err)
+	}
+
+	totals, err := s.closingRepo.GetClosingTotals(closing.ID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"businessDate": date, "error": err}).Error("Failed to get closing totals")
+		return nil, fmt.Errorf("failed to get closing totals: %w", err)
+	}
+
+	summary, err := s.closingRepo.SummarizeClosingBalances(closing.ID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"businessDate": date, "error": err}).Error("Failed to summarize closing balances")
+		return nil, fmt.Errorf("failed to summarize closing balances: %w", err)
+	}
+
+	balances, err := s.closingRepo.GetClosingBalances(closing.ID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"businessDate": date, "error": err}).Error("Failed to get closing balances")
+		return nil, fmt.Errorf("failed to get closing balances: %w", err)
+	}
+
+	return closingReport(closing, totals, summary, balances, page, pageSize)
+}
+
+func closingReport(closing *model.DailyClosing, totals []model.DailyClosingTotal, summary *database.ClosingBalanceSummary, balances []model.DailyClosingBalance, page, pageSize int) (*dto.ClosingReportResponse, error) {
+	opening, err := parseAmount(summary.OpeningBalance)
+	if err != nil {
+		return nil, fmt.Errorf("invalid opening balance sum: %w", err)
+	}
+	closingBalance, err := parseAmount(summary.ClosingBalance)
+	if err != nil {
+		return nil, fmt.Errorf("invalid closing balance sum: %w", err)
+	}
+
+	response := &dto.ClosingReportResponse{
+		BusinessDate:   closing.BusinessDate.Format("2006-01-02"),
+		TimeZone:       closing.TimeZone,
+		PeriodStart:    closing.PeriodStart,
+		PeriodEnd:      closing.PeriodEnd,
+		ClosedAt:       closing.ClosedAt,
+		Users:          summary.Users,
+		OpeningBalance: formatAmount(opening),
+		ClosingBalance: formatAmount(closingBalance),
+		Totals:         make([]dto.ClosingTotal, 0, len(totals)),
+		Balances:       make([]dto.ClosingBalance, 0, len(balances)),
+		Page:           page,
+		PageSize:       pageSize,
+	}
+
+	for _, total := range totals {
+		amount, err := parseAmount(total.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid closing total: %w", err)
+		}
+		response.Totals = append(response.Totals, dto.ClosingTotal{
+			SourceType:   total.SourceType,
+			State:        total.State,
+			Transactions: total.Transactions,
+			Amount:       formatAmount(amount),
+		})
+	}
+
+	for _, balance := range balances {
+		opening, err := parseAmount(balance.OpeningBalance)
+		if err != nil {
+			return nil, fmt.Errorf("invalid opening balance: %w", err)
+		}
+		closingBalance, err := parseAmount(balance.ClosingBalance)
+		if err != nil {
+			return nil, fmt.Errorf("invalid closing balance: %w", err)
+		}
+		response.Balances = append(response.Balances, dto.ClosingBalance{
+			UserID:         balance.UserID,
+			OpeningBalance: formatAmount(opening),
+			ClosingBalance: formatAmount(closingBalance),
+		})
+	}
+
+	return response, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClosingReport(t *testing.T) {
+	closing := &model.DailyClosing{
+		ID:           3,
+		BusinessDate: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC),
+		TimeZone:     "Europe/Riga",
+		PeriodStart:  time.Date(2025, 6, 29, 21, 0, 0, 0, time.UTC),
+		PeriodEnd:    time.Date(2025, 6, 30, 21, 0, 0, 0, time.UTC),
+		ClosedAt:     time.Date(2025, 6, 30, 21, 1, 0, 0, time.UTC),
+	}
+	totals := []model.DailyClosingTotal{
+		{ClosingID: 3, SourceType: "game", State: "lose", Transactions: 4, Amount: "30"},
+		{ClosingID: 3, SourceType: "payment", State: "win", Transactions: 1, Amount: "100.5"},
+	}
+	summary := &database.ClosingBalanceSummary{Users: 2, OpeningBalance: "10", ClosingBalance: "80.50"}
+	balances := []model.DailyClosingBalance{
+		{ClosingID: 3, UserID: 1, OpeningBalance: "10.00", ClosingBalance: "80.5"},
+		{ClosingID: 3, UserID: 2, OpeningBalance: "0", ClosingBalance: "0"},
+	}
+
+	report, err := closingReport(closing, totals, summary, balances, 1, 50)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025-06-30", report.BusinessDate)
+	assert.Equal(t, "Europe/Riga", report.TimeZone)
+	assert.Equal(t, int64(2), report.Users)
+	assert.Equal(t, "10.00", report.OpeningBalance)
+	assert.Equal(t, "80.50", report.ClosingBalance)
+	require.Len(t, report.Totals, 2)
+	assert.Equal(t, "30.00", report.Totals[0].Amount)
+	assert.Equal(t, int64(4), report.Totals[0].Transactions)
+	assert.Equal(t, "100.50", report.Totals[1].Amount)
+	require.Len(t, report.Balances, 2)
+	assert.Equal(t, "80.50", report.Balances[0].ClosingBalance)
+	assert.Equal(t, "0.00", report.Balances[1].OpeningBalance)
+}
+
+func TestClosingReportWithoutActivity(t *testing.T) {
+	closing := &model.DailyClosing{BusinessDate: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)}
+	summary := &database.ClosingBalanceSummary{OpeningBalance: "0", ClosingBalance: "0"}
+
+	report, err := closingReport(closing, nil, summary, nil, 2, 50)
+	require.NoError(t, err)
+	assert.NotNil(t, report.Totals)
+	assert.NotNil(t, report.Balances)
+	assert.Equal(t, 2, report.Page)
+}
+
+func TestClosingReportRejectsInvalidAmounts(t *testing.T) {
+	closing := &model.DailyClosing{}
+	summary := &database.ClosingBalanceSummary{OpeningBalance: "abc", ClosingBalance: "0"}
+
+	_, err := closingReport(closing, nil, summary, nil, 1, 50)
+	assert.Error(t, err)
+}
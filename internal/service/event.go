@@ -0,0 +1,190 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	EventBalanceChanged               = "balance.changed"
+	EventTransactionProcessed         = "transaction.processed"
+	EventTransactionRolledBack        = "transaction.rolled_back"
+	EventTransactionInsufficientFunds = "transaction.insufficient_balance"
+	EventTransactionHeld              = "transaction.held"
+	EventTransactionDeclined          = "transaction.declined"
+)
+
+var EventTypes = []string{
+	EventBalanceChanged,
+	EventTransactionProcessed,
+	EventTransactionRolledBack,
+	EventTransactionInsufficientFunds,
+	EventTransactionHeld,
+	EventTransactionDeclined,
+}
+
+// rollbackReasons lists the rejections that are reported to subscribers as
+// rolled-back transactions. Duplicates and unknown users are not reported.
+var rollbackReasons = map[string]bool{
+	"loss limit exceeded":                 true,
+	"deposit limit exceeded":              true,
+	"account suspended":                   true,
+	"account self-excluded":               true,
+	"account closed":                      true,
+	"round has no bet":                    true,
+	"round closed":                        true,
+	"round mismatch":                      true,
+	"round requires game source":          true,
+	"transaction rejected by rule":        true,
+	"transaction rejected by fraud check": true,
+}
+
+// recordBalanceChange writes a balance-changed event to the outbox in the same
+// database transaction as the balance update, so the event exists exactly
+// when the change is committed.
+func (s *UserService) recordBalanceChange(tx *gorm.DB, transaction *model.Transaction, previousBalance, balance float64, balances walletBalances, now time.Time) error {
+	payload, err := json.Marshal(dto.BalanceChangedEvent{
+		UserID:          transaction.UserID,
+		TransactionID:   transaction.TransactionID,
+		State:           transaction.State,
+		Amount:          transaction.Amount,
+		SourceType:      transaction.SourceType,
+		PreviousBalance: formatAmount(previousBalance),
+		Balance:         formatAmount(balance),
+		Wallets:         walletBalancesResponse(balances),
+		OccurredAt:      now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode balance event: %w", err)
+	}
+
+	event := &model.OutboxEvent{
+		AggregateID: transaction.UserID,
+		EventType:   EventBalanceChanged,
+		DedupeKey:   EventBalanceChanged + ":" + transaction.TransactionID,
+		Payload:     string(payload),
+		AvailableAt: now,
+	}
+	if err := s.outboxRepo.CreateEvent(tx, event); err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
+
+func (s *UserService) recordTransactionProcessed(tx *gorm.DB, transaction *model.Transaction, balance float64, now time.Time) error {
+	event, err := transactionEvent(EventTransactionProcessed, transaction.TransactionID, dto.TransactionEvent{
+		UserID:        transaction.UserID,
+		TransactionID: transaction.TransactionID,
+		State:         transaction.State,
+		Amount:        transaction.Amount,
+		SourceType:    transaction.SourceType,
+		Balance:       formatAmount(balance),
+		OccurredAt:    now,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.outboxRepo.CreateEvent(tx, event); err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
+
+// recordTransactionFailure reports a rejected transaction once its database
+// transaction has rolled back. It is best effort: the rejection itself has
+// already been decided, so a failure to write the event is only logged.
+func (s *UserService) recordTransactionFailure(userID uint64, req dto.TransactionRequest, sourceType string, cause error) {
+	eventType := EventTransactionRolledBack
+	reason := "internal_error"
+	switch {
+	case cause.Error() == "insufficient balance":
+		eventType = EventTransactionInsufficientFunds
+		reason = "insufficient_balance"
+	case rollbackReasons[cause.Error()]:
+		reason = strings.NewReplacer(" ", "_", "-", "_").Replace(cause.Error())
+	case errors.Is(cause, errVersionConflict):
+		reason = "concurrent_update"
+	case errors.Is(cause, ErrRetriesExhausted):
+		reason = "retry_exhausted"
+	case cause.Error() == "transaction already processed" || cause.Error() == "user not found":
+		return
+	}
+
+	now := time.Now().UTC()
+	event, err := transactionEvent(eventType, fmt.Sprintf("%s:%d", req.TransactionID, now.UnixNano()), dto.TransactionEvent{
+		UserID:        userID,
+		TransactionID: req.TransactionID,
+		State:         req.State,
+		Amount:        req.Amount,
+		SourceType:    sourceType,
+		Reason:        reason,
+		OccurredAt:    now,
+	})
+	if err == nil {
+		err = s.outboxRepo.CreateEvent(s.userRepo.GetDB(), event)
+	}
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"userID":        userID,
+			"transactionID": req.TransactionID,
+			"eventType":     eventType,
+			"error":         err,
+		}).Error("Failed to record transaction failure event")
+	}
+}
+
+// recordReviewEvent tells subscribers that a transaction was held for review
+// or declined by it. An approved transaction is reported like any other
+// booking.
+func recordReviewEvent(tx *gorm.DB, outboxRepo database.OutboxRepository, eventType string, review *model.TransactionReview, reason string, now time.Time) error {
+	event, err := transactionEvent(eventType, review.TransactionID, dto.TransactionEvent{
+		UserID:        review.UserID,
+		TransactionID: review.TransactionID,
+		State:         review.State,
+		Amount:        review.Amount,
+		SourceType:    review.SourceType,
+		Reason:        reason,
+		OccurredAt:    now,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := outboxRepo.CreateEvent(tx, event); err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
+
+func transactionEvent(eventType, key string, payload dto.TransactionEvent) (*model.OutboxEvent, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction event: %w", err)
+	}
+
+	return &model.OutboxEvent{
+		AggregateID: payload.UserID,
+		EventType:   eventType,
+		DedupeKey:   eventType + ":" + key,
+		Payload:     string(encoded),
+		AvailableAt: payload.OccurredAt,
+	}, nil
+}
+
+func walletBalancesResponse(balances walletBalances) dto.WalletBalances {
+	return dto.WalletBalances{
+		Cash:   formatAmount(balances[model.WalletCash]),
+		Bonus:  formatAmount(balances[model.WalletBonus]),
+		Locked: formatAmount(balances[model.WalletLocked]),
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/fraud"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+)
+
+// FraudError is returned when a fraud check rejects a win.
+type FraudError struct {
+	Hit fraud.Hit
+}
+
+func (e *FraudError) Error() string {
+	return "transaction rejected by fraud check"
+}
+
+// fraudStore answers the fraud checks' history questions inside the booking
+// transaction.
+type fraudStore struct {
+	tx   *gorm.DB
+	repo database.FraudRepository
+}
+
+func (s fraudStore) Wins(userID uint64, sources []string, since time.Time) (fraud.Wins, error) {
+	count, sum, err := s.repo.CountWins(s.tx, userID, sources, since)
+	if err != nil {
+		return fraud.Wins{}, err
+	}
+	amount, err := parseAmount(sum)
+	if err != nil {
+		return fraud.Wins{}, fmt.Errorf("invalid win sum: %w", err)
+	}
+	return fraud.Wins{Count: count, Sum: amount}, nil
+}
+
+// checkFraud runs the fraud checks for a win. It returns the hit that holds
+// the win for review, if any, and the hits that only flag it. Losses and
+// adjustments, which a second operator has approved already, are not checked.
+func (s *UserService) checkFraud(tx *gorm.DB, userID uint64, sourceType, state string, amount float64, now time.Time) (*fraud.Hit, []fraud.Hit, error) {
+	if state != "win" || sourceType == "adjustment" {
+		return nil, nil, nil
+	}
+
+	hits, err := s.fraudChecks.Evaluate(fraudStore{tx: tx, repo: s.fraudRepo}, fraud.Input{
+		UserID:     userID,
+		SourceType: sourceType,
+		Amount:     amount,
+		At:         now,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return fraudDecision(hits)
+}
+
+func fraudDecision(hits []fraud.Hit) (*fraud.Hit, []fraud.Hit, error) {
+	strongest := fraud.Strongest(hits)
+	if strongest == nil {
+		return nil, nil, nil
+	}
+
+	switch strongest.Action {
+	case fraud.ActionReject:
+		return nil, nil, &FraudError{Hit: *strongest}
+	case fraud.ActionHold:
+		return strongest, nil, nil
+	default:
+		return nil, hits, nil
+	}
+}
+
+func fraudFlags(hits []fraud.Hit) []model.TransactionFlag {
+	flags := make([]model.TransactionFlag, 0, len(hits))
+	for _, hit := range hits {
+		flags = append(flags, model.TransactionFlag{RuleID: hit.CheckID, Message: hit.Reason})
+	}
+	return flags
+}
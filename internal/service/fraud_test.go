@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/lielamurs/balance-transactions/internal/fraud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFraudDecision(t *testing.T) {
+	flag := fraud.Hit{CheckID: "volume", Action: fraud.ActionFlag, Reason: "wins of 1200.00 within 24h0m0s, at most 1000.00 allowed"}
+	hold := fraud.Hit{CheckID: "burst", Action: fraud.ActionHold, Reason: "6 wins within 1m0s, at most 5 allowed"}
+	reject := fraud.Hit{CheckID: "source", Action: fraud.ActionReject}
+
+	t.Run("no hits", func(t *testing.T) {
+		held, flags, err := fraudDecision(nil)
+		require.NoError(t, err)
+		assert.Nil(t, held)
+		assert.Empty(t, flags)
+	})
+
+	t.Run("flags are booked", func(t *testing.T) {
+		held, flags, err := fraudDecision([]fraud.Hit{flag})
+		require.NoError(t, err)
+		assert.Nil(t, held)
+		assert.Equal(t, []fraud.Hit{flag}, flags)
+	})
+
+	t.Run("hold wins over flags", func(t *testing.T) {
+		held, flags, err := fraudDecision([]fraud.Hit{flag, hold})
+		require.NoError(t, err)
+		assert.Equal(t, &hold, held)
+		assert.Empty(t, flags)
+	})
+
+	t.Run("reject wins over hold", func(t *testing.T) {
+		_, _, err := fraudDecision([]fraud.Hit{hold, reject})
+		require.EqualError(t, err, "transaction rejected by fraud check")
+		var fraudErr *FraudError
+		require.ErrorAs(t, err, &fraudErr)
+		assert.Equal(t, "source", fraudErr.Hit.CheckID)
+	})
+}
+
+func TestFraudFlags(t *testing.T) {
+	flags := fraudFlags([]fraud.Hit{{CheckID: "volume", Action: fraud.ActionFlag, Reason: "too much"}})
+	require.Len(t, flags, 1)
+	assert.Equal(t, "volume", flags[0].RuleID)
+	assert.Equal(t, "too much", flags[0].Message)
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/config"
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var limitWindows = map[string]time.Duration{
+	model.PeriodDaily:   24 * time.Hour,
+	model.PeriodWeekly:  7 * 24 * time.Hour,
+	model.PeriodMonthly: 30 * 24 * time.Hour,
+}
+
+type LimitService struct {
+	userRepo  database.UserRepository
+	limitRepo database.LimitRepository
+	auditRepo database.AuditRepository
+}
+
+func NewLimitService() *LimitService {
+	return &LimitService{
+		userRepo:  database.NewUserRepository(),
+		limitRepo: database.NewLimitRepository(),
+		auditRepo: database.NewAuditRepository(),
+	}
+}
+
+func (s *LimitService) GetLimits(userID uint64) (*dto.LimitsResponse, error) {
+	logrus.WithField("userID", userID).Info("Getting user limits")
+
+	if err := s.ensureUser(userID); err != nil {
+		return nil, err
+	}
+
+	limits, err := s.limitRepo.GetLimits(userID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to get limits")
+		return nil, fmt.Errorf("failed to get limits: %w", err)
+	}
+
+	now := time.Now().UTC()
+	response := &dto.LimitsResponse{
+		UserID: userID,
+		Limits: make([]dto.LimitResponse, 0, len(limits)),
+	}
+	for _, limit := range limits {
+		resolved, _, removed := resolveLimit(limit, now)
+		if removed {
+			continue
+		}
+		response.Limits = append(response.Limits, limitResponse(resolved))
+	}
+
+	return response, nil
+}
+
+// SetLimit applies a lower or new limit immediately. Raising a limit only
+// takes effect after the configured cooling-off period. The actor is the
+// operator setting the limit on the user's behalf, or has no ID when the user
+// sets it; operator changes are audited.
+func (s *LimitService) SetLimit(userID uint64, limitType, period, amount string, actor Actor) (*dto.LimitResponse, error) {
+	setBy := limitSetBy(actor)
+	logrus.WithFields(logrus.Fields{
+		"userID":    userID,
+		"limitType": limitType,
+		"period":    period,
+		"amount":    amount,
+		"setBy":     setBy,
+	}).Info("Setting user limit")
+
+	value, err := parseAmount(amount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid limit amount: %w", err)
+	}
+
+	return s.changeLimit(userID, limitType, period, &value, actor, model.AuditLimitSet)
+}
+
+// RemoveLimit schedules a limit for removal once the cooling-off period is over.
+func (s *LimitService) RemoveLimit(userID uint64, limitType, period string, actor Actor) (*dto.LimitResponse, error) {
+	setBy := limitSetBy(actor)
+	logrus.WithFields(logrus.Fields{
+		"userID":    userID,
+		"limitType": limitType,
+		"period":    period,
+		"setBy":     setBy,
+	}).Info("Removing user limit")
+
+	return s.changeLimit(userID, limitType, period, nil, actor, model.AuditLimitRemove)
+}
+
+func (s *LimitService) changeLimit(userID uint64, limitType, period string, amount *float64, actor Actor, action string) (*dto.LimitResponse, error) {
+	if err := s.ensureUser(userID); err != nil {
+		return nil, err
+	}
+
+	var response dto.LimitResponse
+	err := s.limitRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+
+		existing, err := s.limitRepo.GetLimitForUpdate(tx, userID, limitType, period)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("failed to get limit: %w", err)
+			}
+			existing = nil
+		}
+
+		var before interface{}
+		if existing != nil {
+			before = limitResponse(*existing)
+		}
+
+		if existing != nil {
+			resolved, changed, removed := resolveLimit(*existing, now)
+			if removed {
+				if err := s.limitRepo.DeleteLimit(tx, existing); err != nil {
+					return fmt.Errorf("failed to remove limit: %w", err)
+				}
+				existing = nil
+			} else if changed {
+				existing = &resolved
+			}
+		}
+
+		limit, immediate, err := planLimitChange(existing, amount, now, config.Get().LimitCoolingOff)
+		if err != nil {
+			return err
+		}
+		limit.UserID = userID
+		limit.LimitType = limitType
+		limit.Period = period
+		limit.SetBy = limitSetBy(actor)
+
+		if err := s.limitRepo.SaveLimit(tx, limit); err != nil {
+			return fmt.Errorf("failed to save limit: %w", err)
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"userID":    userID,
+			"limitType": limitType,
+			"period":    period,
+			"immediate": immediate,
+		}).Info("User limit changed")
+
+		response = limitResponse(*limit)
+		if actor.ID == "" {
+			return nil
+		}
+		return recordAudit(tx, s.auditRepo, actor, AuditChange{
+			Action:       action,
+			TargetUserID: &userID,
+			Resource:     fmt.Sprintf("user_limits/%d", limit.ID),
+			Before:       before,
+			After:        response,
+		})
+	})
+	if err != nil {
+		if err.Error() != "limit not found" {
+			logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to change limit")
+		}
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// limitSetBy records whether a limit was set by the user or on their behalf
+// by an operator.
+func limitSetBy(actor Actor) string {
+	if actor.ID != "" {
+		return "operator:" + actor.ID
+	}
+	return "user"
+}
+
+func (s *LimitService) ensureUser(userID uint64) error {
+	if _, err := s.userRepo.GetUser(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.WithField("userID", userID).Warn("User not found")
+			return errors.New("user not found")
+		}
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to get user")
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	return nil
+}
+
+// checkLimits rejects game losses and payment deposits that would push the
+// user past any of their limits over the limit's rolling window.
+func (s *UserService) checkLimits(tx *gorm.DB, userID uint64, state, sourceType string, amount float64, now time.Time) error {
+	limitType := limitTypeFor(state, sourceType)
+	if limitType == "" {
+		return nil
+	}
+
+	limits, err := s.limitRepo.GetLimitsForUpdate(tx, userID, limitType)
+	if err != nil {
+		return fmt.Errorf("failed to get limits: %w", err)
+	}
+
+	for _, limit := range limits {
+		resolved, changed, removed := resolveLimit(limit, now)
+		if removed {
+			if err := s.limitRepo.DeleteLimit(tx, &limit); err != nil {
+				return fmt.Errorf("failed to remove limit: %w", err)
+			}
+			continue
+		}
+		if changed {
+			if err := s.limitRepo.SaveLimit(tx, &resolved); err != nil {
+				return fmt.Errorf("failed to apply pending limit: %w", err)
+			}
+		}
+
+		maximum, err := parseAmount(resolved.Amount)
+		if err != nil {
+			return fmt.Errorf("invalid limit amount: %w", err)
+		}
+
+		used, err := s.limitUsage(tx, userID, limitType, now.Add(-limitWindows[resolved.Period]))
+		if err != nil {
+			return err
+		}
+
+		if limitExceeded(used, amount, maximum) {
+			logrus.WithFields(logrus.Fields{
+				"userID":    userID,
+				"limitType": limitType,
+				"period":    resolved.Period,
+				"limit":     maximum,
+				"used":      used,
+				"amount":    amount,
+			}).Warn("Transaction exceeds user limit")
+			return fmt.Errorf("%s limit exceeded", limitType)
+		}
+	}
+
+	return nil
+}
+
+func (s *UserService) limitUsage(tx *gorm.DB, userID uint64, limitType string, since time.Time) (float64, error) {
+	switch limitType {
+	case model.LimitLoss:
+		losses, err := s.sumTransactions(tx, userID, "game", "lose", since)
+		if err != nil {
+			return 0, err
+		}
+		wins, err := s.sumTransactions(tx, userID, "game", "win", since)
+		if err != nil {
+			return 0, err
+		}
+		return roundAmount(losses - wins), nil
+	case model.LimitDeposit:
+		return s.sumTransactions(tx, userID, "payment", "win", since)
+	default:
+		return 0, fmt.Errorf("unknown limit type %q", limitType)
+	}
+}
+
+func (s *UserService) sumTransactions(tx *gorm.DB, userID uint64, sourceType, state string, since time.Time) (float64, error) {
+	sum, err := s.userRepo.SumTransactions(tx, userID, sourceType, state, since)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+	return parseAmount(sum)
+}
+
+func limitTypeFor(state, sourceType string) string {
+	switch {
+	case state == "lose" && sourceType == "game":
+		return model.LimitLoss
+	case state == "win" && sourceType == "payment":
+		return model.LimitDeposit
+	default:
+		return ""
+	}
+}
+
+func limitExceeded(used, amount, maximum float64) bool {
+	return roundAmount(used+amount) > maximum
+}
+
+// resolveLimit applies a pending change whose cooling-off period has passed.
+// It reports whether the limit changed and whether it has been removed.
+func resolveLimit(limit model.UserLimit, now time.Time) (model.UserLimit, bool, bool) {
+	if limit.PendingEffectiveAt == nil || limit.PendingEffectiveAt.After(now) {
+		return limit, false, false
+	}
+
+	if limit.PendingAmount == nil {
+		return limit, true, true
+	}
+
+	limit.Amount = *limit.PendingAmount
+	limit.PendingAmount = nil
+	limit.PendingEffectiveAt = nil
+	return limit, true, false
+}
+
+// planLimitChange returns the limit after a change request and whether the
+// change took effect immediately. A nil amount requests removal.
+func planLimitChange(existing *model.UserLimit, amount *float64, now time.Time, coolingOff time.Duration) (*model.UserLimit, bool, error) {
+	if existing == nil {
+		if amount == nil {
+			return nil, false, errors.New("limit not found")
+		}
+		return &model.UserLimit{Amount: formatAmount(*amount)}, true, nil
+	}
+
+	limit := *existing
+	current, err := parseAmount(limit.Amount)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid limit amount: %w", err)
+	}
+
+	if amount != nil && *amount <= current {
+		limit.Amount = formatAmount(*amount)
+		limit.PendingAmount = nil
+		limit.PendingEffectiveAt = nil
+		return &limit, true, nil
+	}
+
+	var pending *string
+	if amount != nil {
+		formatted := formatAmount(*amount)
+		pending = &formatted
+	}
+
+	samePending := limit.PendingEffectiveAt != nil &&
+		((pending == nil && limit.PendingAmount == nil) ||
+			(pending != nil && limit.PendingAmount != nil && *pending == *limit.PendingAmount))
+	if !samePending {
+		effectiveAt := now.Add(coolingOff)
+		limit.PendingEffectiveAt = &effectiveAt
+	}
+	limit.PendingAmount = pending
+
+	return &limit, false, nil
+}
+
+func limitResponse(limit model.UserLimit) dto.LimitResponse {
+	return dto.LimitResponse{
+		Type:               limit.LimitType,
+		Period:             limit.Period,
+		Amount:             limit.Amount,
+		PendingAmount:      limit.PendingAmount,
+		PendingRemoval:     limit.PendingEffectiveAt != nil && limit.PendingAmount == nil,
+		PendingEffectiveAt: limit.PendingEffectiveAt,
+		SetBy:              limit.SetBy,
+		UpdatedAt:          limit.UpdatedAt,
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitTypeFor(t *testing.T) {
+	assert.Equal(t, model.LimitLoss, limitTypeFor("lose", "game"))
+	assert.Equal(t, model.LimitDeposit, limitTypeFor("win", "payment"))
+	assert.Equal(t, "", limitTypeFor("win", "game"))
+	assert.Equal(t, "", limitTypeFor("lose", "payment"))
+	assert.Equal(t, "", limitTypeFor("lose", "server"))
+}
+
+func TestLimitExceeded(t *testing.T) {
+	assert.False(t, limitExceeded(40, 10, 50))
+	assert.True(t, limitExceeded(40, 10.01, 50))
+	assert.False(t, limitExceeded(-20, 60, 50))
+	assert.False(t, limitExceeded(0.1, 0.2, 0.3))
+}
+
+func TestResolveLimit(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+	pending := "200.00"
+
+	tests := []struct {
+		name        string
+		limit       model.UserLimit
+		wantAmount  string
+		wantChanged bool
+		wantRemoved bool
+	}{
+		{
+			name:       "no pending change",
+			limit:      model.UserLimit{Amount: "100.00"},
+			wantAmount: "100.00",
+		},
+		{
+			name:       "pending increase still cooling off",
+			limit:      model.UserLimit{Amount: "100.00", PendingAmount: &pending, PendingEffectiveAt: &future},
+			wantAmount: "100.00",
+		},
+		{
+			name:        "pending increase takes effect",
+			limit:       model.UserLimit{Amount: "100.00", PendingAmount: &pending, PendingEffectiveAt: &past},
+			wantAmount:  "200.00",
+			wantChanged: true,
+		},
+		{
+			name:        "pending removal takes effect",
+			limit:       model.UserLimit{Amount: "100.00", PendingEffectiveAt: &past},
+			wantAmount:  "100.00",
+			wantChanged: true,
+			wantRemoved: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, removed := resolveLimit(tt.limit, now)
+			assert.Equal(t, tt.wantAmount, got.Amount)
+			assert.Equal(t, tt.wantChanged, changed)
+			assert.Equal(t, tt.wantRemoved, removed)
+			if changed && !removed {
+				assert.Nil(t, got.PendingAmount)
+				assert.Nil(t, got.PendingEffectiveAt)
+			}
+		})
+	}
+}
+
+func TestPlanLimitChange(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	coolingOff := 24 * time.Hour
+	earlier := now.Add(-time.Hour)
+	pending := "200.00"
+	amount := func(value float64) *float64 { return &value }
+
+	t.Run("new limit applies immediately", func(t *testing.T) {
+		got, immediate, err := planLimitChange(nil, amount(50), now, coolingOff)
+		assert.NoError(t, err)
+		assert.True(t, immediate)
+		assert.Equal(t, "50.00", got.Amount)
+		assert.Nil(t, got.PendingEffectiveAt)
+	})
+
+	t.Run("removing a missing limit fails", func(t *testing.T) {
+		_, _, err := planLimitChange(nil, nil, now, coolingOff)
+		assert.EqualError(t, err, "limit not found")
+	})
+
+	t.Run("decrease applies immediately and clears pending change", func(t *testing.T) {
+		existing := &model.UserLimit{ID: 3, Amount: "100.00", PendingAmount: &pending, PendingEffectiveAt: &earlier}
+		got, immediate, err := planLimitChange(existing, amount(80), now, coolingOff)
+		assert.NoError(t, err)
+		assert.True(t, immediate)
+		assert.Equal(t, uint64(3), got.ID)
+		assert.Equal(t, "80.00", got.Amount)
+		assert.Nil(t, got.PendingAmount)
+		assert.Nil(t, got.PendingEffectiveAt)
+	})
+
+	t.Run("increase waits for cooling-off", func(t *testing.T) {
+		existing := &model.UserLimit{Amount: "100.00"}
+		got, immediate, err := planLimitChange(existing, amount(150), now, coolingOff)
+		assert.NoError(t, err)
+		assert.False(t, immediate)
+		assert.Equal(t, "100.00", got.Amount)
+		assert.Equal(t, "150.00", *got.PendingAmount)
+		assert.Equal(t, now.Add(coolingOff), *got.PendingEffectiveAt)
+	})
+
+	t.Run("repeating a pending increase keeps its effective time", func(t *testing.T) {
+		existing := &model.UserLimit{Amount: "100.00", PendingAmount: &pending, PendingEffectiveAt: &earlier}
+		got, immediate, err := planLimitChange(existing, amount(200), now, coolingOff)
+		assert.NoError(t, err)
+		assert.False(t, immediate)
+		assert.Equal(t, earlier, *got.PendingEffectiveAt)
+	})
+
+	t.Run("removal waits for cooling-off", func(t *testing.T) {
+		existing := &model.UserLimit{Amount: "100.00"}
+		got, immediate, err := planLimitChange(existing, nil, now, coolingOff)
+		assert.NoError(t, err)
+		assert.False(t, immediate)
+		assert.Nil(t, got.PendingAmount)
+		assert.Equal(t, now.Add(coolingOff), *got.PendingEffectiveAt)
+	})
+}
+
+func TestLimitSetBy(t *testing.T) {
+	assert.Equal(t, "user", limitSetBy(Actor{}))
+	assert.Equal(t, "user", limitSetBy(Actor{RequestID: "req-1"}))
+	assert.Equal(t, "operator:alice", limitSetBy(Actor{ID: "alice", RequestID: "req-1"}))
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	// LockingPessimistic locks the user row, wallets and active bonuses with
+	// SELECT FOR UPDATE before a transaction is checked, so transactions of
+	// one user run one after another.
+	LockingPessimistic = "pessimistic"
+	// LockingOptimistic reads them without locks and makes the first balance
+	// write conditional on the user's version. A transaction that lost the
+	// race is rolled back and run again.
+	LockingOptimistic = "optimistic"
+)
+
+// errVersionConflict reports that the user changed between reading and
+// writing the balance in optimistic mode. It is retried, and only reaches the
+// caller once every attempt lost the race.
+var errVersionConflict = errors.New("balance changed concurrently")
+
+// lockUser reads the user a transaction is booked against.
+//
+// In optimistic mode nothing is locked until updateBalance. Every write to a
+// user's balance, wallets or bonuses bumps the user's version in the same
+// database transaction, so the version check there also covers the wallets,
+// bonuses and transaction sums read in between.
+func (s *UserService) lockUser(tx *gorm.DB, userID uint64) (*model.User, error) {
+	if s.locking == LockingOptimistic {
+		return s.userRepo.GetUserUnlocked(tx, userID)
+	}
+	return s.userRepo.GetUserForUpdate(tx, userID)
+}
+
+func (s *UserService) lockWallets(tx *gorm.DB, userID uint64) ([]model.Wallet, error) {
+	if s.locking == LockingOptimistic {
+		return s.walletRepo.GetWalletsUnlocked(tx, userID)
+	}
+	return s.walletRepo.GetWalletsForUpdate(tx, userID)
+}
+
+func (s *UserService) lockActiveBonuses(tx *gorm.DB, userID uint64) ([]model.Bonus, error) {
+	if s.locking == LockingOptimistic {
+		return s.bonusRepo.GetActiveBonusesUnlocked(tx, userID)
+	}
+	return s.bonusRepo.GetActiveBonusesForUpdate(tx, userID)
+}
+
+// updateBalance writes the user's new balance. In optimistic mode the first
+// write fails with errVersionConflict if the user changed since lockUser; once
+// it succeeds the row stays locked, so later writes in the same database
+// transaction cannot conflict.
+func (s *UserService) updateBalance(tx *gorm.DB, user *model.User, balance string) error {
+	if s.locking != LockingOptimistic {
+		if err := s.userRepo.UpdateUserBalance(tx, user.ID, balance); err != nil {
+			return err
+		}
+		user.Version++
+		return nil
+	}
+
+	updated, err := s.userRepo.UpdateUserBalanceIfVersion(tx, user.ID, user.Version, balance)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errVersionConflict
+	}
+	user.Version++
+	return nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrRetriesExhausted reports that a database transaction kept failing with a
+// serialization failure or deadlock on every attempt. It wraps the last
+// database error.
+var ErrRetriesExhausted = errors.New("database retries exhausted")
+
+// Reasons a database transaction is run again, as counted in metrics.
+const (
+	retrySerializationFailure = "serialization_failure"
+	retryDeadlock             = "deadlock"
+	retryVersionConflict      = "version_conflict"
+)
+
+// withRetries runs attempt, which wraps a whole database transaction, again
+// when it fails in a way another run can fix:
+//
+//   - a lost optimistic race is run again right away, up to the optimistic
+//     attempt limit;
+//   - a serialization failure or deadlock is run again after a jittered
+//     backoff, up to the retry attempt limit.
+//
+// Every attempt starts from scratch: it reads everything through its own
+// database transaction and its results are only published after it commits,
+// so nothing of a failed attempt survives. A transaction whose earlier
+// attempt did commit is rejected as a duplicate instead of being booked
+// twice. Once the limit is reached the caller gets errVersionConflict for lost
+// races, and ErrRetriesExhausted wrapping the last database error otherwise.
+func (s *UserService) withRetries(attempt func() error) error {
+	conflicts, failures := 0, 0
+	for {
+		err := attempt()
+		reason := retryReason(err)
+		if reason == "" {
+			return err
+		}
+
+		var limit, tries int
+		var delay time.Duration
+		if reason == retryVersionConflict {
+			conflicts++
+			limit, tries = s.maxAttempts, conflicts
+		} else {
+			failures++
+			limit, tries = s.retryAttempts, failures
+			delay = retryDelay(s.retryBase, s.retryMaxDelay, failures)
+		}
+
+		fields := logrus.Fields{"reason": reason, "attempt": tries, "maxAttempts": limit, "error": err}
+		if tries >= limit {
+			metrics.TransactionRetriesExhausted.Add(reason, 1)
+			logrus.WithFields(fields).Error("Giving up on database transaction after its last attempt")
+			if reason == retryVersionConflict {
+				return errVersionConflict
+			}
+			return fmt.Errorf("%w: %w", ErrRetriesExhausted, err)
+		}
+
+		metrics.TransactionRetries.Add(reason, 1)
+		logrus.WithFields(fields).WithField("delay", delay).Warn("Retrying database transaction")
+		time.Sleep(delay)
+	}
+}
+
+// retryReason classifies an attempt's error, returning an empty string when
+// it must not be retried.
+func retryReason(err error) string {
+	if errors.Is(err, errVersionConflict) {
+		return retryVersionConflict
+	}
+	switch database.TransientErrorCode(err) {
+	case database.CodeSerializationFailure:
+		return retrySerializationFailure
+	case database.CodeDeadlockDetected:
+		return retryDeadlock
+	default:
+		return ""
+	}
+}
+
+// retryDelay is the backoff before the given retry: base doubled per earlier
+// retry and capped at maximum, minus a random amount of up to half of it so
+// that the transactions that collided do not collide again.
+func retryDelay(base, maximum time.Duration, retry int) time.Duration {
+	delay := base
+	for i := 1; i < retry && delay < maximum; i++ {
+		delay *= 2
+	}
+	if delay > maximum {
+		delay = maximum
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRetries(t *testing.T) {
+	otherErr := errors.New("insufficient balance")
+	deadlock := fmt.Errorf("failed to update balance: %w", &pgconn.PgError{Code: "40P01"})
+	serialization := &pgconn.PgError{Code: "40001"}
+
+	tests := []struct {
+		name          string
+		maxAttempts   int
+		retryAttempts int
+		results       []error
+		wantErr       error
+		wantAttempts  int
+	}{
+		{
+			name:         "succeeds first time",
+			maxAttempts:  3,
+			results:      []error{nil},
+			wantAttempts: 1,
+		},
+		{
+			name:         "retries a lost race",
+			maxAttempts:  3,
+			results:      []error{errVersionConflict, errVersionConflict, nil},
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after the last optimistic attempt",
+			maxAttempts:  2,
+			results:      []error{errVersionConflict, errVersionConflict, nil},
+			wantErr:      errVersionConflict,
+			wantAttempts: 2,
+		},
+		{
+			name:          "retries deadlocks and serialization failures",
+			retryAttempts: 3,
+			results:       []error{deadlock, serialization, nil},
+			wantAttempts:  3,
+		},
+		{
+			name:          "gives up after the last retry of a transient error",
+			retryAttempts: 2,
+			results:       []error{serialization, deadlock, nil},
+			wantErr:       fmt.Errorf("%w: %w", ErrRetriesExhausted, deadlock),
+			wantAttempts:  2,
+		},
+		{
+			name:          "counts lost races and transient errors separately",
+			maxAttempts:   2,
+			retryAttempts: 2,
+			results:       []error{errVersionConflict, deadlock, nil},
+			wantAttempts:  3,
+		},
+		{
+			name:          "other errors are not retried",
+			maxAttempts:   3,
+			retryAttempts: 3,
+			results:       []error{otherErr, nil},
+			wantErr:       otherErr,
+			wantAttempts:  1,
+		},
+		{
+			name:          "other database errors are not retried",
+			retryAttempts: 3,
+			results:       []error{&pgconn.PgError{Code: "23505"}, nil},
+			wantErr:       &pgconn.PgError{Code: "23505"},
+			wantAttempts:  1,
+		},
+		{
+			name:         "runs at least once",
+			results:      []error{nil},
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &UserService{maxAttempts: tt.maxAttempts, retryAttempts: tt.retryAttempts}
+			attempts := 0
+			err := s.withRetries(func() error {
+				attempts++
+				return tt.results[attempts-1]
+			})
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantAttempts, attempts)
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    time.Duration
+		maximum time.Duration
+		retry   int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{name: "first retry", base: 10 * time.Millisecond, maximum: time.Second, retry: 1, min: 5 * time.Millisecond, max: 10 * time.Millisecond},
+		{name: "doubles per retry", base: 10 * time.Millisecond, maximum: time.Second, retry: 3, min: 20 * time.Millisecond, max: 40 * time.Millisecond},
+		{name: "capped at maximum", base: 10 * time.Millisecond, maximum: 50 * time.Millisecond, retry: 10, min: 25 * time.Millisecond, max: 50 * time.Millisecond},
+		{name: "no delay configured", base: 0, maximum: time.Second, retry: 2, min: 0, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				delay := retryDelay(tt.base, tt.maximum, tt.retry)
+				assert.GreaterOrEqual(t, delay, tt.min)
+				assert.LessOrEqual(t, delay, tt.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/stream"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// expiredBatchSize caps how many overdue reviews, or users with expired
+// bonuses, one expiry pass handles.
+const expiredBatchSize = 100
+
+// expiryActor is recorded as the reviewer of reviews declined because they
+// outlived the review SLA.
+var expiryActor = Actor{ID: "review-sla"}
+
+type ReviewService struct {
+	reviewRepo  database.ReviewRepository
+	auditRepo   database.AuditRepository
+	outboxRepo  database.OutboxRepository
+	userService *UserService
+}
+
+func NewReviewService() *ReviewService {
+	return &ReviewService{
+		reviewRepo:  database.NewReviewRepository(),
+		auditRepo:   database.NewAuditRepository(),
+		outboxRepo:  database.NewOutboxRepository(),
+		userService: NewUserService(),
+	}
+}
+
+func (s *ReviewService) GetReview(reviewID uint64) (*dto.ReviewResponse, error) {
+	review, err := s.reviewRepo.GetReview(reviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("review not found")
+		}
+		logrus.WithFields(logrus.Fields{"reviewID": reviewID, "error": err}).Error("Failed to get review")
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+
+	response := reviewResponse(*review)
+	return &response, nil
+}
+
+// ListReviews lists held transactions oldest first, so the queue is worked
+// in the order transactions arrived.
+func (s *ReviewService) ListReviews(status string, userID uint64, page, pageSize int) (*dto.ReviewListResponse, error) {
+	logrus.WithFields(logrus.Fields{"status": status, "userID": userID, "page": page}).Info("Listing reviews")
+
+	reviews, total, err := s.reviewRepo.ListReviews(status, userID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to list reviews")
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	response := &dto.ReviewListResponse{
+		Reviews:  make([]dto.ReviewResponse, 0, len(reviews)),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}
+	for _, review := range reviews {
+		response.Reviews = append(response.Reviews, reviewResponse(review))
+	}
+	return response, nil
+}
+
+// ApproveReview books the held transaction through the regular transaction
+// path under the user row lock. The booking, the status change and the audit
+// entry commit together; if the booking is rejected, for example for
+// insufficient balance, the review stays pending and can still be declined,
+// so no failure is reported to the source.
+func (s *ReviewService) ApproveReview(reviewID uint64, req dto.DecideReviewRequest, actor Actor) (*dto.ReviewResponse, error) {
+	logrus.WithFields(logrus.Fields{"reviewID": reviewID, "actor": actor.ID}).Info("Approving held transaction")
+
+	var (
+		review *model.TransactionReview
+		update stream.Message
+	)
+	err := s.userService.withRetries(func() error {
+		return s.reviewRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+			var before dto.ReviewResponse
+			var err error
+			review, before, err = s.pendingReview(tx, reviewID)
+			if err != nil {
+				return err
+			}
+
+			result, err := s.userService.processTransaction(tx, review.UserID, reviewTransaction(review), review.SourceType, review)
+			if err != nil {
+				return err
+			}
+			update = result.update
+
+			decideReview(review, model.ReviewApproved, req.Note, actor, time.Now().UTC())
+			return s.saveDecision(tx, review, before, model.AuditReviewApprove, actor)
+		})
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"reviewID": reviewID, "error": err}).Warn("Failed to approve held transaction")
+		return nil, err
+	}
+
+	s.userService.hub.Publish(review.UserID, update)
+
+	response := reviewResponse(*review)
+	return &response, nil
+}
+
+// DeclineReview declines the held transaction. Its amount is never applied
+// and the submitting source is told through a transaction.declined event.
+func (s *ReviewService) DeclineReview(reviewID uint64, req dto.DecideReviewRequest, actor Actor) (*dto.ReviewResponse, error) {
+	logrus.WithFields(logrus.Fields{"reviewID": reviewID, "actor": actor.ID}).Info("Declining held transaction")
+
+	review, err := s.decline(reviewID, req.Note, "review_declined", actor)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"reviewID": reviewID, "error": err}).Warn("Failed to decline held transaction")
+		return nil, err
+	}
+
+	response := reviewResponse(*review)
+	return &response, nil
+}
+
+// DeclineExpired declines reviews held since before the given time and
+// returns how many it declined. Reviews decided in the meantime are skipped.
+func (s *ReviewService) DeclineExpired(before time.Time) (int, error) {
+	reviewIDs, err := s.reviewRepo.ListExpiredReviews(before, expiredBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired reviews: %w", err)
+	}
+
+	declined := 0
+	note := fmt.Sprintf("Not reviewed within the review SLA (held before %s)", before.Format(time.RFC3339))
+	for _, reviewID := range reviewIDs {
+		if _, err := s.decline(reviewID, note, "review_expired", expiryActor); err != nil {
+			if err.Error() == "review already decided" {
+				continue
+			}
+			return declined, err
+		}
+		declined++
+	}
+	return declined, nil
+}
+
+func (s *ReviewService) decline(reviewID uint64, note, reason string, actor Actor) (*model.TransactionReview, error) {
+	var review *model.TransactionReview
+	err := s.reviewRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		var before dto.ReviewResponse
+		var err error
+		review, before, err = s.pendingReview(tx, reviewID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		decideReview(review, model.ReviewDeclined, note, actor, now)
+		if err := s.saveDecision(tx, review, before, model.AuditReviewDecline, actor); err != nil {
+			return err
+		}
+		return recordReviewEvent(tx, s.outboxRepo, EventTransactionDeclined, review, reason, now)
+	})
+	return review, err
+}
+
+// pendingReview locks the review and checks that it is still pending.
+func (s *ReviewService) pendingReview(tx *gorm.DB, reviewID uint64) (*model.TransactionReview, dto.ReviewResponse, error) {
+	review, err := s.reviewRepo.GetReviewForUpdate(tx, reviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dto.ReviewResponse{}, errors.New("review not found")
+		}
+		return nil, dto.ReviewResponse{}, fmt.Errorf("failed to get review: %w", err)
+	}
+
+	if review.Status != model.ReviewPending {
+		return nil, dto.ReviewResponse{}, errors.New("review already decided")
+	}
+	return review, reviewResponse(*review), nil
+}
+
+func (s *ReviewService) saveDecision(tx *gorm.DB, review *model.TransactionReview, before dto.ReviewResponse, action string, actor Actor) error {
+	if err := s.reviewRepo.SaveReview(tx, review); err != nil {
+		return fmt.Errorf("failed to save review: %w", err)
+	}
+
+	return recordAudit(tx, s.auditRepo, actor, AuditChange{
+		Action:       action,
+		TargetUserID: &review.UserID,
+		Resource:     fmt.Sprintf("transaction_reviews/%d", review.ID),
+		Before:       before,
+		After:        reviewResponse(*review),
+	})
+}
+
+func decideReview(review *model.TransactionReview, status, note string, actor Actor, now time.Time) {
+	review.Status = status
+	review.ReviewedBy = &actor.ID
+	review.ReviewedAt = &now
+	review.UpdatedAt = now
+	if note != "" {
+		review.ReviewNote = &note
+	}
+}
+
+// reviewTransaction rebuilds the request the source submitted, so an approved
+// transaction is booked under its original ID and round.
+func reviewTransaction(review *model.TransactionReview) dto.TransactionRequest {
+	return dto.TransactionRequest{
+		State:         review.State,
+		Amount:        review.Amount,
+		TransactionID: review.TransactionID,
+		RoundID:       stringValue(review.RoundID),
+		GameID:        stringValue(review.GameID),
+		Provider:      stringValue(review.Provider),
+		CloseRound:    review.CloseRound,
+	}
+}
+
+func reviewResponse(review model.TransactionReview) dto.ReviewResponse {
+	return dto.ReviewResponse{
+		ID:            review.ID,
+		UserID:        review.UserID,
+		TransactionID: review.TransactionID,
+		SourceType:    review.SourceType,
+		State:         review.State,
+		Amount:        review.Amount,
+		RoundID:       review.RoundID,
+		GameID:        review.GameID,
+		Provider:      review.Provider,
+		CloseRound:    review.CloseRound,
+		Rule:          review.RuleID,
+		Reason:        review.Reason,
+		Status:        review.Status,
+		ReviewedBy:    review.ReviewedBy,
+		ReviewNote:    review.ReviewNote,
+		ReviewedAt:    review.ReviewedAt,
+		CreatedAt:     review.CreatedAt,
+	}
+}
+
+// screenTransaction runs the transaction rules and fraud checks. A rejection
+// is returned as the error; a review rule or a fraud hold parks the
+// transaction, together with the flags raised, and returns its review;
+// otherwise it returns the flags to record on the booked transaction. A fraud
+// rejection wins over a review rule.
+func (s *UserService) screenTransaction(tx *gorm.DB, user *model.User, req dto.TransactionRequest, sourceType string, amount float64, now time.Time) (*model.TransactionReview, []model.TransactionFlag, error) {
+	reviewRule, flagRules, err := s.checkRules(user, sourceType, req.State, amount, now)
+	if err != nil {
+		var ruleErr *RuleError
+		if errors.As(err, &ruleErr) {
+			logrus.WithFields(logrus.Fields{
+				"userID":        user.ID,
+				"transactionID": req.TransactionID,
+				"rule":          ruleErr.Rule.ID,
+			}).Warn("Transaction rejected by rule")
+		}
+		return nil, nil, err
+	}
+
+	hold, fraudHits, err := s.checkFraud(tx, user.ID, sourceType, req.State, amount, now)
+	if err != nil {
+		var fraudErr *FraudError
+		if errors.As(err, &fraudErr) {
+			logrus.WithFields(logrus.Fields{
+				"userID":        user.ID,
+				"transactionID": req.TransactionID,
+				"check":         fraudErr.Hit.CheckID,
+				"reason":        fraudErr.Hit.Reason,
+			}).Warn("Transaction rejected by fraud check")
+		}
+		return nil, nil, err
+	}
+
+	flags := append(ruleFlags(flagRules), fraudFlags(fraudHits)...)
+	switch {
+	case reviewRule != nil:
+		review, err := s.holdTransaction(tx, user.ID, req, sourceType, reviewRule.ID, reviewRule.Message, flags, now)
+		return review, nil, err
+	case hold != nil:
+		review, err := s.holdTransaction(tx, user.ID, req, sourceType, hold.CheckID, hold.Reason, flags, now)
+		return review, nil, err
+	}
+	return nil, flags, nil
+}
+
+// holdTransaction stores the transaction for review instead of booking it and
+// tells subscribers it is pending. The flags raised while screening it are
+// kept with the review and recorded if it is approved.
+func (s *UserService) holdTransaction(tx *gorm.DB, userID uint64, req dto.TransactionRequest, sourceType, ruleID, reason string, flags []model.TransactionFlag, now time.Time) (*model.TransactionReview, error) {
+	encoded, err := encodeHeldFlags(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &model.TransactionReview{
+		UserID:        userID,
+		TransactionID: req.TransactionID,
+		SourceType:    sourceType,
+		State:         req.State,
+		Amount:        req.Amount,
+		RoundID:       optionalString(req.RoundID),
+		GameID:        optionalString(req.GameID),
+		Provider:      optionalString(req.Provider),
+		CloseRound:    req.CloseRound,
+		RuleID:        ruleID,
+		Reason:        reason,
+		Flags:         encoded,
+		Status:        model.ReviewPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.reviewRepo.CreateReview(tx, review); err != nil {
+		return nil, fmt.Errorf("failed to hold transaction: %w", err)
+	}
+
+	if err := recordReviewEvent(tx, s.outboxRepo, EventTransactionHeld, review, "held_for_review", now); err != nil {
+		return nil, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"userID":        userID,
+		"transactionID": req.TransactionID,
+		"rule":          ruleID,
+		"reason":        reason,
+	}).Warn("Transaction held for review")
+	return review, nil
+}
+
+// heldFlag is a flag raised on a held transaction, stored with its review
+// until the transaction is booked.
+type heldFlag struct {
+	RuleID  string `json:"ruleId"`
+	Message string `json:"message,omitempty"`
+}
+
+func encodeHeldFlags(flags []model.TransactionFlag) (string, error) {
+	held := make([]heldFlag, 0, len(flags))
+	for _, flag := range flags {
+		held = append(held, heldFlag{RuleID: flag.RuleID, Message: flag.Message})
+	}
+	encoded, err := json.Marshal(held)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode flags: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// heldFlags returns the flags raised when the review's transaction was held.
+func heldFlags(review *model.TransactionReview) ([]model.TransactionFlag, error) {
+	if review.Flags == "" {
+		return nil, nil
+	}
+	var held []heldFlag
+	if err := json.Unmarshal([]byte(review.Flags), &held); err != nil {
+		return nil, fmt.Errorf("invalid flags on review %d: %w", review.ID, err)
+	}
+	flags := make([]model.TransactionFlag, 0, len(held))
+	for _, flag := range held {
+		flags = append(flags, model.TransactionFlag{RuleID: flag.RuleID, Message: flag.Message})
+	}
+	return flags, nil
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewTransaction(t *testing.T) {
+	roundID, gameID, provider := "round-1", "starburst", "netent"
+
+	tests := []struct {
+		name     string
+		review   model.TransactionReview
+		expected dto.TransactionRequest
+	}{
+		{
+			name:     "without round",
+			review:   model.TransactionReview{TransactionID: "tx-77", State: "win", Amount: "2500.00"},
+			expected: dto.TransactionRequest{TransactionID: "tx-77", State: "win", Amount: "2500.00"},
+		},
+		{
+			name: "closing a round",
+			review: model.TransactionReview{
+				TransactionID: "tx-78", State: "win", Amount: "40.00",
+				RoundID: &roundID, GameID: &gameID, Provider: &provider, CloseRound: true,
+			},
+			expected: dto.TransactionRequest{
+				TransactionID: "tx-78", State: "win", Amount: "40.00",
+				RoundID: roundID, GameID: gameID, Provider: provider, CloseRound: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, reviewTransaction(&tt.review))
+		})
+	}
+}
+
+func TestDecideReview(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+
+	review := &model.TransactionReview{Status: model.ReviewPending}
+	decideReview(review, model.ReviewApproved, "", Actor{ID: "bob"}, now)
+	assert.Equal(t, model.ReviewApproved, review.Status)
+	assert.Equal(t, "bob", *review.ReviewedBy)
+	assert.Equal(t, now, *review.ReviewedAt)
+	assert.Equal(t, now, review.UpdatedAt)
+	assert.Nil(t, review.ReviewNote)
+
+	review = &model.TransactionReview{Status: model.ReviewPending}
+	decideReview(review, model.ReviewDeclined, "known bonus abuser", expiryActor, now)
+	assert.Equal(t, "review-sla", *review.ReviewedBy)
+	assert.Equal(t, "known bonus abuser", *review.ReviewNote)
+}
+
+func TestHeldFlags(t *testing.T) {
+	flags := []model.TransactionFlag{{RuleID: "watched-user"}, {RuleID: "server-win-burst", Message: "6 wins in 1m"}}
+	encoded, err := encodeHeldFlags(flags)
+	require.NoError(t, err)
+
+	decoded, err := heldFlags(&model.TransactionReview{Flags: encoded})
+	require.NoError(t, err)
+	assert.Equal(t, flags, decoded)
+
+	none, err := encodeHeldFlags(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", none)
+
+	_, err = heldFlags(&model.TransactionReview{ID: 3, Flags: "{"})
+	assert.ErrorContains(t, err, "invalid flags on review 3")
+}
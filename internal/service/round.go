@@ -0,0 +1,415 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/stream"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// enterRound finds or opens the round a transaction belongs to. A round is
+// opened by its first bet; wins and later bets must come from the same user,
+// game and provider while the round is still open. Callers hold the user row
+// lock, so entries of one round are checked one at a time.
+func (s *UserService) enterRound(tx *gorm.DB, userID uint64, req dto.TransactionRequest, sourceType string) (*model.GameRound, error) {
+	if req.RoundID == "" {
+		return nil, nil
+	}
+	if sourceType != "game" {
+		return nil, errors.New("round requires game source")
+	}
+
+	round, err := s.roundRepo.GetRoundForUpdate(tx, req.RoundID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if req.State != "lose" {
+			return nil, errors.New("round has no bet")
+		}
+		round = &model.GameRound{
+			RoundID:  req.RoundID,
+			UserID:   userID,
+			GameID:   req.GameID,
+			Provider: req.Provider,
+			Status:   model.RoundOpen,
+		}
+		if err := s.roundRepo.CreateRound(tx, round); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return nil, errors.New("round mismatch")
+			}
+			return nil, fmt.Errorf("failed to create round: %w", err)
+		}
+		return round, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get round: %w", err)
+	}
+
+	if err := checkRoundEntry(round, userID, req); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"userID":        userID,
+			"transactionID": req.TransactionID,
+			"roundID":       req.RoundID,
+			"status":        round.Status,
+			"error":         err,
+		}).Warn("Transaction rejected by round rules")
+		return nil, err
+	}
+	return round, nil
+}
+
+// checkRoundEntry applies the round rules to a transaction joining an
+// existing round.
+func checkRoundEntry(round *model.GameRound, userID uint64, req dto.TransactionRequest) error {
+	if round.UserID != userID || round.GameID != req.GameID || round.Provider != req.Provider {
+		return errors.New("round mismatch")
+	}
+	if round.Status != model.RoundOpen {
+		return errors.New("round closed")
+	}
+	return nil
+}
+
+func (s *UserService) GetRound(roundID string) (*dto.RoundResponse, error) {
+	logrus.WithField("roundID", roundID).Info("Getting game round")
+
+	round, err := s.roundRepo.GetRound(roundID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("round not found")
+		}
+		logrus.WithFields(logrus.Fields{"roundID": roundID, "error": err}).Error("Failed to get round")
+		return nil, fmt.Errorf("failed to get round: %w", err)
+	}
+
+	transactions, err := s.roundRepo.GetRoundTransactions(s.roundRepo.GetDB(), round.ID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"roundID": roundID, "error": err}).Error("Failed to get round transactions")
+		return nil, fmt.Errorf("failed to get round transactions: %w", err)
+	}
+
+	return roundResponse(round, transactions)
+}
+
+// RollbackRound reverses every transaction of a round and marks it rolled
+// back. Bets are refunded to the wallets they were taken from; wins are taken
+// back like a game bet, so a rollback fails with insufficient balance when the
+// winnings have already been spent. The wagering a bet counted towards active
+// bonuses is given back; a bet that helped convert a bonus cannot be rolled
+// back. Reversals are booked as transactions "rollback:<id>" of the same
+// round. The operator's rollback is audited with the round and the user's
+// balance before and after it.
+func (s *UserService) RollbackRound(roundID string, actor Actor) (*dto.RoundResponse, error) {
+	logrus.WithField("roundID", roundID).Info("Rolling back game round")
+
+	round, err := s.roundRepo.GetRound(roundID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("round not found")
+		}
+		return nil, fmt.Errorf("failed to get round: %w", err)
+	}
+
+	var update *stream.Message
+	err = s.userRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		open, err := s.closingRepo.LockOpenPeriod(tx, now)
+		if err != nil {
+			return fmt.Errorf("failed to check business day: %w", err)
+		}
+		if !open {
+			return errors.New("business day closed")
+		}
+
+		// Lock the user before the round, in the same order as transactions.
+		user, err := s.userRepo.GetUserForUpdate(tx, round.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		round, err = s.roundRepo.GetRoundForUpdate(tx, roundID)
+		if err != nil {
+			return fmt.Errorf("failed to get round: %w", err)
+		}
+		if round.Status == model.RoundRolledBack {
+			return errors.New("round already rolled back")
+		}
+
+		wallets, err := s.walletRepo.GetWalletsForUpdate(tx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get wallets: %w", err)
+		}
+		balances, err := walletBalancesFor(user, wallets)
+		if err != nil {
+			return fmt.Errorf("invalid wallet balance: %w", err)
+		}
+		bonuses, err := s.bonusRepo.GetActiveBonusesForUpdate(tx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get active bonuses: %w", err)
+		}
+		balance, err := parseAmount(user.Balance)
+		if err != nil {
+			return fmt.Errorf("invalid current balance: %w", err)
+		}
+
+		transactions, err := s.roundRepo.GetRoundTransactions(tx, round.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get round transactions: %w", err)
+		}
+
+		for _, original := range transactions {
+			reversal, newBalance, err := s.reverseTransaction(tx, original, balance, balances, bonuses, now)
+			if err != nil {
+				return err
+			}
+			balance = newBalance
+
+			message, err := balanceUpdate(reversal, balance, balances, now)
+			if err != nil {
+				return err
+			}
+			update = &message
+		}
+
+		if err := s.userRepo.UpdateUserBalance(tx, user.ID, formatAmount(balance)); err != nil {
+			return fmt.Errorf("failed to update balance: %w", err)
+		}
+		if err := s.roundRepo.UpdateRoundStatus(tx, round.ID, model.RoundRolledBack, now); err != nil {
+			return fmt.Errorf("failed to update round: %w", err)
+		}
+
+		err = recordAudit(tx, s.auditRepo, actor, AuditChange{
+			Action:       model.AuditRoundRollback,
+			TargetUserID: &user.ID,
+			Resource:     fmt.Sprintf("game_rounds/%d", round.ID),
+			Before:       rollbackAuditState(round.RoundID, round.Status, user.Balance),
+			After:        rollbackAuditState(round.RoundID, model.RoundRolledBack, formatAmount(balance)),
+		})
+		if err != nil {
+			return err
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"roundID":      roundID,
+			"userID":       user.ID,
+			"transactions": len(transactions),
+			"newBalance":   balance,
+		}).Info("Game round rolled back")
+		return nil
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"roundID": roundID, "error": err}).Warn("Failed to roll back game round")
+		return nil, err
+	}
+
+	if update != nil {
+		s.hub.Publish(round.UserID, *update)
+	}
+	return s.GetRound(roundID)
+}
+
+// rollbackAuditState is the part of a round and its user a rollback changes.
+func rollbackAuditState(roundID, status, balance string) map[string]interface{} {
+	return map[string]interface{}{
+		"roundId": roundID,
+		"status":  status,
+		"balance": balance,
+	}
+}
+
+// reverseTransaction books the opposite of original and returns it with the
+// user's new total balance. balances and bonuses are updated in place. Both
+// bets and wins are reversed through the wallet entries they were booked
+// with, so money goes back to, or is taken from, the wallet it actually moved
+// through.
+func (s *UserService) reverseTransaction(tx *gorm.DB, original model.Transaction, balance float64, balances walletBalances, bonuses []model.Bonus, now time.Time) (*model.Transaction, float64, error) {
+	amount, err := parseAmount(original.Amount)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid transaction amount: %w", err)
+	}
+
+	entries, err := s.roundRepo.GetTransactionWalletEntries(tx, original.ID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get wallet entries: %w", err)
+	}
+
+	var deltas map[string]float64
+	if original.State == "lose" {
+		if err := s.restoreWagering(tx, original.ID, bonuses); err != nil {
+			return nil, 0, err
+		}
+		deltas, err = refundDeltas(entries, amount)
+		if err != nil {
+			return nil, 0, err
+		}
+		// The bonus wallet part goes back bonus by bonus in refundBonuses.
+		delete(deltas, model.WalletBonus)
+	} else {
+		deltas, err = winReversalDeltas(entries, balances, original.SourceType, amount)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	reversal := reversalTransaction(original)
+	newBalance, err := calculateNewBalance(balance, amount, reversal.State)
+	if err != nil {
+		return nil, 0, err
+	}
+	setBalances(reversal, balance, newBalance)
+
+	if err := s.createTransaction(tx, reversal); err != nil {
+		return nil, 0, fmt.Errorf("failed to create reversal: %w", err)
+	}
+
+	entry := model.WalletEntry{UserID: original.UserID, TransactionID: &reversal.ID}
+	if err := s.applyWalletDeltas(tx, entry, balances, deltas, bonuses); err != nil {
+		return nil, 0, err
+	}
+	var forfeits []bonusForfeit
+	if original.State == "lose" {
+		if forfeits, err = s.refundBonuses(tx, reversal, entries, balances, bonuses); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err := s.recordBalanceChange(tx, reversal, balance, newBalance, balances, now); err != nil {
+		return nil, 0, err
+	}
+	if err := s.recordTransactionProcessed(tx, reversal, newBalance, now); err != nil {
+		return nil, 0, err
+	}
+
+	for _, forfeit := range forfeits {
+		if newBalance, err = s.forfeitRefund(tx, reversal, forfeit, newBalance, balances, now); err != nil {
+			return nil, 0, err
+		}
+	}
+	return reversal, newBalance, nil
+}
+
+func reversalTransaction(original model.Transaction) *model.Transaction {
+	state := "lose"
+	if original.State == "lose" {
+		state = "win"
+	}
+	return &model.Transaction{
+		UserID:        original.UserID,
+		TransactionID: model.ReversalPrefix + strconv.FormatUint(original.ID, 10),
+		Amount:        original.Amount,
+		State:         state,
+		SourceType:    original.SourceType,
+		RoundID:       original.RoundID,
+	}
+}
+
+// refundDeltas returns a bet to the wallets it was taken from. Bets made
+// before wallets existed have no entries and are refunded to cash. The bonus
+// wallet part is returned in total; refundBonuses splits it by bonus.
+func refundDeltas(entries []model.WalletEntry, amount float64) (map[string]float64, error) {
+	if len(entries) == 0 {
+		return map[string]float64{model.WalletCash: amount}, nil
+	}
+
+	deltas := map[string]float64{}
+	for _, entry := range entries {
+		taken, err := parseAmount(entry.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wallet entry amount: %w", err)
+		}
+		deltas[entry.WalletType] = roundAmount(deltas[entry.WalletType] - taken)
+	}
+	return deltas, nil
+}
+
+// winReversalDeltas takes a win back from the wallets it was paid into. A
+// wallet that no longer holds its part, because locked winnings were released
+// to cash or spent, leaves the rest to be taken like a bet of the win's source
+// type. Wins booked before wallets existed have no entries and are taken back
+// like a bet.
+func winReversalDeltas(entries []model.WalletEntry, balances walletBalances, sourceType string, amount float64) (map[string]float64, error) {
+	if len(entries) == 0 {
+		return allocateDebit(balances, sourceType, amount)
+	}
+
+	deltas := map[string]float64{}
+	remaining := walletBalances{}
+	for walletType, balance := range balances {
+		remaining[walletType] = balance
+	}
+
+	shortfall := 0.0
+	for _, entry := range entries {
+		paid, err := parseAmount(entry.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wallet entry amount: %w", err)
+		}
+		take := math.Max(math.Min(paid, remaining[entry.WalletType]), 0)
+		if take > 0 {
+			deltas[entry.WalletType] = roundAmount(deltas[entry.WalletType] - take)
+			remaining[entry.WalletType] = roundAmount(remaining[entry.WalletType] - take)
+		}
+		shortfall = roundAmount(shortfall + paid - take)
+	}
+	if shortfall <= 0 {
+		return deltas, nil
+	}
+
+	rest, err := allocateDebit(remaining, sourceType, shortfall)
+	if err != nil {
+		return nil, err
+	}
+	for walletType, delta := range rest {
+		deltas[walletType] = roundAmount(deltas[walletType] + delta)
+	}
+	return deltas, nil
+}
+
+func roundResponse(round *model.GameRound, transactions []model.Transaction) (*dto.RoundResponse, error) {
+	response := &dto.RoundResponse{
+		RoundID:      round.RoundID,
+		UserID:       round.UserID,
+		GameID:       round.GameID,
+		Provider:     round.Provider,
+		Status:       round.Status,
+		Transactions: make([]dto.RoundTransaction, 0, len(transactions)),
+		CreatedAt:    round.CreatedAt,
+		ClosedAt:     round.ClosedAt,
+	}
+
+	var bets, wins float64
+	for _, transaction := range transactions {
+		amount, err := parseAmount(transaction.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction amount: %w", err)
+		}
+		if transaction.State == "lose" {
+			bets += amount
+		} else {
+			wins += amount
+		}
+
+		response.Transactions = append(response.Transactions, dto.RoundTransaction{
+			TransactionID: transaction.TransactionID,
+			State:         transaction.State,
+			Amount:        transaction.Amount,
+			BalanceBefore: transaction.BalanceBefore,
+			BalanceAfter:  transaction.BalanceAfter,
+			CreatedAt:     transaction.CreatedAt,
+		})
+	}
+
+	response.Bets = formatAmount(bets)
+	response.Wins = formatAmount(wins)
+	net := roundAmount(wins - bets)
+	if net == 0 {
+		net = 0 // drop the sign of -0
+	}
+	response.Net = formatAmount(net)
+	return response, nil
+}
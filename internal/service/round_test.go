@@ -0,0 +1,153 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRoundEntry(t *testing.T) {
+	open := model.GameRound{UserID: 1, GameID: "starburst", Provider: "netent", Status: model.RoundOpen}
+	closed := open
+	closed.Status = model.RoundClosed
+	rolledBack := open
+	rolledBack.Status = model.RoundRolledBack
+
+	tests := []struct {
+		name          string
+		round         model.GameRound
+		userID        uint64
+		req           dto.TransactionRequest
+		expectedError string
+	}{
+		{name: "win in open round", round: open, userID: 1, req: dto.TransactionRequest{State: "win", GameID: "starburst", Provider: "netent"}},
+		{name: "second bet in open round", round: open, userID: 1, req: dto.TransactionRequest{State: "lose", GameID: "starburst", Provider: "netent"}},
+		{name: "win in closed round", round: closed, userID: 1, req: dto.TransactionRequest{State: "win", GameID: "starburst", Provider: "netent"}, expectedError: "round closed"},
+		{name: "bet in rolled back round", round: rolledBack, userID: 1, req: dto.TransactionRequest{State: "lose", GameID: "starburst", Provider: "netent"}, expectedError: "round closed"},
+		{name: "other user", round: open, userID: 2, req: dto.TransactionRequest{State: "win", GameID: "starburst", Provider: "netent"}, expectedError: "round mismatch"},
+		{name: "other game", round: open, userID: 1, req: dto.TransactionRequest{State: "win", GameID: "gonzo", Provider: "netent"}, expectedError: "round mismatch"},
+		{name: "other provider", round: open, userID: 1, req: dto.TransactionRequest{State: "win", GameID: "starburst", Provider: "evolution"}, expectedError: "round mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRoundEntry(&tt.round, tt.userID, tt.req)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReversalTransaction(t *testing.T) {
+	roundID := uint64(9)
+
+	bet := model.Transaction{ID: 41, UserID: 1, TransactionID: "bet-1", Amount: "2.00", State: "lose", SourceType: "game", RoundID: &roundID}
+	reversal := reversalTransaction(bet)
+	assert.Equal(t, &model.Transaction{
+		UserID: 1, TransactionID: "rollback:41", Amount: "2.00", State: "win", SourceType: "game", RoundID: &roundID,
+	}, reversal)
+
+	win := model.Transaction{ID: 42, UserID: 1, TransactionID: "win-1", Amount: "5.00", State: "win", SourceType: "game", RoundID: &roundID}
+	assert.Equal(t, "lose", reversalTransaction(win).State)
+}
+
+func TestRefundDeltas(t *testing.T) {
+	deltas, err := refundDeltas([]model.WalletEntry{
+		{WalletType: model.WalletCash, Amount: "-1.50"},
+		{WalletType: model.WalletBonus, Amount: "-0.50"},
+	}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{model.WalletCash: 1.5, model.WalletBonus: 0.5}, deltas)
+
+	deltas, err = refundDeltas(nil, 3)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{model.WalletCash: 3}, deltas)
+}
+
+func TestWinReversalDeltas(t *testing.T) {
+	tests := []struct {
+		name       string
+		entries    []model.WalletEntry
+		balances   walletBalances
+		amount     float64
+		wantWallet walletBalances
+		wantErr    string
+	}{
+		{
+			name:       "win paid into locked is taken back from locked",
+			entries:    []model.WalletEntry{{WalletType: model.WalletLocked, Amount: "20.00"}},
+			balances:   walletBalances{model.WalletCash: 50, model.WalletLocked: 20, model.WalletBonus: 10},
+			amount:     20,
+			wantWallet: walletBalances{model.WalletCash: 50, model.WalletLocked: 0, model.WalletBonus: 10},
+		},
+		{
+			name:       "win paid into cash is taken back from cash",
+			entries:    []model.WalletEntry{{WalletType: model.WalletCash, Amount: "5.00"}},
+			balances:   walletBalances{model.WalletCash: 50, model.WalletLocked: 20, model.WalletBonus: 10},
+			amount:     5,
+			wantWallet: walletBalances{model.WalletCash: 45, model.WalletLocked: 20, model.WalletBonus: 10},
+		},
+		{
+			name:       "released locked winnings are taken from cash",
+			entries:    []model.WalletEntry{{WalletType: model.WalletLocked, Amount: "20.00"}},
+			balances:   walletBalances{model.WalletCash: 70, model.WalletLocked: 5, model.WalletBonus: 0},
+			amount:     20,
+			wantWallet: walletBalances{model.WalletCash: 55, model.WalletLocked: 0, model.WalletBonus: 0},
+		},
+		{
+			name:       "win from before wallets is taken like a bet",
+			balances:   walletBalances{model.WalletCash: 10, model.WalletLocked: 20, model.WalletBonus: 0},
+			amount:     15,
+			wantWallet: walletBalances{model.WalletCash: 0, model.WalletLocked: 15, model.WalletBonus: 0},
+		},
+		{
+			name:     "winnings already spent",
+			entries:  []model.WalletEntry{{WalletType: model.WalletLocked, Amount: "20.00"}},
+			balances: walletBalances{model.WalletCash: 5, model.WalletLocked: 0, model.WalletBonus: 0},
+			amount:   20,
+			wantErr:  "insufficient balance",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deltas, err := winReversalDeltas(tt.entries, tt.balances, "game", tt.amount)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			wallets := walletBalances{}
+			for walletType, balance := range tt.balances {
+				wallets[walletType] = roundAmount(balance + deltas[walletType])
+			}
+			assert.Equal(t, tt.wantWallet, wallets)
+		})
+	}
+}
+
+func TestRoundResponse(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	round := &model.GameRound{RoundID: "r-1", UserID: 1, GameID: "starburst", Provider: "netent", Status: model.RoundRolledBack, CreatedAt: now, ClosedAt: &now}
+
+	response, err := roundResponse(round, []model.Transaction{
+		{TransactionID: "bet-1", State: "lose", Amount: "2.00", CreatedAt: now},
+		{TransactionID: "win-1", State: "win", Amount: "5.00", CreatedAt: now},
+		{TransactionID: "rollback:1", State: "win", Amount: "2.00", CreatedAt: now},
+		{TransactionID: "rollback:2", State: "lose", Amount: "5.00", CreatedAt: now},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "7.00", response.Bets)
+	assert.Equal(t, "7.00", response.Wins)
+	assert.Equal(t, "0.00", response.Net)
+	assert.Len(t, response.Transactions, 4)
+	assert.Equal(t, "rollback:2", response.Transactions[3].TransactionID)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/rules"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// RuleError is returned when a transaction rule rejects a transaction. Rule
+// tells which rule decided.
+type RuleError struct {
+	Rule rules.Rule
+}
+
+func (e *RuleError) Error() string {
+	return "transaction rejected by rule"
+}
+
+// checkRules evaluates the transaction rules. It returns the review rule that
+// holds the transaction, if any, and the flag rules that matched, which are
+// kept with a held transaction until it is booked. A matching
+// reject rule wins over a review rule, whatever their order in the rule file.
+// Adjustments have been approved by a second operator already, so a review
+// rule only flags them.
+func (s *UserService) checkRules(user *model.User, sourceType, state string, amount float64, now time.Time) (*rules.Rule, []rules.Rule, error) {
+	matched := s.rules.Evaluate(rules.Input{
+		UserID:           user.ID,
+		SourceType:       sourceType,
+		State:            state,
+		Amount:           amount,
+		Status:           user.Status,
+		AccountCreatedAt: user.CreatedAt,
+		At:               now,
+	})
+	return ruleDecision(matched, sourceType)
+}
+
+func ruleDecision(matched []rules.Rule, sourceType string) (*rules.Rule, []rules.Rule, error) {
+	var flags []rules.Rule
+	var review *rules.Rule
+	for i, rule := range matched {
+		switch rule.Action {
+		case rules.ActionReject:
+			return nil, nil, &RuleError{Rule: rule}
+		case rules.ActionReview:
+			if sourceType == "adjustment" {
+				flags = append(flags, rule)
+			} else if review == nil {
+				review = &matched[i]
+			}
+		case rules.ActionFlag:
+			flags = append(flags, rule)
+		}
+	}
+	return review, flags, nil
+}
+
+func ruleFlags(matched []rules.Rule) []model.TransactionFlag {
+	flags := make([]model.TransactionFlag, 0, len(matched))
+	for _, rule := range matched {
+		flags = append(flags, model.TransactionFlag{RuleID: rule.ID, Message: rule.Message})
+	}
+	return flags
+}
+
+// recordFlags stores the flags raised by rules and fraud checks on the booked
+// transaction.
+func (s *UserService) recordFlags(tx *gorm.DB, transaction *model.Transaction, flags []model.TransactionFlag, now time.Time) error {
+	for _, flag := range flags {
+		logrus.WithFields(logrus.Fields{
+			"userID":        transaction.UserID,
+			"transactionID": transaction.TransactionID,
+			"rule":          flag.RuleID,
+		}).Warn("Transaction flagged")
+
+		flag.TransactionID = transaction.ID
+		flag.UserID = transaction.UserID
+		flag.CreatedAt = now
+		if err := s.flagRepo.CreateFlag(tx, &flag); err != nil {
+			return fmt.Errorf("failed to flag transaction: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/lielamurs/balance-transactions/internal/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleDecision(t *testing.T) {
+	flag := rules.Rule{ID: "watched", Action: rules.ActionFlag}
+	review := rules.Rule{ID: "large-server", Action: rules.ActionReview}
+	reject := rules.Rule{ID: "max-win", Action: rules.ActionReject}
+
+	tests := []struct {
+		name          string
+		matched       []rules.Rule
+		sourceType    string
+		expectedFlags []string
+		expectedHeld  string
+		expectedRule  string
+		expectedError string
+	}{
+		{name: "no rules"},
+		{name: "flags only", matched: []rules.Rule{flag, {ID: "night", Action: rules.ActionFlag}}, expectedFlags: []string{"watched", "night"}},
+		{name: "review holds the transaction", matched: []rules.Rule{flag, review}, expectedHeld: "large-server", expectedFlags: []string{"watched"}},
+		{name: "reject wins over an earlier review", matched: []rules.Rule{review, flag, reject}, expectedRule: "max-win", expectedError: "transaction rejected by rule"},
+		{name: "review only flags an approved adjustment", matched: []rules.Rule{flag, review}, sourceType: "adjustment", expectedFlags: []string{"watched", "large-server"}},
+		{name: "reject still applies to an adjustment", matched: []rules.Rule{review, reject}, sourceType: "adjustment", expectedRule: "max-win", expectedError: "transaction rejected by rule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceType := tt.sourceType
+			if sourceType == "" {
+				sourceType = "server"
+			}
+			held, flags, err := ruleDecision(tt.matched, sourceType)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				var ruleErr *RuleError
+				require.ErrorAs(t, err, &ruleErr)
+				assert.Equal(t, tt.expectedRule, ruleErr.Rule.ID)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expectedHeld != "" {
+				require.NotNil(t, held)
+				assert.Equal(t, tt.expectedHeld, held.ID)
+			} else {
+				assert.Nil(t, held)
+			}
+			var ids []string
+			for _, rule := range flags {
+				ids = append(ids, rule.ID)
+			}
+			assert.Equal(t, tt.expectedFlags, ids)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/statement"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type StatementService struct {
+	statementRepo database.StatementRepository
+}
+
+func NewStatementService() *StatementService {
+	return &StatementService{
+		statementRepo: database.NewStatementRepository(),
+	}
+}
+
+// WriteStatement streams the user's statement for [from, to) to w. The
+// opening balance is derived backwards from the current balance, so balances
+// that predate the transaction history are still accounted for. The period is
+// cut at the last transaction created before from and before to, and walked
+// in ID order, the order transactions were applied in. Everything is read
+// from one snapshot so concurrent transactions cannot skew the totals.
+func (s *StatementService) WriteStatement(userID uint64, from, to time.Time, w statement.Writer) error {
+	logrus.WithFields(logrus.Fields{"userID": userID, "from": from, "to": to}).Info("Generating statement")
+
+	lines := 0
+	err := s.statementRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		user, err := s.statementRepo.GetUser(tx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		current, err := parseAmount(user.Balance)
+		if err != nil {
+			return fmt.Errorf("invalid current balance: %w", err)
+		}
+
+		openingID, err := s.statementRepo.LastTransactionIDBefore(tx, userID, from)
+		if err != nil {
+			return fmt.Errorf("failed to find opening transaction: %w", err)
+		}
+		closingID, err := s.statementRepo.LastTransactionIDBefore(tx, userID, to)
+		if err != nil {
+			return fmt.Errorf("failed to find closing transaction: %w", err)
+		}
+
+		netSince, err := s.statementRepo.NetTransactionsAfter(tx, userID, openingID)
+		if err != nil {
+			return fmt.Errorf("failed to sum transactions: %w", err)
+		}
+		net, err := parseAmount(netSince)
+		if err != nil {
+			return fmt.Errorf("invalid transaction sum: %w", err)
+		}
+
+		balance := roundAmount(current - net)
+		if err := w.WriteHeader(statement.Header{
+			UserID:         userID,
+			From:           from,
+			To:             to,
+			OpeningBalance: balance,
+			GeneratedAt:    time.Now().UTC(),
+		}); err != nil {
+			return fmt.Errorf("failed to write statement: %w", err)
+		}
+
+		totals := map[string]*statement.Total{}
+		err = s.statementRepo.EachTransaction(tx, userID, openingID, closingID, func(transaction model.Transaction) error {
+			amount, err := parseAmount(transaction.Amount)
+			if err != nil {
+				return fmt.Errorf("invalid amount on transaction %s: %w", transaction.TransactionID, err)
+			}
+
+			total, ok := totals[transaction.SourceType]
+			if !ok {
+				total = &statement.Total{SourceType: transaction.SourceType}
+				totals[transaction.SourceType] = total
+			}
+			if transaction.State == "win" {
+				balance = roundAmount(balance + amount)
+				total.Credits = roundAmount(total.Credits + amount)
+			} else {
+				balance = roundAmount(balance - amount)
+				total.Debits = roundAmount(total.Debits + amount)
+			}
+
+			lines++
+			return w.WriteLine(statement.Line{
+				CreatedAt:     transaction.CreatedAt,
+				TransactionID: transaction.TransactionID,
+				SourceType:    transaction.SourceType,
+				State:         transaction.State,
+				Amount:        amount,
+				Balance:       balance,
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write statement: %w", err)
+		}
+
+		return w.WriteSummary(statement.Summary{
+			Totals:         sortedTotals(totals),
+			ClosingBalance: balance,
+		})
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		if err.Error() != "user not found" {
+			logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to generate statement")
+		}
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{"userID": userID, "transactions": lines}).Info("Statement generated")
+	return nil
+}
+
+func sortedTotals(totals map[string]*statement.Total) []statement.Total {
+	sorted := make([]statement.Total, 0, len(totals))
+	for _, total := range totals {
+		sorted = append(sorted, *total)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].SourceType < sorted[j].SourceType
+	})
+	return sorted
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/stream"
+	"github.com/sirupsen/logrus"
+)
+
+const StreamEventBalance = "balance"
+
+// SubscribeBalance opens a live feed of the user's balance. A client resuming
+// from a Last-Event-ID that is still buffered gets the updates it missed;
+// otherwise the feed starts with a snapshot of the current balance.
+func (s *UserService) SubscribeBalance(userID, lastEventID uint64) (*stream.Subscription, []stream.Message, error) {
+	logrus.WithFields(logrus.Fields{"userID": userID, "lastEventID": lastEventID}).Info("Subscribing to balance stream")
+
+	subscription, replay, resumed := s.hub.Subscribe(userID, lastEventID)
+	if resumed {
+		return subscription, replay, nil
+	}
+
+	balance, err := s.GetBalance(userID)
+	if err != nil {
+		subscription.Close()
+		return nil, nil, err
+	}
+
+	data, err := json.Marshal(dto.BalanceUpdate{
+		UserID:     balance.UserID,
+		Balance:    balance.Balance,
+		Wallets:    balance.Wallets,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		subscription.Close()
+		return nil, nil, fmt.Errorf("failed to encode balance snapshot: %w", err)
+	}
+
+	return subscription, []stream.Message{{Event: StreamEventBalance, Data: data}}, nil
+}
+
+// balanceUpdate builds the stream message for a processed transaction. It is
+// keyed by the transaction's row ID, which increases per user because
+// transactions for a user are serialised by the row lock.
+func balanceUpdate(transaction *model.Transaction, balance float64, balances walletBalances, now time.Time) (stream.Message, error) {
+	data, err := json.Marshal(dto.BalanceUpdate{
+		UserID:  transaction.UserID,
+		Balance: formatAmount(balance),
+		Wallets: walletBalancesResponse(balances),
+		Transaction: &dto.TransactionSummary{
+			TransactionID: transaction.TransactionID,
+			State:         transaction.State,
+			Amount:        transaction.Amount,
+			SourceType:    transaction.SourceType,
+		},
+		OccurredAt: now,
+	})
+	if err != nil {
+		return stream.Message{}, fmt.Errorf("failed to encode balance update: %w", err)
+	}
+
+	return stream.Message{ID: transaction.ID, Event: StreamEventBalance, Data: data}, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// GetTransaction looks up a transaction by the ID its source submitted it
+// under. Only the source that submitted it can see it, and with a userID other
+// than zero only as a transaction of that user; anything else is reported as
+// not found so the lookup cannot be used to probe other sources' IDs.
+//
+// A booked transaction reports the user's balance right before and after it
+// was applied. A transaction held for review reports the review's status
+// instead.
+func (s *UserService) GetTransaction(transactionID, sourceType string, userID uint64) (*dto.TransactionLookupResponse, error) {
+	logrus.WithFields(logrus.Fields{"transactionID": transactionID, "sourceType": sourceType, "userID": userID}).Info("Looking up transaction")
+
+	var response *dto.TransactionLookupResponse
+	err := s.snapshotRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		transaction, err := s.userRepo.GetTransaction(tx, transactionID)
+		if err == nil {
+			if !visibleTo(transaction.SourceType, transaction.UserID, sourceType, userID) {
+				return errors.New("transaction not found")
+			}
+			response, err = s.bookedTransaction(tx, transaction)
+			return err
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+
+		review, err := s.reviewRepo.GetReviewByTransactionID(tx, transactionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("transaction not found")
+			}
+			return fmt.Errorf("failed to get held transaction: %w", err)
+		}
+		if !visibleTo(review.SourceType, review.UserID, sourceType, userID) {
+			return errors.New("transaction not found")
+		}
+		response = heldTransaction(review)
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		if err.Error() == "transaction not found" {
+			logrus.WithFields(logrus.Fields{"transactionID": transactionID, "sourceType": sourceType}).Warn("Transaction not found")
+		} else {
+			logrus.WithFields(logrus.Fields{"transactionID": transactionID, "error": err}).Error("Failed to look up transaction")
+		}
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *UserService) bookedTransaction(tx *gorm.DB, transaction *model.Transaction) (*dto.TransactionLookupResponse, error) {
+	before, after, err := s.transactionBalances(tx, transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TransactionLookupResponse{
+		TransactionID: transaction.TransactionID,
+		UserID:        transaction.UserID,
+		Status:        model.TransactionBooked,
+		State:         transaction.State,
+		Amount:        transaction.Amount,
+		SourceType:    transaction.SourceType,
+		BalanceBefore: before,
+		BalanceAfter:  after,
+		CreatedAt:     transaction.CreatedAt,
+	}, nil
+}
+
+// transactionBalances returns the balances stored with the transaction. Rows
+// booked before balances were stored, and not backfilled yet, are computed
+// from the history instead.
+func (s *UserService) transactionBalances(tx *gorm.DB, transaction *model.Transaction) (string, string, error) {
+	if transaction.BalanceBefore != nil && transaction.BalanceAfter != nil {
+		return *transaction.BalanceBefore, *transaction.BalanceAfter, nil
+	}
+
+	user, err := s.snapshotRepo.GetUser(tx, transaction.UserID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get user: %w", err)
+	}
+	after, err := s.balanceAt(tx, user, transaction.ID)
+	if err != nil {
+		return "", "", err
+	}
+	amount, err := parseAmount(transaction.Amount)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid transaction amount: %w", err)
+	}
+	return formatAmount(after - signedAmount(transaction.State, amount)), formatAmount(after), nil
+}
+
+// heldTransaction reports a transaction that was held for review and not
+// booked: pending until an operator decides, declined afterwards. An approved
+// review is booked in the same database transaction, so its transaction is
+// always found first.
+func heldTransaction(review *model.TransactionReview) *dto.TransactionLookupResponse {
+	status := model.TransactionPending
+	if review.Status == model.ReviewDeclined {
+		status = model.TransactionDeclined
+	}
+	return &dto.TransactionLookupResponse{
+		TransactionID: review.TransactionID,
+		UserID:        review.UserID,
+		Status:        status,
+		State:         review.State,
+		Amount:        review.Amount,
+		SourceType:    review.SourceType,
+		CreatedAt:     review.CreatedAt,
+		DecidedAt:     review.ReviewedAt,
+	}
+}
+
+func visibleTo(ownerSource string, ownerID uint64, sourceType string, userID uint64) bool {
+	return ownerSource == sourceType && (userID == 0 || ownerID == userID)
+}
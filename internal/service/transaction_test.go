@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisibleTo(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourceType string
+		userID     uint64
+		expected   bool
+	}{
+		{name: "submitting source", sourceType: "game", expected: true},
+		{name: "submitting source and user", sourceType: "game", userID: 7, expected: true},
+		{name: "other source", sourceType: "payment"},
+		{name: "other user", sourceType: "game", userID: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, visibleTo("game", 7, tt.sourceType, tt.userID))
+		})
+	}
+}
+
+func TestHeldTransaction(t *testing.T) {
+	held := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	decided := held.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		status    string
+		decidedAt *time.Time
+		expected  string
+	}{
+		{name: "pending review", status: model.ReviewPending, expected: model.TransactionPending},
+		{name: "declined review", status: model.ReviewDeclined, decidedAt: &decided, expected: model.TransactionDeclined},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review := &model.TransactionReview{
+				UserID: 7, TransactionID: "tx-77", SourceType: "server", State: "win", Amount: "2500.00",
+				Status: tt.status, ReviewedAt: tt.decidedAt, CreatedAt: held,
+			}
+
+			response := heldTransaction(review)
+			assert.Equal(t, tt.expected, response.Status)
+			assert.Equal(t, "tx-77", response.TransactionID)
+			assert.Empty(t, response.BalanceAfter)
+			assert.Equal(t, held, response.CreatedAt)
+			assert.Equal(t, tt.decidedAt, response.DecidedAt)
+		})
+	}
+}
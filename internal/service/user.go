@@ -4,21 +4,65 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
+	"github.com/lielamurs/balance-transactions/internal/config"
 	"github.com/lielamurs/balance-transactions/internal/database"
 	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/fraud"
+	"github.com/lielamurs/balance-transactions/internal/hashchain"
 	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/rules"
+	"github.com/lielamurs/balance-transactions/internal/stream"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type UserService struct {
-	userRepo database.UserRepository
+	userRepo      database.UserRepository
+	walletRepo    database.WalletRepository
+	bonusRepo     database.BonusRepository
+	limitRepo     database.LimitRepository
+	outboxRepo    database.OutboxRepository
+	snapshotRepo  database.SnapshotRepository
+	closingRepo   database.ClosingRepository
+	auditRepo     database.AuditRepository
+	roundRepo     database.RoundRepository
+	flagRepo      database.FlagRepository
+	reviewRepo    database.ReviewRepository
+	fraudRepo     database.FraudRepository
+	rules         *rules.Engine
+	fraudChecks   *fraud.Engine
+	hub           *stream.Hub
+	locking       string
+	maxAttempts   int
+	retryAttempts int
+	retryBase     time.Duration
+	retryMaxDelay time.Duration
 }
 
 func NewUserService() *UserService {
 	return &UserService{
-		userRepo: database.NewUserRepository(),
+		userRepo:      database.NewUserRepository(),
+		walletRepo:    database.NewWalletRepository(),
+		bonusRepo:     database.NewBonusRepository(),
+		limitRepo:     database.NewLimitRepository(),
+		outboxRepo:    database.NewOutboxRepository(),
+		snapshotRepo:  database.NewSnapshotRepository(),
+		closingRepo:   database.NewClosingRepository(),
+		auditRepo:     database.NewAuditRepository(),
+		roundRepo:     database.NewRoundRepository(),
+		flagRepo:      database.NewFlagRepository(),
+		reviewRepo:    database.NewReviewRepository(),
+		fraudRepo:     database.NewFraudRepository(),
+		rules:         rules.Default(),
+		fraudChecks:   fraud.Default(),
+		hub:           stream.Default(),
+		locking:       config.Get().BalanceLocking,
+		maxAttempts:   config.Get().OptimisticAttempts,
+		retryAttempts: config.Get().RetryAttempts,
+		retryBase:     config.Get().RetryBaseDelay,
+		retryMaxDelay: config.Get().RetryMaxDelay,
 	}
 }
 
@@ -41,15 +85,31 @@ func (s *UserService) GetBalance(userID uint64) (*dto.BalanceResponse, error) {
 		return nil, fmt.Errorf("invalid balance format: %w", err)
 	}
 
+	wallets, err := s.walletRepo.GetWallets(userID)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to get wallets")
+		return nil, fmt.Errorf("failed to get wallets: %w", err)
+	}
+
+	balances, err := walletBalancesFor(user, wallets)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Invalid wallet balance format")
+		return nil, fmt.Errorf("invalid wallet balance format: %w", err)
+	}
+
 	formattedBalance := formatAmount(balance)
 	logrus.WithFields(logrus.Fields{"userID": userID, "balance": formattedBalance}).Info("Balance retrieved successfully")
 	return &dto.BalanceResponse{
 		UserID:  user.ID,
 		Balance: formattedBalance,
+		Wallets: walletBalancesResponse(balances),
 	}, nil
 }
 
-func (s *UserService) ProcessTransaction(userID uint64, req dto.TransactionRequest, sourceType string) error {
+// ProcessTransaction books the transaction and returns
+// model.TransactionBooked, or model.TransactionPending when a rule or fraud
+// check held it for review.
+func (s *UserService) ProcessTransaction(userID uint64, req dto.TransactionRequest, sourceType string) (string, error) {
 	logrus.WithFields(logrus.Fields{
 		"userID":        userID,
 		"transactionID": req.TransactionID,
@@ -58,75 +118,340 @@ func (s *UserService) ProcessTransaction(userID uint64, req dto.TransactionReque
 		"sourceType":    sourceType,
 	}).Info("Starting transaction processing")
 
-	return s.userRepo.GetDB().Transaction(func(tx *gorm.DB) error {
-		exists, err := s.userRepo.TransactionExists(tx, req.TransactionID)
+	var result booking
+	err := s.withRetries(func() error {
+		return s.userRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+			var err error
+			result, err = s.processTransaction(tx, userID, req, sourceType, nil)
+			return err
+		})
+	})
+	if err != nil {
+		s.recordTransactionFailure(userID, req, sourceType, err)
+		return "", err
+	}
+	if result.review != nil {
+		return model.TransactionPending, nil
+	}
+
+	s.hub.Publish(userID, result.update)
+	return model.TransactionBooked, nil
+}
+
+// booking is what processTransaction did with a transaction: either booked it,
+// with the balance update to publish once the database transaction has
+// committed, or held it for review.
+type booking struct {
+	update stream.Message
+	review *model.TransactionReview
+}
+
+// processTransaction books the transaction inside tx and returns the balance
+// update to publish once tx has committed. Callers that wrap it in a larger
+// database transaction, such as adjustment approval, get the same checks as
+// ProcessTransaction. held is the review of a transaction an operator approved
+// after it was held: it was screened by the rules and fraud checks when it
+// was held and is not screened again, but the flags raised then are recorded.
+func (s *UserService) processTransaction(tx *gorm.DB, userID uint64, req dto.TransactionRequest, sourceType string, held *model.TransactionReview) (booking, error) {
+	exists, err := s.userRepo.TransactionExists(tx, req.TransactionID)
+	if err != nil {
+		return booking{}, fmt.Errorf("failed to check existing transaction: %w", err)
+	}
+	if !exists && held == nil {
+		exists, err = s.reviewRepo.ReviewExists(tx, req.TransactionID)
 		if err != nil {
-			return fmt.Errorf("failed to check existing transaction: %w", err)
+			return booking{}, fmt.Errorf("failed to check held transaction: %w", err)
 		}
-		if exists {
-			logrus.WithFields(logrus.Fields{"userID": userID, "transactionID": req.TransactionID}).Warn("Duplicate transaction detected")
-			return errors.New("transaction already processed")
+	}
+	if exists {
+		logrus.WithFields(logrus.Fields{"userID": userID, "transactionID": req.TransactionID}).Warn("Duplicate transaction detected")
+		return booking{}, errors.New("transaction already processed")
+	}
+
+	now := time.Now().UTC()
+	open, err := s.closingRepo.LockOpenPeriod(tx, now)
+	if err != nil {
+		return booking{}, fmt.Errorf("failed to check business day: %w", err)
+	}
+	if !open {
+		logrus.WithFields(logrus.Fields{"userID": userID, "transactionID": req.TransactionID, "at": now}).Warn("Transaction falls into a closed business day")
+		return booking{}, errors.New("business day closed")
+	}
+
+	user, err := s.lockUser(tx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) && autoProvisions(sourceType) {
+		user, err = s.provisionUser(tx, userID, sourceType)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.WithFields(logrus.Fields{"userID": userID, "transactionID": req.TransactionID}).Warn("User not found for transaction")
+			return booking{}, errors.New("user not found")
 		}
+		logrus.WithFields(logrus.Fields{"userID": userID, "transactionID": req.TransactionID, "error": err}).Error("Failed to get user for transaction")
+		return booking{}, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err := checkAccountPolicy(user, sourceType, req.State); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"userID":        userID,
+			"transactionID": req.TransactionID,
+			"status":        user.Status,
+			"sourceType":    sourceType,
+			"state":         req.State,
+		}).Warn("Transaction rejected by account status")
+		return booking{}, err
+	}
+
+	transactionAmount, err := parseAmount(req.Amount)
+	if err != nil {
+		return booking{}, fmt.Errorf("invalid transaction amount: %w", err)
+	}
 
-		user, err := s.userRepo.GetUserForUpdate(tx, userID)
+	var flags []model.TransactionFlag
+	if held == nil {
+		var review *model.TransactionReview
+		review, flags, err = s.screenTransaction(tx, user, req, sourceType, transactionAmount, now)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				logrus.WithFields(logrus.Fields{"userID": userID, "transactionID": req.TransactionID}).Warn("User not found for transaction")
-				return errors.New("user not found")
-			}
-			logrus.WithFields(logrus.Fields{"userID": userID, "transactionID": req.TransactionID, "error": err}).Error("Failed to get user for transaction")
-			return fmt.Errorf("failed to get user: %w", err)
+			return booking{}, err
 		}
+		if review != nil {
+			return booking{review: review}, nil
+		}
+	} else if flags, err = heldFlags(held); err != nil {
+		return booking{}, err
+	}
 
-		currentBalance, err := parseAmount(user.Balance)
-		if err != nil {
-			return fmt.Errorf("invalid current balance: %w", err)
+	round, err := s.enterRound(tx, userID, req, sourceType)
+	if err != nil {
+		return booking{}, err
+	}
+
+	wallets, err := s.lockWallets(tx, userID)
+	if err != nil {
+		return booking{}, fmt.Errorf("failed to get wallets: %w", err)
+	}
+
+	balances, err := walletBalancesFor(user, wallets)
+	if err != nil {
+		return booking{}, fmt.Errorf("invalid wallet balance: %w", err)
+	}
+
+	bonuses, err := s.lockActiveBonuses(tx, userID)
+	if err != nil {
+		return booking{}, fmt.Errorf("failed to get active bonuses: %w", err)
+	}
+
+	bonuses, _, err = s.expireBonuses(tx, user, balances, bonuses, now)
+	if err != nil {
+		return booking{}, err
+	}
+
+	currentBalance, err := parseAmount(user.Balance)
+	if err != nil {
+		return booking{}, fmt.Errorf("invalid current balance: %w", err)
+	}
+
+	if err := s.checkLimits(tx, userID, req.State, sourceType, transactionAmount, now); err != nil {
+		return booking{}, err
+	}
+
+	newBalance, err := calculateNewBalance(currentBalance, transactionAmount, req.State)
+	if err != nil {
+		if err.Error() == "insufficient balance" {
+			logrus.WithFields(logrus.Fields{
+				"userID":         userID,
+				"transactionID":  req.TransactionID,
+				"currentBalance": currentBalance,
+				"amount":         transactionAmount,
+			}).Warn("Insufficient balance for transaction")
 		}
+		return booking{}, err
+	}
 
-		transactionAmount, err := parseAmount(req.Amount)
-		if err != nil {
-			return fmt.Errorf("invalid transaction amount: %w", err)
+	walletChanges, err := walletDeltas(balances, transactionAmount, req.State, sourceType)
+	if err != nil {
+		if err.Error() == "insufficient balance" {
+			logrus.WithFields(logrus.Fields{
+				"userID":        userID,
+				"transactionID": req.TransactionID,
+				"sourceType":    sourceType,
+				"wallets":       balances,
+				"amount":        transactionAmount,
+			}).Warn("Insufficient wallet balance for source type")
 		}
+		return booking{}, err
+	}
 
-		newBalance, err := calculateNewBalance(currentBalance, transactionAmount, req.State)
-		if err != nil {
-			if err.Error() == "insufficient balance" {
-				logrus.WithFields(logrus.Fields{
-					"userID":         userID,
-					"transactionID":  req.TransactionID,
-					"currentBalance": currentBalance,
-					"amount":         transactionAmount,
-				}).Warn("Insufficient balance for transaction")
+	if err := s.updateBalance(tx, user, formatAmount(newBalance)); err != nil {
+		if errors.Is(err, errVersionConflict) {
+			return booking{}, err
+		}
+		return booking{}, fmt.Errorf("failed to update balance: %w", err)
+	}
+
+	transaction := &model.Transaction{
+		UserID:        userID,
+		TransactionID: req.TransactionID,
+		Amount:        req.Amount,
+		State:         req.State,
+		SourceType:    sourceType,
+	}
+	setBalances(transaction, currentBalance, newBalance)
+	if round != nil {
+		transaction.RoundID = &round.ID
+	}
+
+	if err := s.createTransaction(tx, transaction); err != nil {
+		logrus.WithFields(logrus.Fields{"userID": userID, "transactionID": req.TransactionID, "error": err}).Error("Failed to create transaction record")
+		return booking{}, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	if err := s.recordFlags(tx, transaction, flags, now); err != nil {
+		return booking{}, err
+	}
+
+	if round != nil && req.CloseRound {
+		if err := s.roundRepo.UpdateRoundStatus(tx, round.ID, model.RoundClosed, now); err != nil {
+			return booking{}, fmt.Errorf("failed to close round: %w", err)
+		}
+	}
+
+	entry := model.WalletEntry{UserID: userID, TransactionID: &transaction.ID}
+	if err := s.applyWalletDeltas(tx, entry, balances, walletChanges, bonuses); err != nil {
+		return booking{}, err
+	}
+
+	if credited := walletChanges[model.WalletBonus]; req.State == "win" && credited > 0 {
+		if err := s.grantBonus(tx, userID, transaction.ID, credited, now); err != nil {
+			return booking{}, err
+		}
+	}
+
+	if req.State == "lose" && sourceType == "game" && len(bonuses) > 0 {
+		if err := s.wagerBonuses(tx, userID, transaction.ID, balances, bonuses, transactionAmount); err != nil {
+			return booking{}, err
+		}
+	}
+
+	if err := s.recordBalanceChange(tx, transaction, currentBalance, newBalance, balances, now); err != nil {
+		return booking{}, err
+	}
+
+	if err := s.recordTransactionProcessed(tx, transaction, newBalance, now); err != nil {
+		return booking{}, err
+	}
+
+	update, err := balanceUpdate(transaction, newBalance, balances, now)
+	if err != nil {
+		return booking{}, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"userID":        userID,
+		"transactionID": req.TransactionID,
+		"oldBalance":    currentBalance,
+		"newBalance":    newBalance,
+		"wallets":       walletChanges,
+	}).Info("Transaction processed successfully")
+	return booking{update: update}, nil
+}
+
+// createTransaction links the transaction into the user's hash chain and
+// stores it. Callers hold the user row lock, taken up front or, in optimistic
+// mode, by the first balance update, so no other transaction of the user can
+// take the same place in the chain. CreatedAt is stamped here for the same
+// reason: taken under the lock, the user's transactions are created in the
+// order of their IDs.
+func (s *UserService) createTransaction(tx *gorm.DB, transaction *model.Transaction) error {
+	transaction.CreatedAt = time.Now().UTC()
+	previousHash, err := s.userRepo.GetLastTransactionHash(tx, transaction.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get previous transaction hash: %w", err)
+	}
+	if err := hashchain.Link(previousHash, transaction); err != nil {
+		return fmt.Errorf("failed to hash transaction: %w", err)
+	}
+	return s.userRepo.CreateTransaction(tx, transaction)
+}
+
+// setBalances records the user's total balance right before and right after
+// the transaction.
+func setBalances(transaction *model.Transaction, before, after float64) {
+	balanceBefore, balanceAfter := formatAmount(before), formatAmount(after)
+	transaction.BalanceBefore = &balanceBefore
+	transaction.BalanceAfter = &balanceAfter
+}
+
+// applyWalletDeltas writes the wallet balances changed by deltas and records
+// one wallet entry per changed wallet, copying the links from entry. A debit
+// of the bonus wallet that entry does not tie to a bonus is taken from the
+// active bonuses holding it, updated in place, with one entry per bonus.
+func (s *UserService) applyWalletDeltas(tx *gorm.DB, entry model.WalletEntry, balances walletBalances, deltas map[string]float64, bonuses []model.Bonus) error {
+	for _, walletType := range model.WalletTypes {
+		delta, ok := deltas[walletType]
+		if !ok || delta == 0 {
+			continue
+		}
+
+		balances[walletType] = roundAmount(balances[walletType] + delta)
+		if err := s.walletRepo.UpsertWalletBalance(tx, entry.UserID, walletType, formatAmount(balances[walletType])); err != nil {
+			return fmt.Errorf("failed to update %s wallet: %w", walletType, err)
+		}
+
+		walletEntry := entry
+		walletEntry.WalletType = walletType
+		if walletType == model.WalletBonus && delta < 0 && entry.BonusID == nil {
+			if err := s.debitBonuses(tx, walletEntry, bonuses, -delta); err != nil {
+				return err
 			}
-			return err
+			continue
 		}
 
-		newBalanceStr := formatAmount(newBalance)
-		if err := s.userRepo.UpdateUserBalance(tx, userID, newBalanceStr); err != nil {
-			return fmt.Errorf("failed to update balance: %w", err)
+		walletEntry.Amount = formatAmount(delta)
+		if err := s.walletRepo.CreateWalletEntry(tx, &walletEntry); err != nil {
+			return fmt.Errorf("failed to create wallet entry: %w", err)
 		}
+	}
+	return nil
+}
+
+// debitBonuses records a debit of the bonus wallet against the bonuses it is
+// taken from.
+func (s *UserService) debitBonuses(tx *gorm.DB, entry model.WalletEntry, bonuses []model.Bonus, amount float64) error {
+	taken, unowned, err := splitBonusDebit(bonuses, amount)
+	if err != nil {
+		return err
+	}
 
-		transaction := &model.Transaction{
-			UserID:        userID,
-			TransactionID: req.TransactionID,
-			Amount:        req.Amount,
-			State:         req.State,
-			SourceType:    sourceType,
+	for i := range bonuses {
+		if taken[i] == 0 {
+			continue
+		}
+		bonus := &bonuses[i]
+		held, err := parseAmount(bonus.Balance)
+		if err != nil {
+			return fmt.Errorf("invalid bonus balance: %w", err)
+		}
+		bonus.Balance = formatAmount(roundAmount(held - taken[i]))
+		if err := s.bonusRepo.UpdateBonus(tx, bonus); err != nil {
+			return fmt.Errorf("failed to update bonus: %w", err)
 		}
 
-		if err := s.userRepo.CreateTransaction(tx, transaction); err != nil {
-			logrus.WithFields(logrus.Fields{"userID": userID, "transactionID": req.TransactionID, "error": err}).Error("Failed to create transaction record")
-			return fmt.Errorf("failed to create transaction: %w", err)
+		bonusEntry := entry
+		bonusEntry.BonusID = &bonus.ID
+		bonusEntry.Amount = formatAmount(-taken[i])
+		if err := s.walletRepo.CreateWalletEntry(tx, &bonusEntry); err != nil {
+			return fmt.Errorf("failed to create wallet entry: %w", err)
 		}
+	}
 
-		logrus.WithFields(logrus.Fields{
-			"userID":        userID,
-			"transactionID": req.TransactionID,
-			"oldBalance":    currentBalance,
-			"newBalance":    newBalance,
-		}).Info("Transaction processed successfully")
-		return nil
-	})
+	if unowned > 0 {
+		entry.Amount = formatAmount(-unowned)
+		if err := s.walletRepo.CreateWalletEntry(tx, &entry); err != nil {
+			return fmt.Errorf("failed to create wallet entry: %w", err)
+		}
+	}
+	return nil
 }
 
 func parseAmount(amount string) (float64, error) {
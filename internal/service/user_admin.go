@@ -0,0 +1,159 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/lielamurs/balance-transactions/internal/config"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func (s *UserService) CreateUser(req dto.CreateUserRequest, actor Actor) (*dto.UserDetailsResponse, error) {
+	logrus.WithFields(logrus.Fields{"userID": req.ID, "externalRef": req.ExternalRef, "actor": actor.ID}).Info("Creating user")
+
+	metadata := "{}"
+	if len(req.Metadata) > 0 {
+		metadata = string(req.Metadata)
+	}
+
+	user := &model.User{
+		ExternalRef: req.ExternalRef,
+		Metadata:    metadata,
+		Balance:     formatAmount(0),
+		Status:      model.StatusActive,
+	}
+	if req.ID != nil {
+		user.ID = *req.ID
+	}
+
+	err := s.userRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.CreateUser(tx, user); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return errors.New("user already exists")
+			}
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+
+		if req.ID != nil {
+			if err := s.userRepo.AdvanceUserIDSequence(tx, user.ID); err != nil {
+				return fmt.Errorf("failed to advance user ID sequence: %w", err)
+			}
+		}
+
+		return recordAudit(tx, s.auditRepo, actor, AuditChange{
+			Action:       model.AuditUserCreate,
+			TargetUserID: &user.ID,
+			Resource:     fmt.Sprintf("users/%d", user.ID),
+			After:        userDetails(user),
+		})
+	})
+	if err != nil {
+		if err.Error() == "user already exists" {
+			logrus.WithFields(logrus.Fields{"userID": req.ID, "externalRef": req.ExternalRef}).Warn("User already exists")
+		} else {
+			logrus.WithFields(logrus.Fields{"userID": req.ID, "error": err}).Error("Failed to create user")
+		}
+		return nil, err
+	}
+
+	logrus.WithField("userID", user.ID).Info("User created successfully")
+	return s.GetUserDetails(user.ID)
+}
+
+func (s *UserService) GetUserDetails(userID uint64) (*dto.UserDetailsResponse, error) {
+	logrus.WithField("userID", userID).Info("Getting user details")
+
+	user, err := s.userRepo.GetUser(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.WithField("userID", userID).Warn("User not found")
+			return nil, errors.New("user not found")
+		}
+		logrus.WithFields(logrus.Fields{"userID": userID, "error": err}).Error("Failed to get user")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	details := userDetails(user)
+	return &details, nil
+}
+
+func (s *UserService) ListUsers(page, pageSize int) (*dto.UserListResponse, error) {
+	logrus.WithFields(logrus.Fields{"page": page, "pageSize": pageSize}).Info("Listing users")
+
+	users, total, err := s.userRepo.ListUsers((page-1)*pageSize, pageSize)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"page": page, "error": err}).Error("Failed to list users")
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	response := &dto.UserListResponse{
+		Users:    make([]dto.UserDetailsResponse, 0, len(users)),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}
+	for i := range users {
+		response.Users = append(response.Users, userDetails(&users[i]))
+	}
+
+	return response, nil
+}
+
+// provisionUser creates a user the first time a source type configured for
+// auto-provisioning sends a transaction for an unknown ID.
+func (s *UserService) provisionUser(tx *gorm.DB, userID uint64, sourceType string) (*model.User, error) {
+	metadata, err := json.Marshal(map[string]interface{}{
+		"autoProvisioned": true,
+		"sourceType":      sourceType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	user := &model.User{
+		ID:       userID,
+		Metadata: string(metadata),
+		Balance:  formatAmount(0),
+		Status:   model.StatusActive,
+	}
+	if err := s.userRepo.CreateUserIfMissing(tx, user); err != nil {
+		return nil, fmt.Errorf("failed to provision user: %w", err)
+	}
+	if err := s.userRepo.AdvanceUserIDSequence(tx, userID); err != nil {
+		return nil, fmt.Errorf("failed to advance user ID sequence: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{"userID": userID, "sourceType": sourceType}).Info("User auto-provisioned")
+	return s.userRepo.GetUserForUpdate(tx, userID)
+}
+
+func autoProvisions(sourceType string) bool {
+	for _, source := range config.Get().AutoProvisionSources {
+		if source == sourceType {
+			return true
+		}
+	}
+	return false
+}
+
+func userDetails(user *model.User) dto.UserDetailsResponse {
+	metadata := json.RawMessage(user.Metadata)
+	if len(metadata) == 0 {
+		metadata = json.RawMessage("{}")
+	}
+
+	return dto.UserDetailsResponse{
+		ID:            user.ID,
+		ExternalRef:   user.ExternalRef,
+		Metadata:      metadata,
+		Balance:       user.Balance,
+		Status:        user.Status,
+		ExcludedUntil: user.ExcludedUntil,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
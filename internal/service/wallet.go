@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"math"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+)
+
+type walletBalances map[string]float64
+
+var debitPriority = map[string][]string{
+	"game":       {model.WalletCash, model.WalletLocked, model.WalletBonus},
+	"server":     {model.WalletBonus, model.WalletCash, model.WalletLocked},
+	"payment":    {model.WalletCash},
+	"adjustment": {model.WalletCash},
+}
+
+// walletBalancesFor builds the per-wallet view of a user. Users that predate
+// wallets have no cash row, so their cash is whatever part of the total is not
+// held in the other wallets.
+func walletBalancesFor(user *model.User, wallets []model.Wallet) (walletBalances, error) {
+	balances := walletBalances{}
+	hasCash := false
+	for _, wallet := range wallets {
+		amount, err := parseAmount(wallet.Balance)
+		if err != nil {
+			return nil, err
+		}
+		balances[wallet.Type] = amount
+		if wallet.Type == model.WalletCash {
+			hasCash = true
+		}
+	}
+
+	if !hasCash {
+		total, err := parseAmount(user.Balance)
+		if err != nil {
+			return nil, err
+		}
+		balances[model.WalletCash] = roundAmount(total - balances[model.WalletBonus] - balances[model.WalletLocked])
+	}
+
+	for _, walletType := range model.WalletTypes {
+		if _, ok := balances[walletType]; !ok {
+			balances[walletType] = 0
+		}
+	}
+
+	return balances, nil
+}
+
+func creditWalletType(sourceType string, balances walletBalances) string {
+	switch sourceType {
+	case "server":
+		return model.WalletBonus
+	case "game":
+		if balances[model.WalletBonus] > 0 {
+			return model.WalletLocked
+		}
+		return model.WalletCash
+	default:
+		return model.WalletCash
+	}
+}
+
+func allocateDebit(balances walletBalances, sourceType string, amount float64) (map[string]float64, error) {
+	order, ok := debitPriority[sourceType]
+	if !ok {
+		order = []string{model.WalletCash}
+	}
+
+	deltas := map[string]float64{}
+	remaining := amount
+	for _, walletType := range order {
+		if remaining <= 0 {
+			break
+		}
+		available := balances[walletType]
+		if available <= 0 {
+			continue
+		}
+		take := math.Min(available, remaining)
+		deltas[walletType] = -take
+		remaining = roundAmount(remaining - take)
+	}
+
+	if remaining > 0 {
+		return nil, errors.New("insufficient balance")
+	}
+
+	return deltas, nil
+}
+
+func walletDeltas(balances walletBalances, amount float64, state, sourceType string) (map[string]float64, error) {
+	switch state {
+	case "win":
+		return map[string]float64{creditWalletType(sourceType, balances): amount}, nil
+	case "lose":
+		return allocateDebit(balances, sourceType, amount)
+	default:
+		return nil, errors.New("invalid transaction state")
+	}
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
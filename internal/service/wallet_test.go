@@ -0,0 +1,196 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalletBalancesFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    *model.User
+		wallets []model.Wallet
+		want    walletBalances
+		wantErr bool
+	}{
+		{
+			name: "all wallets present",
+			user: &model.User{Balance: "60.00"},
+			wallets: []model.Wallet{
+				{Type: model.WalletCash, Balance: "10.00"},
+				{Type: model.WalletBonus, Balance: "30.00"},
+				{Type: model.WalletLocked, Balance: "20.00"},
+			},
+			want: walletBalances{model.WalletCash: 10, model.WalletBonus: 30, model.WalletLocked: 20},
+		},
+		{
+			name:    "legacy user without wallets",
+			user:    &model.User{Balance: "42.50"},
+			wallets: nil,
+			want:    walletBalances{model.WalletCash: 42.5, model.WalletBonus: 0, model.WalletLocked: 0},
+		},
+		{
+			name: "missing cash wallet takes the remainder",
+			user: &model.User{Balance: "50.00"},
+			wallets: []model.Wallet{
+				{Type: model.WalletBonus, Balance: "15.25"},
+			},
+			want: walletBalances{model.WalletCash: 34.75, model.WalletBonus: 15.25, model.WalletLocked: 0},
+		},
+		{
+			name: "invalid wallet balance",
+			user: &model.User{Balance: "50.00"},
+			wallets: []model.Wallet{
+				{Type: model.WalletBonus, Balance: "abc"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid user balance",
+			user:    &model.User{Balance: "abc"},
+			wallets: nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := walletBalancesFor(tt.user, tt.wallets)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCreditWalletType(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourceType string
+		balances   walletBalances
+		want       string
+	}{
+		{
+			name:       "payment credits cash",
+			sourceType: "payment",
+			balances:   walletBalances{model.WalletBonus: 10},
+			want:       model.WalletCash,
+		},
+		{
+			name:       "server credits bonus",
+			sourceType: "server",
+			balances:   walletBalances{},
+			want:       model.WalletBonus,
+		},
+		{
+			name:       "game credits cash without bonus",
+			sourceType: "game",
+			balances:   walletBalances{model.WalletCash: 10},
+			want:       model.WalletCash,
+		},
+		{
+			name:       "game credits locked while bonus is active",
+			sourceType: "game",
+			balances:   walletBalances{model.WalletBonus: 0.01},
+			want:       model.WalletLocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, creditWalletType(tt.sourceType, tt.balances))
+		})
+	}
+}
+
+func TestAllocateDebit(t *testing.T) {
+	tests := []struct {
+		name          string
+		balances      walletBalances
+		sourceType    string
+		amount        float64
+		want          map[string]float64
+		wantErr       bool
+		expectedError string
+	}{
+		{
+			name:       "game debits cash first",
+			balances:   walletBalances{model.WalletCash: 50, model.WalletLocked: 20, model.WalletBonus: 30},
+			sourceType: "game",
+			amount:     40,
+			want:       map[string]float64{model.WalletCash: -40},
+		},
+		{
+			name:       "game spills over into locked then bonus",
+			balances:   walletBalances{model.WalletCash: 10, model.WalletLocked: 5.5, model.WalletBonus: 30},
+			sourceType: "game",
+			amount:     20,
+			want:       map[string]float64{model.WalletCash: -10, model.WalletLocked: -5.5, model.WalletBonus: -4.5},
+		},
+		{
+			name:       "server debits bonus first",
+			balances:   walletBalances{model.WalletCash: 50, model.WalletBonus: 30},
+			sourceType: "server",
+			amount:     35,
+			want:       map[string]float64{model.WalletBonus: -30, model.WalletCash: -5},
+		},
+		{
+			name:       "payment debits cash only",
+			balances:   walletBalances{model.WalletCash: 25, model.WalletBonus: 100},
+			sourceType: "payment",
+			amount:     25,
+			want:       map[string]float64{model.WalletCash: -25},
+		},
+		{
+			name:          "payment cannot spend bonus funds",
+			balances:      walletBalances{model.WalletCash: 25, model.WalletBonus: 100},
+			sourceType:    "payment",
+			amount:        25.01,
+			wantErr:       true,
+			expectedError: "insufficient balance",
+		},
+		{
+			name:          "game exceeds all wallets",
+			balances:      walletBalances{model.WalletCash: 1, model.WalletLocked: 1, model.WalletBonus: 1},
+			sourceType:    "game",
+			amount:        3.01,
+			wantErr:       true,
+			expectedError: "insufficient balance",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := allocateDebit(tt.balances, tt.sourceType, tt.amount)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.expectedError != "" {
+					assert.EqualError(t, err, tt.expectedError)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWalletDeltas(t *testing.T) {
+	balances := walletBalances{model.WalletCash: 10, model.WalletBonus: 5, model.WalletLocked: 0}
+
+	got, err := walletDeltas(balances, 7.5, "win", "game")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{model.WalletLocked: 7.5}, got)
+
+	got, err = walletDeltas(balances, 12, "lose", "game")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{model.WalletCash: -10, model.WalletBonus: -2}, got)
+
+	_, err = walletDeltas(balances, 1, "invalid", "game")
+	assert.EqualError(t, err, "invalid transaction state")
+}
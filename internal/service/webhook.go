@@ -0,0 +1,224 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lielamurs/balance-transactions/internal/database"
+	"github.com/lielamurs/balance-transactions/internal/dto"
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const deliveryListLimit = 100
+
+type WebhookService struct {
+	webhookRepo database.WebhookRepository
+	auditRepo   database.AuditRepository
+}
+
+func NewWebhookService() *WebhookService {
+	return &WebhookService{
+		webhookRepo: database.NewWebhookRepository(),
+		auditRepo:   database.NewAuditRepository(),
+	}
+}
+
+// CreateSubscription registers a webhook endpoint. The secret is generated
+// when not supplied and is only returned in this response.
+func (s *WebhookService) CreateSubscription(req dto.CreateWebhookRequest, actor Actor) (*dto.WebhookResponse, error) {
+	logrus.WithFields(logrus.Fields{"url": req.URL, "eventTypes": req.EventTypes, "actor": actor.ID}).Info("Creating webhook subscription")
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateSecret()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+		secret = generated
+	}
+
+	subscription := &model.WebhookSubscription{
+		URL:        req.URL,
+		EventTypes: strings.Join(req.EventTypes, ","),
+		Secret:     secret,
+		Active:     true,
+		CreatedBy:  actor.ID,
+	}
+	err := s.webhookRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := s.webhookRepo.CreateSubscription(tx, subscription); err != nil {
+			return fmt.Errorf("failed to create subscription: %w", err)
+		}
+
+		// The secret never goes into the audit log.
+		return recordAudit(tx, s.auditRepo, actor, AuditChange{
+			Action:   model.AuditWebhookCreate,
+			Resource: fmt.Sprintf("webhooks/%d", subscription.ID),
+			After:    webhookResponse(*subscription),
+		})
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"url": req.URL, "error": err}).Error("Failed to create webhook subscription")
+		return nil, err
+	}
+
+	response := webhookResponse(*subscription)
+	response.Secret = secret
+	return &response, nil
+}
+
+func (s *WebhookService) ListSubscriptions() (*dto.WebhookListResponse, error) {
+	subscriptions, err := s.webhookRepo.GetSubscriptions()
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to list webhook subscriptions")
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	response := &dto.WebhookListResponse{Webhooks: make([]dto.WebhookResponse, 0, len(subscriptions))}
+	for _, subscription := range subscriptions {
+		response.Webhooks = append(response.Webhooks, webhookResponse(subscription))
+	}
+	return response, nil
+}
+
+func (s *WebhookService) DeleteSubscription(subscriptionID uint64, actor Actor) error {
+	logrus.WithFields(logrus.Fields{"subscriptionID": subscriptionID, "actor": actor.ID}).Info("Deactivating webhook subscription")
+
+	err := s.webhookRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		subscription, err := s.webhookRepo.GetSubscriptionForUpdate(tx, subscriptionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("webhook not found")
+			}
+			return fmt.Errorf("failed to get subscription: %w", err)
+		}
+		if !subscription.Active {
+			return errors.New("webhook not found")
+		}
+
+		if _, err := s.webhookRepo.DeactivateSubscription(tx, subscriptionID); err != nil {
+			return fmt.Errorf("failed to deactivate subscription: %w", err)
+		}
+
+		before := webhookResponse(*subscription)
+		after := before
+		after.Active = false
+		return recordAudit(tx, s.auditRepo, actor, AuditChange{
+			Action:   model.AuditWebhookDelete,
+			Resource: fmt.Sprintf("webhooks/%d", subscriptionID),
+			Before:   before,
+			After:    after,
+		})
+	})
+	if err != nil && err.Error() != "webhook not found" {
+		logrus.WithFields(logrus.Fields{"subscriptionID": subscriptionID, "error": err}).Error("Failed to deactivate webhook subscription")
+	}
+	return err
+}
+
+// ListDeliveries returns the most recent deliveries, optionally filtered by
+// status. Listing status "dead" gives the dead-letter view.
+func (s *WebhookService) ListDeliveries(status string, subscriptionID uint64) (*dto.WebhookDeliveryListResponse, error) {
+	deliveries, err := s.webhookRepo.GetDeliveries(status, subscriptionID, deliveryListLimit)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"status": status, "error": err}).Error("Failed to list webhook deliveries")
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	response := &dto.WebhookDeliveryListResponse{Deliveries: make([]dto.WebhookDeliveryResponse, 0, len(deliveries))}
+	for _, delivery := range deliveries {
+		response.Deliveries = append(response.Deliveries, deliveryResponse(delivery))
+	}
+	return response, nil
+}
+
+// Redeliver queues a delivery again with a fresh attempt budget, whatever its
+// current status.
+func (s *WebhookService) Redeliver(deliveryID uint64, actor Actor) (*dto.WebhookDeliveryResponse, error) {
+	logrus.WithFields(logrus.Fields{"deliveryID": deliveryID, "actor": actor.ID}).Info("Redelivering webhook")
+
+	var response dto.WebhookDeliveryResponse
+	err := s.webhookRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		before, err := s.webhookRepo.GetDeliveryForUpdate(tx, deliveryID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("delivery not found")
+			}
+			return fmt.Errorf("failed to get delivery: %w", err)
+		}
+
+		if err := s.webhookRepo.ResetDelivery(tx, deliveryID); err != nil {
+			logrus.WithFields(logrus.Fields{"deliveryID": deliveryID, "error": err}).Error("Failed to reset webhook delivery")
+			return fmt.Errorf("failed to reset delivery: %w", err)
+		}
+
+		after, err := s.webhookRepo.GetDeliveryForUpdate(tx, deliveryID)
+		if err != nil {
+			return fmt.Errorf("failed to get delivery: %w", err)
+		}
+
+		response = deliveryResponse(*after)
+		return recordAudit(tx, s.auditRepo, actor, AuditChange{
+			Action:   model.AuditWebhookRedeliver,
+			Resource: fmt.Sprintf("webhook_deliveries/%d", deliveryID),
+			Before:   deliveryAuditState(*before),
+			After:    deliveryAuditState(*after),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// deliveryAuditState is the part of a delivery a redelivery changes; the
+// payload is left out because it is already kept on the delivery itself.
+func deliveryAuditState(delivery model.WebhookDelivery) map[string]interface{} {
+	return map[string]interface{}{
+		"status":        delivery.Status,
+		"attempts":      delivery.Attempts,
+		"nextAttemptAt": delivery.NextAttemptAt,
+	}
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func webhookResponse(subscription model.WebhookSubscription) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: strings.Split(subscription.EventTypes, ","),
+		Active:     subscription.Active,
+		CreatedBy:  subscription.CreatedBy,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+func deliveryResponse(delivery model.WebhookDelivery) dto.WebhookDeliveryResponse {
+	return dto.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventType:      delivery.EventType,
+		DedupeKey:      delivery.DedupeKey,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		Payload:        json.RawMessage(delivery.Payload),
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
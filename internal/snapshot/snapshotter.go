@@ -0,0 +1,60 @@
+package snapshot
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Store is the part of the snapshot repository the snapshotter depends on.
+type Store interface {
+	CreateSnapshots() (int64, error)
+}
+
+// Snapshotter periodically records every user's balance so that historical
+// balances can be computed from the nearest snapshot instead of the full
+// transaction history.
+type Snapshotter struct {
+	store    Store
+	interval time.Duration
+}
+
+func NewSnapshotter(store Store, interval time.Duration) *Snapshotter {
+	return &Snapshotter{
+		store:    store,
+		interval: interval,
+	}
+}
+
+func (s *Snapshotter) Run(ctx context.Context) {
+	logrus.WithField("interval", s.interval).Info("Balance snapshotter started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.TakeSnapshots()
+
+		select {
+		case <-ctx.Done():
+			logrus.Info("Balance snapshotter stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// TakeSnapshots snapshots the users whose balance changed since their last
+// snapshot and returns how many snapshots were written.
+func (s *Snapshotter) TakeSnapshots() int64 {
+	created, err := s.store.CreateSnapshots()
+	if err != nil {
+		logrus.WithField("error", err).Error("Failed to take balance snapshots")
+		return 0
+	}
+	if created > 0 {
+		logrus.WithField("snapshots", created).Info("Balance snapshots taken")
+	}
+	return created
+}
@@ -0,0 +1,72 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	mu      sync.Mutex
+	calls   int
+	created int64
+	err     error
+}
+
+func (f *fakeStore) CreateSnapshots() (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return f.created, f.err
+}
+
+func (f *fakeStore) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func TestTakeSnapshots(t *testing.T) {
+	tests := []struct {
+		name     string
+		store    *fakeStore
+		expected int64
+	}{
+		{name: "snapshots written", store: &fakeStore{created: 3}, expected: 3},
+		{name: "nothing changed", store: &fakeStore{}, expected: 0},
+		{name: "store failure", store: &fakeStore{created: 3, err: errors.New("connection refused")}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshotter := NewSnapshotter(tt.store, time.Hour)
+			assert.Equal(t, tt.expected, snapshotter.TakeSnapshots())
+			assert.Equal(t, 1, tt.store.Calls())
+		})
+	}
+}
+
+func TestRunSnapshotsImmediatelyAndOnEveryTick(t *testing.T) {
+	store := &fakeStore{}
+	snapshotter := NewSnapshotter(store, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		snapshotter.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return store.Calls() >= 3 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("snapshotter did not stop after the context was cancelled")
+	}
+}
@@ -0,0 +1,46 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+)
+
+// csvWriter writes one record per row with a type column, so the opening
+// balance, transactions, totals and closing balance share one table.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteHeader(header Header) error {
+	c.w.Write([]string{"type", "created_at", "transaction_id", "source_type", "state", "amount", "balance"})
+	c.w.Write([]string{"opening", header.From.Format(time.RFC3339), "", "", "", "", formatAmount(header.OpeningBalance)})
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) WriteLine(line Line) error {
+	return c.w.Write([]string{
+		"transaction",
+		line.CreatedAt.Format(time.RFC3339),
+		line.TransactionID,
+		line.SourceType,
+		line.State,
+		formatAmount(signedAmount(line)),
+		formatAmount(line.Balance),
+	})
+}
+
+func (c *csvWriter) WriteSummary(summary Summary) error {
+	for _, total := range summary.Totals {
+		c.w.Write([]string{"total", "", "", total.SourceType, "win", formatAmount(total.Credits), ""})
+		c.w.Write([]string{"total", "", "", total.SourceType, "lose", formatAmount(-total.Debits), ""})
+	}
+	c.w.Write([]string{"closing", "", "", "", "", "", formatAmount(summary.ClosingBalance)})
+	c.w.Flush()
+	return c.w.Error()
+}
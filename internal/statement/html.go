@@ -0,0 +1,88 @@
+package statement
+
+import (
+	"bufio"
+	"html/template"
+	"io"
+)
+
+// The HTML statement is print-ready: the page size and margins are set for
+// A4 so "Save as PDF" from a browser gives the document Finance files.
+var htmlTemplates = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"amount": formatAmount,
+	"signed": func(line Line) string { return formatAmount(signedAmount(line)) },
+	"date":   func(t interface{ Format(string) string }) string { return t.Format("2006-01-02 15:04:05 MST") },
+}).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Statement for user {{.UserID}}</title>
+<style>
+  @page { size: A4; margin: 18mm 15mm; }
+  body { font-family: Helvetica, Arial, sans-serif; font-size: 10pt; color: #222; }
+  h1 { font-size: 16pt; margin-bottom: 2mm; }
+  table { width: 100%; border-collapse: collapse; margin-top: 6mm; }
+  thead { display: table-header-group; }
+  tr { page-break-inside: avoid; }
+  th, td { padding: 1.5mm 2mm; border-bottom: 0.2mm solid #ccc; text-align: left; }
+  td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+  .meta td { border: none; padding: 0.5mm 2mm 0.5mm 0; }
+</style>
+</head>
+<body>
+<h1>Account statement</h1>
+<table class="meta">
+  <tr><td>User</td><td>{{.UserID}}</td></tr>
+  <tr><td>Period</td><td>{{date .From}} to {{date .To}}</td></tr>
+  <tr><td>Generated</td><td>{{date .GeneratedAt}}</td></tr>
+  <tr><td>Opening balance</td><td>{{amount .OpeningBalance}}</td></tr>
+</table>
+<table>
+<thead>
+  <tr><th>Date</th><th>Transaction</th><th>Source</th><th>State</th><th class="num">Amount</th><th class="num">Balance</th></tr>
+</thead>
+<tbody>
+{{end}}
+{{define "line"}}  <tr><td>{{date .CreatedAt}}</td><td>{{.TransactionID}}</td><td>{{.SourceType}}</td><td>{{.State}}</td><td class="num">{{signed .}}</td><td class="num">{{amount .Balance}}</td></tr>
+{{end}}
+{{define "summary"}}</tbody>
+</table>
+<table>
+<thead>
+  <tr><th>Source</th><th class="num">Credits</th><th class="num">Debits</th><th class="num">Net</th></tr>
+</thead>
+<tbody>
+{{range .Totals}}  <tr><td>{{.SourceType}}</td><td class="num">{{amount .Credits}}</td><td class="num">{{amount .Debits}}</td><td class="num">{{amount .Net}}</td></tr>
+{{end}}</tbody>
+</table>
+<p><strong>Closing balance: {{amount .ClosingBalance}}</strong></p>
+</body>
+</html>
+{{end}}`))
+
+type htmlWriter struct {
+	w *bufio.Writer
+}
+
+func newHTMLWriter(w io.Writer) *htmlWriter {
+	return &htmlWriter{w: bufio.NewWriter(w)}
+}
+
+func (h *htmlWriter) WriteHeader(header Header) error {
+	if err := htmlTemplates.ExecuteTemplate(h.w, "header", header); err != nil {
+		return err
+	}
+	return h.w.Flush()
+}
+
+func (h *htmlWriter) WriteLine(line Line) error {
+	return htmlTemplates.ExecuteTemplate(h.w, "line", line)
+}
+
+func (h *htmlWriter) WriteSummary(summary Summary) error {
+	if err := htmlTemplates.ExecuteTemplate(h.w, "summary", summary); err != nil {
+		return err
+	}
+	return h.w.Flush()
+}
@@ -0,0 +1,112 @@
+package statement
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+type jsonLine struct {
+	CreatedAt     time.Time `json:"createdAt"`
+	TransactionID string    `json:"transactionId"`
+	SourceType    string    `json:"sourceType"`
+	State         string    `json:"state"`
+	Amount        string    `json:"amount"`
+	Balance       string    `json:"balance"`
+}
+
+type jsonTotal struct {
+	SourceType string `json:"sourceType"`
+	Credits    string `json:"credits"`
+	Debits     string `json:"debits"`
+	Net        string `json:"net"`
+}
+
+// jsonWriter streams a single JSON object, writing the transactions array
+// element by element.
+type jsonWriter struct {
+	w     *bufio.Writer
+	lines int
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{w: bufio.NewWriter(w)}
+}
+
+func (j *jsonWriter) WriteHeader(header Header) error {
+	fields := []struct {
+		key   string
+		value interface{}
+	}{
+		{"userId", header.UserID},
+		{"from", header.From},
+		{"to", header.To},
+		{"generatedAt", header.GeneratedAt},
+		{"openingBalance", formatAmount(header.OpeningBalance)},
+	}
+
+	j.w.WriteString("{")
+	for _, field := range fields {
+		if err := j.writeField(field.key, field.value); err != nil {
+			return err
+		}
+		j.w.WriteString(",")
+	}
+	j.w.WriteString(`"transactions":[`)
+	return j.w.Flush()
+}
+
+func (j *jsonWriter) WriteLine(line Line) error {
+	if j.lines > 0 {
+		j.w.WriteString(",")
+	}
+	j.lines++
+
+	encoded, err := json.Marshal(jsonLine{
+		CreatedAt:     line.CreatedAt,
+		TransactionID: line.TransactionID,
+		SourceType:    line.SourceType,
+		State:         line.State,
+		Amount:        formatAmount(signedAmount(line)),
+		Balance:       formatAmount(line.Balance),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = j.w.Write(encoded)
+	return err
+}
+
+func (j *jsonWriter) WriteSummary(summary Summary) error {
+	totals := make([]jsonTotal, 0, len(summary.Totals))
+	for _, total := range summary.Totals {
+		totals = append(totals, jsonTotal{
+			SourceType: total.SourceType,
+			Credits:    formatAmount(total.Credits),
+			Debits:     formatAmount(total.Debits),
+			Net:        formatAmount(total.Net()),
+		})
+	}
+
+	j.w.WriteString("],")
+	if err := j.writeField("totals", totals); err != nil {
+		return err
+	}
+	j.w.WriteString(",")
+	if err := j.writeField("closingBalance", formatAmount(summary.ClosingBalance)); err != nil {
+		return err
+	}
+	j.w.WriteString("}\n")
+	return j.w.Flush()
+}
+
+func (j *jsonWriter) writeField(key string, value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	j.w.WriteString(`"` + key + `":`)
+	_, err = j.w.Write(encoded)
+	return err
+}
@@ -0,0 +1,137 @@
+// Package statement renders account statements. Writers receive the header,
+// then one line per transaction, then the summary, so a statement can be
+// streamed without holding the whole history in memory.
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatHTML = "html"
+)
+
+var Formats = []string{FormatCSV, FormatJSON, FormatHTML}
+
+type Header struct {
+	UserID         uint64
+	From           time.Time
+	To             time.Time
+	OpeningBalance float64
+	GeneratedAt    time.Time
+}
+
+type Line struct {
+	CreatedAt     time.Time
+	TransactionID string
+	SourceType    string
+	State         string
+	Amount        float64
+	Balance       float64
+}
+
+type Total struct {
+	SourceType string
+	Credits    float64
+	Debits     float64
+}
+
+func (t Total) Net() float64 {
+	return t.Credits - t.Debits
+}
+
+type Summary struct {
+	Totals         []Total
+	ClosingBalance float64
+}
+
+type Writer interface {
+	WriteHeader(header Header) error
+	WriteLine(line Line) error
+	WriteSummary(summary Summary) error
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatJSON:
+		return newJSONWriter(w), nil
+	case FormatHTML:
+		return newHTMLWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unknown statement format %q", format)
+	}
+}
+
+// ContentType returns the MIME type of a statement format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// ParseRange reads the from and to bounds of a statement. Both accept a
+// date (2006-01-02) or an RFC 3339 timestamp. The range is half-open, and a
+// date given as to includes that whole day.
+func ParseRange(fromValue, toValue string) (time.Time, time.Time, error) {
+	if fromValue == "" || toValue == "" {
+		return time.Time{}, time.Time{}, errors.New("from and to are required")
+	}
+
+	from, _, err := parseBound(fromValue)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+	}
+
+	to, isDate, err := parseBound(toValue)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+	}
+	if isDate {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, errors.New("to must be after from")
+	}
+	return from, to, nil
+}
+
+func parseBound(value string) (time.Time, bool, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, true, nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, errors.New("must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+	}
+	return timestamp.UTC(), false, nil
+}
+
+// formatAmount renders two decimals; a zero debit prints as 0.00, not -0.00.
+func formatAmount(amount float64) string {
+	if amount == 0 {
+		amount = 0
+	}
+	return fmt.Sprintf("%.2f", amount)
+}
+
+// signedAmount shows debits as negative amounts.
+func signedAmount(line Line) float64 {
+	if line.State == "lose" {
+		return -line.Amount
+	}
+	return line.Amount
+}
@@ -0,0 +1,166 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name         string
+		from         string
+		to           string
+		expectedFrom time.Time
+		expectedTo   time.Time
+		expectError  bool
+	}{
+		{
+			name:         "dates include the whole last day",
+			from:         "2025-06-01",
+			to:           "2025-06-30",
+			expectedFrom: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:   time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "timestamps are exclusive and normalised to UTC",
+			from:         "2025-06-01T00:00:00+02:00",
+			to:           "2025-06-02T12:00:00Z",
+			expectedFrom: time.Date(2025, 5, 31, 22, 0, 0, 0, time.UTC),
+			expectedTo:   time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "single day",
+			from:         "2025-06-01",
+			to:           "2025-06-01",
+			expectedFrom: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:   time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{name: "missing to", from: "2025-06-01", expectError: true},
+		{name: "bad format", from: "06/01/2025", to: "2025-06-30", expectError: true},
+		{name: "reversed", from: "2025-06-30T00:00:00Z", to: "2025-06-01T00:00:00Z", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := ParseRange(tt.from, tt.to)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFrom, from)
+			assert.Equal(t, tt.expectedTo, to)
+		})
+	}
+}
+
+func writeSample(t *testing.T, format string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(format, &buf)
+	require.NoError(t, err)
+
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, writer.WriteHeader(Header{
+		UserID:         7,
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		OpeningBalance: 100,
+		GeneratedAt:    from.AddDate(0, 1, 1),
+	}))
+	require.NoError(t, writer.WriteLine(Line{
+		CreatedAt: from.Add(time.Hour), TransactionID: "tx-1", SourceType: "payment", State: "win", Amount: 50, Balance: 150,
+	}))
+	require.NoError(t, writer.WriteLine(Line{
+		CreatedAt: from.Add(2 * time.Hour), TransactionID: "<tx-2>", SourceType: "game", State: "lose", Amount: 20.5, Balance: 129.5,
+	}))
+	require.NoError(t, writer.WriteSummary(Summary{
+		Totals: []Total{
+			{SourceType: "game", Debits: 20.5},
+			{SourceType: "payment", Credits: 50},
+		},
+		ClosingBalance: 129.5,
+	}))
+	return buf.String()
+}
+
+func TestCSVWriter(t *testing.T) {
+	expected := strings.Join([]string{
+		"type,created_at,transaction_id,source_type,state,amount,balance",
+		"opening,2025-06-01T00:00:00Z,,,,,100.00",
+		"transaction,2025-06-01T01:00:00Z,tx-1,payment,win,50.00,150.00",
+		"transaction,2025-06-01T02:00:00Z,<tx-2>,game,lose,-20.50,129.50",
+		"total,,,game,win,0.00,",
+		"total,,,game,lose,-20.50,",
+		"total,,,payment,win,50.00,",
+		"total,,,payment,lose,0.00,",
+		"closing,,,,,,129.50",
+		"",
+	}, "\n")
+	assert.Equal(t, expected, writeSample(t, FormatCSV))
+}
+
+func TestJSONWriter(t *testing.T) {
+	output := writeSample(t, FormatJSON)
+	require.True(t, json.Valid([]byte(output)), output)
+
+	var decoded struct {
+		UserID         uint64 `json:"userId"`
+		OpeningBalance string `json:"openingBalance"`
+		Transactions   []struct {
+			TransactionID string `json:"transactionId"`
+			Amount        string `json:"amount"`
+			Balance       string `json:"balance"`
+		} `json:"transactions"`
+		Totals []struct {
+			SourceType string `json:"sourceType"`
+			Net        string `json:"net"`
+		} `json:"totals"`
+		ClosingBalance string `json:"closingBalance"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output), &decoded))
+
+	assert.Equal(t, uint64(7), decoded.UserID)
+	assert.Equal(t, "100.00", decoded.OpeningBalance)
+	assert.Len(t, decoded.Transactions, 2)
+	assert.Equal(t, "-20.50", decoded.Transactions[1].Amount)
+	assert.Equal(t, "129.50", decoded.Transactions[1].Balance)
+	assert.Equal(t, "-20.50", decoded.Totals[0].Net)
+	assert.Equal(t, "129.50", decoded.ClosingBalance)
+}
+
+func TestJSONWriterWithoutTransactions(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatJSON, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.WriteHeader(Header{UserID: 1}))
+	require.NoError(t, writer.WriteSummary(Summary{}))
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, []interface{}{}, decoded["transactions"])
+	assert.Equal(t, []interface{}{}, decoded["totals"])
+}
+
+func TestHTMLWriter(t *testing.T) {
+	output := writeSample(t, FormatHTML)
+
+	assert.Contains(t, output, "@page { size: A4")
+	assert.Contains(t, output, "&lt;tx-2&gt;", "transaction IDs are escaped")
+	assert.Contains(t, output, "<td class=\"num\">-20.50</td>")
+	assert.Contains(t, output, "Closing balance: 129.50")
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(output), "</html>"))
+}
+
+func TestNewWriterRejectsUnknownFormat(t *testing.T) {
+	_, err := NewWriter("pdf", &bytes.Buffer{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,171 @@
+package stream
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+
+	"github.com/lielamurs/balance-transactions/internal/config"
+)
+
+// Message is one Server-Sent Event. An ID of zero is sent without an id
+// field, so it does not move the client's Last-Event-ID.
+type Message struct {
+	ID    uint64
+	Event string
+	Data  []byte
+}
+
+// Encode renders the message in the text/event-stream wire format.
+func (m Message) Encode() []byte {
+	var buf bytes.Buffer
+	if m.ID != 0 {
+		buf.WriteString("id: " + strconv.FormatUint(m.ID, 10) + "\n")
+	}
+	if m.Event != "" {
+		buf.WriteString("event: " + m.Event + "\n")
+	}
+	for _, line := range bytes.Split(m.Data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+	return buf.Bytes()
+}
+
+// Hub fans messages out to the subscribers of each user and keeps the most
+// recent messages per user so a reconnecting client can resume from its
+// Last-Event-ID. Message IDs must increase per user.
+type Hub struct {
+	mu             sync.Mutex
+	bufferSize     int
+	subscriberSize int
+	users          map[uint64]*userStream
+}
+
+type userStream struct {
+	recent      []Message
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives the messages published for one user. Its channel is
+// closed when the subscription is closed or when the subscriber falls so far
+// behind that its buffer fills; the client is expected to reconnect.
+type Subscription struct {
+	hub    *Hub
+	userID uint64
+	ch     chan Message
+	closed bool
+}
+
+var (
+	defaultHub  *Hub
+	defaultOnce sync.Once
+)
+
+// Default returns the process-wide hub shared by the services and handlers.
+func Default() *Hub {
+	defaultOnce.Do(func() {
+		cfg := config.Get()
+		defaultHub = NewHub(cfg.StreamBufferSize, cfg.StreamSubscriberBuffer)
+	})
+	return defaultHub
+}
+
+func NewHub(bufferSize, subscriberSize int) *Hub {
+	return &Hub{
+		bufferSize:     bufferSize,
+		subscriberSize: subscriberSize,
+		users:          map[uint64]*userStream{},
+	}
+}
+
+// Publish delivers a message to every current subscriber of the user and
+// appends it to the user's replay buffer. It never blocks on a subscriber.
+func (h *Hub) Publish(userID uint64, message Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stream := h.stream(userID)
+	stream.recent = append(stream.recent, message)
+	if len(stream.recent) > h.bufferSize {
+		stream.recent = stream.recent[len(stream.recent)-h.bufferSize:]
+	}
+
+	for subscription := range stream.subscribers {
+		select {
+		case subscription.ch <- message:
+		default:
+			h.remove(subscription)
+		}
+	}
+}
+
+// Subscribe registers a subscriber for the user. When lastEventID is non-zero
+// it also returns the buffered messages published after it; resumed is false
+// when that ID is no longer buffered and the caller has to resynchronise the
+// client some other way.
+func (h *Hub) Subscribe(userID, lastEventID uint64) (subscription *Subscription, replay []Message, resumed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stream := h.stream(userID)
+	if lastEventID != 0 {
+		for i, message := range stream.recent {
+			if message.ID == lastEventID {
+				replay = append(replay, stream.recent[i+1:]...)
+				resumed = true
+				break
+			}
+		}
+	}
+
+	subscription = &Subscription{
+		hub:    h,
+		userID: userID,
+		ch:     make(chan Message, h.subscriberSize),
+	}
+	stream.subscribers[subscription] = struct{}{}
+	return subscription, replay, resumed
+}
+
+// Subscribers returns the number of open subscriptions for the user.
+func (h *Hub) Subscribers(userID uint64) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if stream, ok := h.users[userID]; ok {
+		return len(stream.subscribers)
+	}
+	return 0
+}
+
+func (h *Hub) stream(userID uint64) *userStream {
+	stream, ok := h.users[userID]
+	if !ok {
+		stream = &userStream{subscribers: map[*Subscription]struct{}{}}
+		h.users[userID] = stream
+	}
+	return stream
+}
+
+func (h *Hub) remove(subscription *Subscription) {
+	if subscription.closed {
+		return
+	}
+	subscription.closed = true
+	close(subscription.ch)
+	delete(h.users[subscription.userID].subscribers, subscription)
+}
+
+func (s *Subscription) C() <-chan Message {
+	return s.ch
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
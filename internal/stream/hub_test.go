@@ -0,0 +1,92 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  Message
+		expected string
+	}{
+		{
+			name:     "with id and event",
+			message:  Message{ID: 42, Event: "balance", Data: []byte(`{"balance":"10.00"}`)},
+			expected: "id: 42\nevent: balance\ndata: {\"balance\":\"10.00\"}\n\n",
+		},
+		{
+			name:     "snapshot without id",
+			message:  Message{Event: "balance", Data: []byte(`{}`)},
+			expected: "event: balance\ndata: {}\n\n",
+		},
+		{
+			name:     "multi-line data",
+			message:  Message{ID: 1, Data: []byte("first\nsecond")},
+			expected: "id: 1\ndata: first\ndata: second\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, string(tt.message.Encode()))
+		})
+	}
+}
+
+func TestHubFansOutPerUser(t *testing.T) {
+	hub := NewHub(10, 4)
+	first, _, _ := hub.Subscribe(1, 0)
+	second, _, _ := hub.Subscribe(1, 0)
+	other, _, _ := hub.Subscribe(2, 0)
+
+	hub.Publish(1, Message{ID: 5, Event: "balance"})
+
+	assert.Equal(t, uint64(5), (<-first.C()).ID)
+	assert.Equal(t, uint64(5), (<-second.C()).ID)
+	assert.Len(t, other.C(), 0)
+	assert.Equal(t, 2, hub.Subscribers(1))
+
+	first.Close()
+	first.Close()
+	assert.Equal(t, 1, hub.Subscribers(1))
+	_, open := <-first.C()
+	assert.False(t, open)
+}
+
+func TestHubResumesFromLastEventID(t *testing.T) {
+	hub := NewHub(3, 4)
+	for _, id := range []uint64{10, 11, 12, 13} {
+		hub.Publish(1, Message{ID: id})
+	}
+
+	_, replay, resumed := hub.Subscribe(1, 11)
+	assert.True(t, resumed)
+	assert.Equal(t, []Message{{ID: 12}, {ID: 13}}, replay)
+
+	_, replay, resumed = hub.Subscribe(1, 13)
+	assert.True(t, resumed)
+	assert.Empty(t, replay)
+
+	_, replay, resumed = hub.Subscribe(1, 10)
+	assert.False(t, resumed, "evicted IDs cannot be resumed")
+	assert.Empty(t, replay)
+
+	_, _, resumed = hub.Subscribe(1, 0)
+	assert.False(t, resumed)
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	hub := NewHub(10, 1)
+	slow, _, _ := hub.Subscribe(1, 0)
+
+	hub.Publish(1, Message{ID: 1})
+	hub.Publish(1, Message{ID: 2})
+
+	assert.Equal(t, 0, hub.Subscribers(1))
+	assert.Equal(t, uint64(1), (<-slow.C()).ID)
+	_, open := <-slow.C()
+	assert.False(t, open)
+}
@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+const claimLease = time.Minute
+
+// Store is the part of the webhook repository the dispatcher depends on.
+type Store interface {
+	ClaimDeliveries(limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+	GetSubscription(subscriptionID uint64) (*model.WebhookSubscription, error)
+	MarkDelivered(deliveryID uint64, statusCode int) error
+	MarkFailed(deliveryID uint64, attempts int, statusCode *int, lastError string, nextAttemptAt time.Time, dead bool) error
+}
+
+// Dispatcher sends pending deliveries to subscribers. Non-2xx responses and
+// transport errors are retried with exponential backoff until maxAttempts,
+// after which the delivery is moved to the dead-letter state.
+type Dispatcher struct {
+	store        Store
+	client       *http.Client
+	batchSize    int
+	pollInterval time.Duration
+	retryBase    time.Duration
+	maxAttempts  int
+	now          func() time.Time
+}
+
+func NewDispatcher(store Store, client *http.Client, batchSize int, pollInterval, retryBase time.Duration, maxAttempts int) *Dispatcher {
+	return &Dispatcher{
+		store:        store,
+		client:       client,
+		batchSize:    batchSize,
+		pollInterval: pollInterval,
+		retryBase:    retryBase,
+		maxAttempts:  maxAttempts,
+		now:          func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	logrus.WithFields(logrus.Fields{"batchSize": d.batchSize, "maxAttempts": d.maxAttempts}).Info("Webhook dispatcher started")
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.ProcessBatch(ctx); err != nil {
+			logrus.WithField("error", err).Error("Failed to process webhook deliveries")
+		}
+
+		select {
+		case <-ctx.Done():
+			logrus.Info("Webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch sends one batch of due deliveries and returns how many
+// succeeded.
+func (d *Dispatcher) ProcessBatch(ctx context.Context) (int, error) {
+	deliveries, err := d.store.ClaimDeliveries(d.batchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		subscription, err := d.store.GetSubscription(delivery.SubscriptionID)
+		if err != nil {
+			return delivered, err
+		}
+
+		statusCode, sendErr := d.send(ctx, subscription, delivery)
+		if sendErr == nil {
+			if err := d.store.MarkDelivered(delivery.ID, statusCode); err != nil {
+				return delivered, err
+			}
+			delivered++
+			continue
+		}
+
+		var code *int
+		if statusCode != 0 {
+			code = &statusCode
+		}
+		attempts := delivery.Attempts + 1
+		dead := attempts >= d.maxAttempts || !subscription.Active
+		nextAttemptAt := d.now().Add(retryDelay(d.retryBase, attempts))
+
+		logrus.WithFields(logrus.Fields{
+			"deliveryID":     delivery.ID,
+			"subscriptionID": subscription.ID,
+			"eventType":      delivery.EventType,
+			"attempts":       attempts,
+			"dead":           dead,
+			"error":          sendErr,
+		}).Warn("Webhook delivery failed")
+
+		if err := d.store.MarkFailed(delivery.ID, attempts, code, sendErr.Error(), nextAttemptAt, dead); err != nil {
+			return delivered, err
+		}
+	}
+
+	return delivered, nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, subscription *model.WebhookSubscription, delivery model.WebhookDelivery) (int, error) {
+	if !subscription.Active {
+		return 0, fmt.Errorf("subscription %d is inactive", subscription.ID)
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(delivery.ID, 10))
+	req.Header.Set("X-Webhook-Dedupe-Key", delivery.DedupeKey)
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, d.now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func retryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= time.Hour {
+			return time.Hour
+		}
+	}
+	return delay
+}
@@ -0,0 +1,150 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	deliveries    []model.WebhookDelivery
+	subscriptions map[uint64]*model.WebhookSubscription
+	delivered     map[uint64]int
+	failed        map[uint64]failure
+}
+
+type failure struct {
+	attempts      int
+	statusCode    *int
+	nextAttemptAt time.Time
+	dead          bool
+}
+
+func newFakeStore(subscriptions ...*model.WebhookSubscription) *fakeStore {
+	store := &fakeStore{
+		subscriptions: map[uint64]*model.WebhookSubscription{},
+		delivered:     map[uint64]int{},
+		failed:        map[uint64]failure{},
+	}
+	for _, subscription := range subscriptions {
+		store.subscriptions[subscription.ID] = subscription
+	}
+	return store
+}
+
+func (s *fakeStore) ClaimDeliveries(limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	deliveries := s.deliveries
+	s.deliveries = nil
+	return deliveries, nil
+}
+
+func (s *fakeStore) GetSubscription(subscriptionID uint64) (*model.WebhookSubscription, error) {
+	return s.subscriptions[subscriptionID], nil
+}
+
+func (s *fakeStore) MarkDelivered(deliveryID uint64, statusCode int) error {
+	s.delivered[deliveryID] = statusCode
+	return nil
+}
+
+func (s *fakeStore) MarkFailed(deliveryID uint64, attempts int, statusCode *int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	s.failed[deliveryID] = failure{attempts: attempts, statusCode: statusCode, nextAttemptAt: nextAttemptAt, dead: dead}
+	return nil
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func TestDispatcherDeliversSignedPayload(t *testing.T) {
+	now := time.Now().UTC()
+	var mu sync.Mutex
+	var received []receivedRequest
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, receivedRequest{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	store := newFakeStore(&model.WebhookSubscription{ID: 1, URL: receiver.URL, Secret: "secret", Active: true})
+	store.deliveries = []model.WebhookDelivery{{
+		ID:             5,
+		SubscriptionID: 1,
+		EventType:      "transaction.processed",
+		DedupeKey:      "transaction.processed:tx-1",
+		Payload:        `{"type":"transaction.processed"}`,
+	}}
+
+	dispatcher := NewDispatcher(store, receiver.Client(), 10, time.Second, time.Second, 3)
+	dispatcher.now = func() time.Time { return now }
+
+	delivered, err := dispatcher.ProcessBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, http.StatusNoContent, store.delivered[5])
+
+	assert.Len(t, received, 1)
+	request := received[0]
+	assert.Equal(t, `{"type":"transaction.processed"}`, string(request.body))
+	assert.Equal(t, "transaction.processed", request.header.Get("X-Webhook-Event"))
+	assert.Equal(t, "5", request.header.Get("X-Webhook-Delivery"))
+	assert.Equal(t, "transaction.processed:tx-1", request.header.Get("X-Webhook-Dedupe-Key"))
+	assert.True(t, Verify("secret", request.header.Get(SignatureHeader), request.body, time.Minute, now))
+}
+
+func TestDispatcherRetriesAndDeadLetters(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	store := newFakeStore(
+		&model.WebhookSubscription{ID: 1, URL: receiver.URL, Secret: "secret", Active: true},
+		&model.WebhookSubscription{ID: 2, URL: receiver.URL, Secret: "secret", Active: false},
+	)
+	store.deliveries = []model.WebhookDelivery{
+		{ID: 1, SubscriptionID: 1, Payload: `{}`, Attempts: 0},
+		{ID: 2, SubscriptionID: 1, Payload: `{}`, Attempts: 2},
+		{ID: 3, SubscriptionID: 2, Payload: `{}`, Attempts: 0},
+	}
+
+	dispatcher := NewDispatcher(store, receiver.Client(), 10, time.Second, 5*time.Second, 3)
+	dispatcher.now = func() time.Time { return now }
+
+	delivered, err := dispatcher.ProcessBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+
+	first := store.failed[1]
+	assert.Equal(t, 1, first.attempts)
+	assert.False(t, first.dead)
+	assert.Equal(t, http.StatusServiceUnavailable, *first.statusCode)
+	assert.Equal(t, now.Add(5*time.Second), first.nextAttemptAt)
+
+	last := store.failed[2]
+	assert.Equal(t, 3, last.attempts)
+	assert.True(t, last.dead)
+
+	inactive := store.failed[3]
+	assert.True(t, inactive.dead)
+	assert.Nil(t, inactive.statusCode)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 5*time.Second, retryDelay(5*time.Second, 1))
+	assert.Equal(t, 10*time.Second, retryDelay(5*time.Second, 2))
+	assert.Equal(t, 40*time.Second, retryDelay(5*time.Second, 4))
+	assert.Equal(t, time.Hour, retryDelay(5*time.Second, 20))
+}
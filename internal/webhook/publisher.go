@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/outbox"
+)
+
+// SubscriptionStore is the part of the webhook repository the publisher
+// depends on.
+type SubscriptionStore interface {
+	GetActiveSubscriptions() ([]model.WebhookSubscription, error)
+	CreateDelivery(delivery *model.WebhookDelivery) error
+}
+
+// Publisher is an outbox publisher that fans each event out into one
+// delivery per matching subscription. The dispatcher sends them later.
+type Publisher struct {
+	store SubscriptionStore
+}
+
+func NewPublisher(store SubscriptionStore) *Publisher {
+	return &Publisher{store: store}
+}
+
+func (p *Publisher) Publish(ctx context.Context, event outbox.Event) error {
+	subscriptions, err := p.store.GetActiveSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	for _, subscription := range subscriptions {
+		if !Subscribes(subscription, event.Type) {
+			continue
+		}
+
+		if body == nil {
+			if body, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+
+		delivery := &model.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			DedupeKey:      event.DedupeKey,
+			Payload:        string(body),
+			Status:         model.DeliveryPending,
+			NextAttemptAt:  time.Now().UTC(),
+		}
+		if err := p.store.CreateDelivery(delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Subscribes reports whether a subscription wants an event type. The event
+// type list is comma-separated and "*" matches every event.
+func Subscribes(subscription model.WebhookSubscription, eventType string) bool {
+	for _, subscribed := range strings.Split(subscription.EventTypes, ",") {
+		if subscribed == "*" || subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
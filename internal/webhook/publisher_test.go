@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/lielamurs/balance-transactions/internal/model"
+	"github.com/lielamurs/balance-transactions/internal/outbox"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSubscriptionStore struct {
+	subscriptions []model.WebhookSubscription
+	deliveries    []model.WebhookDelivery
+}
+
+func (s *fakeSubscriptionStore) GetActiveSubscriptions() ([]model.WebhookSubscription, error) {
+	return s.subscriptions, nil
+}
+
+func (s *fakeSubscriptionStore) CreateDelivery(delivery *model.WebhookDelivery) error {
+	s.deliveries = append(s.deliveries, *delivery)
+	return nil
+}
+
+func TestSubscribes(t *testing.T) {
+	subscription := model.WebhookSubscription{EventTypes: "transaction.processed,transaction.rolled_back"}
+	assert.True(t, Subscribes(subscription, "transaction.processed"))
+	assert.True(t, Subscribes(subscription, "transaction.rolled_back"))
+	assert.False(t, Subscribes(subscription, "balance.changed"))
+	assert.True(t, Subscribes(model.WebhookSubscription{EventTypes: "*"}, "balance.changed"))
+}
+
+func TestPublisherFansOutToMatchingSubscriptions(t *testing.T) {
+	store := &fakeSubscriptionStore{
+		subscriptions: []model.WebhookSubscription{
+			{ID: 1, EventTypes: "transaction.processed"},
+			{ID: 2, EventTypes: "balance.changed"},
+			{ID: 3, EventTypes: "*"},
+		},
+	}
+	event := outbox.Event{
+		ID:          9,
+		Type:        "transaction.processed",
+		DedupeKey:   "transaction.processed:tx-1",
+		AggregateID: 1,
+		Payload:     json.RawMessage(`{"transactionId":"tx-1"}`),
+	}
+
+	err := NewPublisher(store).Publish(context.Background(), event)
+	assert.NoError(t, err)
+	assert.Len(t, store.deliveries, 2)
+	assert.Equal(t, uint64(1), store.deliveries[0].SubscriptionID)
+	assert.Equal(t, uint64(3), store.deliveries[1].SubscriptionID)
+	for _, delivery := range store.deliveries {
+		assert.Equal(t, uint64(9), delivery.EventID)
+		assert.Equal(t, "transaction.processed:tx-1", delivery.DedupeKey)
+		assert.Equal(t, model.DeliveryPending, delivery.Status)
+
+		var envelope outbox.Event
+		assert.NoError(t, json.Unmarshal([]byte(delivery.Payload), &envelope))
+		assert.Equal(t, event.DedupeKey, envelope.DedupeKey)
+	}
+}
@@ -0,0 +1,22 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"type":"transaction.processed"}`)
+	header := Sign("secret", now, body)
+
+	assert.True(t, Verify("secret", header, body, 5*time.Minute, now))
+	assert.True(t, Verify("secret", header, body, 5*time.Minute, now.Add(4*time.Minute)))
+	assert.False(t, Verify("other-secret", header, body, 5*time.Minute, now))
+	assert.False(t, Verify("secret", header, []byte(`{"type":"tampered"}`), 5*time.Minute, now))
+	assert.False(t, Verify("secret", header, body, 5*time.Minute, now.Add(6*time.Minute)))
+	assert.False(t, Verify("secret", "garbage", body, 5*time.Minute, now))
+	assert.False(t, Verify("secret", "t=abc,v1=00", body, 5*time.Minute, now))
+}